@@ -41,6 +41,34 @@ import (
 func main() {
 	args := os.Args[1:]
 
+	if len(args) > 0 && args[0] == "cache" {
+		if err := cli.RunCache(args[1:], os.Stdout); err != nil {
+			runner.ExitWithError(err)
+		}
+		return
+	}
+
+	if len(args) > 0 && args[0] == "report" {
+		if err := cli.RunReport(args[1:], os.Stdout); err != nil {
+			runner.ExitWithError(err)
+		}
+		return
+	}
+
+	if len(args) > 0 && args[0] == "compare" {
+		if err := cli.RunCompare(args[1:], os.Stdout); err != nil {
+			runner.ExitWithError(err)
+		}
+		return
+	}
+
+	if len(args) > 0 && args[0] == "tui" {
+		if err := cli.RunTUI(args[1:], os.Stdout); err != nil {
+			runner.ExitWithError(err)
+		}
+		return
+	}
+
 	flags, err := cli.Parse(args)
 	if err != nil {
 		runner.ExitWithError(err)
@@ -0,0 +1,91 @@
+// Package operators embeds the operator documentation in this directory so
+// reports can carry it. Each operator is documented under a second-level
+// heading named after it.
+package operators
+
+import (
+	"bufio"
+	"embed"
+	"io/fs"
+	"strings"
+	"sync"
+)
+
+//go:embed *.md
+var files embed.FS
+
+// Doc is the documentation of one operator.
+type Doc struct {
+	Name        string
+	Page        string // file name of the page that documents the operator
+	Summary     string // first paragraph of the section
+	Description string // prose of the section, without code blocks
+	Help        string // the whole section body, as markdown
+}
+
+var (
+	docsOnce sync.Once
+	docs     map[string]Doc
+)
+
+// Lookup returns the documentation of the named operator.
+func Lookup(name string) (Doc, bool) {
+	docsOnce.Do(func() {
+		docs = make(map[string]Doc)
+		pages, _ := fs.Glob(files, "*.md")
+		for _, page := range pages {
+			data, err := files.ReadFile(page)
+			if err != nil {
+				continue
+			}
+			for _, d := range parsePage(page, string(data)) {
+				docs[d.Name] = d
+			}
+		}
+	})
+	d, ok := docs[name]
+	return d, ok
+}
+
+// parsePage splits a page into its "## name" sections, skipping headings
+// inside code fences.
+func parsePage(page, text string) []Doc {
+	var out []Doc
+	var cur *Doc
+	var body, prose strings.Builder
+	flush := func() {
+		if cur == nil {
+			return
+		}
+		cur.Help = strings.TrimSpace(body.String())
+		cur.Summary, _, _ = strings.Cut(cur.Help, "\n\n")
+		cur.Summary = strings.Join(strings.Fields(cur.Summary), " ")
+		cur.Description = strings.Join(strings.Fields(prose.String()), " ")
+		out = append(out, *cur)
+		body.Reset()
+		prose.Reset()
+	}
+	inFence := false
+	sc := bufio.NewScanner(strings.NewReader(text))
+	for sc.Scan() {
+		line := sc.Text()
+		fence := strings.HasPrefix(line, "```")
+		if fence {
+			inFence = !inFence
+		}
+		if !inFence && strings.HasPrefix(line, "## ") {
+			flush()
+			cur = &Doc{Name: strings.TrimSpace(strings.TrimPrefix(line, "## ")), Page: page}
+			continue
+		}
+		if cur == nil {
+			continue
+		}
+		body.WriteString(line + "\n")
+		if !inFence && !fence {
+			prose.WriteString(line + "\n")
+		}
+	}
+	flush()
+	return out
+}
@@ -0,0 +1,58 @@
+package operators
+
+import (
+	"testing"
+
+	"github.com/aclfe/gorgon/pkg/mutator"
+	_ "github.com/aclfe/gorgon/pkg/mutator/operators/arithmetic_flip"
+	_ "github.com/aclfe/gorgon/pkg/mutator/operators/assignment_operator"
+	_ "github.com/aclfe/gorgon/pkg/mutator/operators/boundary_value"
+	_ "github.com/aclfe/gorgon/pkg/mutator/operators/concurrency"
+	_ "github.com/aclfe/gorgon/pkg/mutator/operators/condition_negation"
+	_ "github.com/aclfe/gorgon/pkg/mutator/operators/conditional_expression"
+	_ "github.com/aclfe/gorgon/pkg/mutator/operators/constant_replacement"
+	_ "github.com/aclfe/gorgon/pkg/mutator/operators/defer_panic_recover"
+	_ "github.com/aclfe/gorgon/pkg/mutator/operators/defer_removal"
+	_ "github.com/aclfe/gorgon/pkg/mutator/operators/early_return_removal"
+	_ "github.com/aclfe/gorgon/pkg/mutator/operators/empty_body"
+	_ "github.com/aclfe/gorgon/pkg/mutator/operators/error_handling"
+	_ "github.com/aclfe/gorgon/pkg/mutator/operators/function_call_removal"
+	_ "github.com/aclfe/gorgon/pkg/mutator/operators/inc_dec_flip"
+	_ "github.com/aclfe/gorgon/pkg/mutator/operators/logical_operator"
+	_ "github.com/aclfe/gorgon/pkg/mutator/operators/loop_body_removal"
+	_ "github.com/aclfe/gorgon/pkg/mutator/operators/loop_break_first"
+	_ "github.com/aclfe/gorgon/pkg/mutator/operators/loop_break_removal"
+	_ "github.com/aclfe/gorgon/pkg/mutator/operators/math_operators"
+	_ "github.com/aclfe/gorgon/pkg/mutator/operators/negate_condition"
+	_ "github.com/aclfe/gorgon/pkg/mutator/operators/reference_returns"
+	_ "github.com/aclfe/gorgon/pkg/mutator/operators/sign_toggle"
+	_ "github.com/aclfe/gorgon/pkg/mutator/operators/switch_mutations"
+	_ "github.com/aclfe/gorgon/pkg/mutator/operators/variable_replacement"
+	_ "github.com/aclfe/gorgon/pkg/mutator/operators/zero_value_return"
+)
+
+func TestEveryOperatorIsDocumented(t *testing.T) {
+	for _, op := range mutator.ListAll() {
+		d, ok := Lookup(op.Name())
+		if !ok {
+			t.Errorf("operator %s has no section in docs/documentation/operators", op.Name())
+			continue
+		}
+		if d.Summary == "" || d.Help == "" {
+			t.Errorf("operator %s has an empty section in %s", op.Name(), d.Page)
+		}
+	}
+}
+
+func TestParsePageSkipsFencedHeadings(t *testing.T) {
+	docs := parsePage("x.md", "# X\n\nIntro.\n\n## a\n\nDoes\nthings.\n\n```\n## not a heading\n```\n\n## b\n\nOther.\n")
+	if len(docs) != 2 || docs[0].Name != "a" || docs[1].Name != "b" {
+		t.Fatalf("unexpected sections: %+v", docs)
+	}
+	if docs[0].Summary != "Does things." {
+		t.Errorf("summary = %q", docs[0].Summary)
+	}
+	if docs[0].Description != "Does things." {
+		t.Errorf("description = %q", docs[0].Description)
+	}
+}
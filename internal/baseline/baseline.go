@@ -5,18 +5,43 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"time"
 )
 
 const DefaultFile = ".gorgon-baseline.json"
 
+// Baseline modes. A score baseline ratchets the mutation score; a mutants
+// baseline records every survivor and fails on any survivor it doesn't know.
+const (
+	ModeScore   = "score"
+	ModeMutants = "mutants"
+)
+
 type Data struct {
-	Score     float64 `json:"score"`
-	Killed    int     `json:"killed"`
-	Survived  int     `json:"survived"`
-	Untested  int     `json:"untested"`
-	Total     int     `json:"total"`
-	Timestamp string  `json:"timestamp"`
+	Mode      string     `json:"mode,omitempty"`
+	Score     float64    `json:"score"`
+	Killed    int        `json:"killed"`
+	Survived  int        `json:"survived"`
+	Untested  int        `json:"untested"`
+	Total     int        `json:"total"`
+	Timestamp string     `json:"timestamp"`
+	Survivors []Survivor `json:"survivors,omitempty"`
+}
+
+// Survivor identifies a surviving mutant by fingerprint. File, line and
+// operator are informational: they make the baseline readable and let a run
+// tell which survivors it was in a position to re-check.
+type Survivor struct {
+	Fingerprint string `json:"fingerprint"`
+	File        string `json:"file"`
+	Line        int    `json:"line"`
+	Column      int    `json:"column,omitempty"`
+	Operator    string `json:"operator"`
+}
+
+func (s Survivor) String() string {
+	return fmt.Sprintf("%s:%d:%d %s [%s]", s.File, s.Line, s.Column, s.Operator, s.Fingerprint)
 }
 
 func Load(dir, file string) (*Data, error) {
@@ -57,6 +82,67 @@ func CheckRegression(current, base *Data, tolerance float64) error {
 	return nil
 }
 
+// SurvivorDiff is the result of comparing a run's survivors to a mutants
+// baseline.
+type SurvivorDiff struct {
+	// New lists survivors that are not in the baseline.
+	New []Survivor
+	// Fixed lists baseline survivors in files covered by the run that no
+	// longer survive: they were killed, or the code they mutated is gone.
+	Fixed []Survivor
+}
+
+// DiffSurvivors compares current's survivors with base's. Baseline entries in
+// files the run did not mutate are neither fixed nor new, so checking a
+// single package against a whole-project baseline works; covered lists the
+// files the run mutated.
+func DiffSurvivors(current, base *Data, covered map[string]bool) SurvivorDiff {
+	known := make(map[string]bool, len(base.Survivors))
+	for _, s := range base.Survivors {
+		known[s.Fingerprint] = true
+	}
+	alive := make(map[string]bool, len(current.Survivors))
+	var d SurvivorDiff
+	for _, s := range current.Survivors {
+		alive[s.Fingerprint] = true
+		if !known[s.Fingerprint] {
+			d.New = append(d.New, s)
+		}
+	}
+	for _, s := range base.Survivors {
+		if !alive[s.Fingerprint] && covered[s.File] {
+			d.Fixed = append(d.Fixed, s)
+		}
+	}
+	sortSurvivors(d.New)
+	sortSurvivors(d.Fixed)
+	return d
+}
+
+// CheckSurvivors returns an error if the run has survivors that are not in
+// the baseline.
+func CheckSurvivors(d SurvivorDiff) error {
+	if len(d.New) > 0 {
+		return fmt.Errorf("%d surviving mutant(s) not in baseline", len(d.New))
+	}
+	return nil
+}
+
+func sortSurvivors(s []Survivor) {
+	sort.Slice(s, func(i, j int) bool {
+		if s[i].File != s[j].File {
+			return s[i].File < s[j].File
+		}
+		if s[i].Line != s[j].Line {
+			return s[i].Line < s[j].Line
+		}
+		if s[i].Column != s[j].Column {
+			return s[i].Column < s[j].Column
+		}
+		return s[i].Fingerprint < s[j].Fingerprint
+	})
+}
+
 func resolvePath(dir, file string) string {
 	if file != "" {
 		if filepath.IsAbs(file) {
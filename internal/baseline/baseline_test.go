@@ -0,0 +1,35 @@
+//go:build unit
+// +build unit
+
+package baseline
+
+import "testing"
+
+// TestDiffSurvivors verifies that a mutants baseline flags survivors it
+// doesn't know, reports known survivors that are gone as fixed, and leaves
+// entries for files outside the run alone.
+func TestDiffSurvivors(t *testing.T) {
+	base := &Data{Mode: ModeMutants, Survivors: []Survivor{
+		{Fingerprint: "aaaa", File: "calc/calc.go", Line: 3},
+		{Fingerprint: "bbbb", File: "calc/calc.go", Line: 5},
+		{Fingerprint: "cccc", File: "other/other.go", Line: 7},
+	}}
+	current := &Data{Survivors: []Survivor{
+		{Fingerprint: "aaaa", File: "calc/calc.go", Line: 4},
+		{Fingerprint: "dddd", File: "calc/calc.go", Line: 9},
+	}}
+
+	diff := DiffSurvivors(current, base, map[string]bool{"calc/calc.go": true})
+	if len(diff.New) != 1 || diff.New[0].Fingerprint != "dddd" {
+		t.Errorf("expected dddd as the only new survivor, got %+v", diff.New)
+	}
+	if len(diff.Fixed) != 1 || diff.Fixed[0].Fingerprint != "bbbb" {
+		t.Errorf("expected bbbb as the only fixed survivor, got %+v", diff.Fixed)
+	}
+	if err := CheckSurvivors(diff); err == nil {
+		t.Error("expected new survivors to fail the check")
+	}
+	if err := CheckSurvivors(SurvivorDiff{Fixed: diff.Fixed}); err != nil {
+		t.Errorf("fixed survivors alone should not fail: %v", err)
+	}
+}
@@ -0,0 +1,83 @@
+package cache
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"time"
+)
+
+// archiveEntryName is the single file inside an exported cache tarball.
+const archiveEntryName = "gorgon-cache.json"
+
+// Export writes every entry of src as a gzipped tarball, suitable for
+// storing as a CI artifact and restoring with Import.
+func Export(src Enumerator, w io.Writer) (int, error) {
+	entries, err := src.All()
+	if err != nil {
+		return 0, err
+	}
+	data, err := encode(entries)
+	if err != nil {
+		return 0, err
+	}
+
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+	hdr := &tar.Header{
+		Name:    archiveEntryName,
+		Mode:    0o644,
+		Size:    int64(len(data)),
+		ModTime: time.Now(),
+	}
+	if err := tw.WriteHeader(hdr); err != nil {
+		return 0, fmt.Errorf("failed to write archive: %w", err)
+	}
+	if _, err := tw.Write(data); err != nil {
+		return 0, fmt.Errorf("failed to write archive: %w", err)
+	}
+	if err := tw.Close(); err != nil {
+		return 0, fmt.Errorf("failed to write archive: %w", err)
+	}
+	if err := gz.Close(); err != nil {
+		return 0, fmt.Errorf("failed to write archive: %w", err)
+	}
+	return len(entries), nil
+}
+
+// Import merges the entries of a tarball written by Export into dst.
+// Archives from older gorgon versions are migrated like local files.
+func Import(r io.Reader, dst Backend) (int, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read archive: %w", err)
+	}
+	defer gz.Close()
+
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return 0, fmt.Errorf("archive has no %s", archiveEntryName)
+		}
+		if err != nil {
+			return 0, fmt.Errorf("failed to read archive: %w", err)
+		}
+		if hdr.Name != archiveEntryName {
+			continue
+		}
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			return 0, fmt.Errorf("failed to read archive: %w", err)
+		}
+		c, err := decode(data)
+		if err != nil {
+			return 0, err
+		}
+		if err := dst.Store(c.Entries); err != nil {
+			return 0, err
+		}
+		return len(c.Entries), nil
+	}
+}
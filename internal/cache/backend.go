@@ -0,0 +1,276 @@
+package cache
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	resultsDirName = "results"
+	lockTimeout    = 30 * time.Second
+	lockRetry      = 20 * time.Millisecond
+)
+
+// Backend persists cache entries. Keys are content-derived (see Key), so an
+// entry never changes meaning once written; concurrent writers storing the
+// same key store the same result.
+type Backend interface {
+	// Fetch returns the stored entries for keys. Unknown keys are omitted.
+	Fetch(keys []string) (map[string]Entry, error)
+	// Store persists entries alongside whatever other writers stored.
+	Store(entries map[string]Entry) error
+	// Location describes where entries live, for log output.
+	Location() string
+}
+
+// Enumerator is implemented by backends that can list every entry cheaply.
+type Enumerator interface {
+	All() (map[string]Entry, error)
+}
+
+// NewBackend resolves a cache_backend setting. An empty spec selects the
+// project's local cache file; an http(s) URL selects a remote server and a
+// path selects a shared directory. Remote and shared backends are layered
+// over the local one so results are always available offline.
+func NewBackend(spec, projectDir string) (Backend, error) {
+	local, err := NewLocalBackend(projectDir)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case spec == "":
+		return local, nil
+	case strings.HasPrefix(spec, "http://") || strings.HasPrefix(spec, "https://"):
+		return &tieredBackend{local: local, shared: NewHTTPBackend(spec)}, nil
+	default:
+		dir, err := NewDirBackend(spec)
+		if err != nil {
+			return nil, err
+		}
+		return &tieredBackend{local: local, shared: dir}, nil
+	}
+}
+
+// DirBackend stores each entry as its own file, named by key, under a
+// directory that may be shared between machines (e.g. a network mount).
+// Writes go through a temp file and rename, so concurrent writers and
+// readers never observe a partial entry.
+type DirBackend struct {
+	dir string
+}
+
+// NewDirBackend returns a content-addressed entry store rooted at dir.
+func NewDirBackend(dir string) (*DirBackend, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create cache dir: %w", err)
+	}
+	return &DirBackend{dir: dir}, nil
+}
+
+func (b *DirBackend) Location() string { return b.dir }
+
+func (b *DirBackend) path(key string) string {
+	return filepath.Join(b.dir, key[:2], key+".json")
+}
+
+// Get returns a single entry.
+func (b *DirBackend) Get(key string) (Entry, bool, error) {
+	if !ValidKey(key) {
+		return Entry{}, false, fmt.Errorf("invalid cache key %q", key)
+	}
+	data, err := os.ReadFile(b.path(key))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return Entry{}, false, nil
+		}
+		return Entry{}, false, fmt.Errorf("failed to read cache entry: %w", err)
+	}
+	var e Entry
+	if err := json.Unmarshal(data, &e); err != nil {
+		return Entry{}, false, fmt.Errorf("failed to parse cache entry %s: %w", key, err)
+	}
+	return e, true, nil
+}
+
+// Put writes a single entry.
+func (b *DirBackend) Put(key string, e Entry) error {
+	if !ValidKey(key) {
+		return fmt.Errorf("invalid cache key %q", key)
+	}
+	data, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("failed to marshal cache entry: %w", err)
+	}
+	path := b.path(key)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create cache dir: %w", err)
+	}
+	return writeFileAtomic(path, data)
+}
+
+func (b *DirBackend) Fetch(keys []string) (map[string]Entry, error) {
+	found := make(map[string]Entry)
+	for _, k := range keys {
+		e, ok, err := b.Get(k)
+		if err != nil {
+			return found, err
+		}
+		if ok {
+			found[k] = e
+		}
+	}
+	return found, nil
+}
+
+func (b *DirBackend) Store(entries map[string]Entry) error {
+	for k, e := range entries {
+		if err := b.Put(k, e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// tieredBackend serves from the local backend first and falls back to a
+// shared one; stores go to both.
+type tieredBackend struct {
+	local  *LocalBackend
+	shared Backend
+}
+
+func (b *tieredBackend) Location() string {
+	return b.local.Location() + " (shared: " + b.shared.Location() + ")"
+}
+
+func (b *tieredBackend) Fetch(keys []string) (map[string]Entry, error) {
+	found, err := b.local.Fetch(keys)
+	if err != nil {
+		return nil, err
+	}
+	var missing []string
+	for _, k := range keys {
+		if _, ok := found[k]; !ok {
+			missing = append(missing, k)
+		}
+	}
+	if len(missing) == 0 {
+		return found, nil
+	}
+	remote, err := b.shared.Fetch(missing)
+	for k, e := range remote {
+		found[k] = e
+	}
+	if len(remote) > 0 {
+		// Keep what the team cache returned for the next offline run.
+		_ = b.local.Store(remote)
+	}
+	return found, err
+}
+
+func (b *tieredBackend) Store(entries map[string]Entry) error {
+	return errors.Join(b.local.Store(entries), b.shared.Store(entries))
+}
+
+// ValidKey reports whether key has the shape produced by Key.
+func ValidKey(key string) bool {
+	if len(key) != 64 {
+		return false
+	}
+	for _, r := range key {
+		if !(r >= '0' && r <= '9' || r >= 'a' && r <= 'f') {
+			return false
+		}
+	}
+	return true
+}
+
+// lockFile takes an exclusive lock by creating path. Locks older than
+// lockTimeout are assumed abandoned by a crashed process and broken.
+func lockFile(path string) (func(), error) {
+	deadline := time.Now().Add(lockTimeout)
+	for {
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o644)
+		if err == nil {
+			fmt.Fprintf(f, "%d\n", os.Getpid())
+			f.Close()
+			return func() { os.Remove(path) }, nil
+		}
+		if !os.IsExist(err) {
+			return nil, fmt.Errorf("failed to lock cache: %w", err)
+		}
+		if info, statErr := os.Stat(path); statErr == nil && time.Since(info.ModTime()) > lockTimeout {
+			os.Remove(path)
+			continue
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("timed out waiting for cache lock %s", path)
+		}
+		time.Sleep(lockRetry)
+	}
+}
+
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-"+filepath.Base(path)+"-*")
+	if err != nil {
+		return fmt.Errorf("failed to write cache: %w", err)
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write cache: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write cache: %w", err)
+	}
+	if err := os.Chmod(tmp.Name(), 0o644); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write cache: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write cache: %w", err)
+	}
+	return nil
+}
+
+// moduleIdentity returns the module root enclosing dir and its module path.
+// Outside a module it returns dir and its base name.
+func moduleIdentity(dir string) (root, module string) {
+	for d := dir; ; d = filepath.Dir(d) {
+		if f, err := os.Open(filepath.Join(d, "go.mod")); err == nil {
+			scanner := bufio.NewScanner(f)
+			for scanner.Scan() {
+				line := strings.TrimSpace(scanner.Text())
+				if strings.HasPrefix(line, "module ") {
+					module = strings.Trim(strings.TrimSpace(strings.TrimPrefix(line, "module ")), `"`)
+					break
+				}
+			}
+			f.Close()
+			if module == "" {
+				module = filepath.Base(d)
+			}
+			return d, module
+		}
+		if filepath.Dir(d) == d {
+			return dir, filepath.Base(dir)
+		}
+	}
+}
+
+func sanitizeName(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '.':
+			return r
+		}
+		return '_'
+	}, s)
+}
@@ -0,0 +1,147 @@
+//go:build unit
+// +build unit
+
+package cache
+
+import (
+	"bytes"
+	"fmt"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func testKey(i int) string {
+	return fmt.Sprintf("%064x", i)
+}
+
+// TestLocalBackend_ConcurrentWriters verifies no writer's entries are lost
+// when several runs save to the same project cache at once
+func TestLocalBackend_ConcurrentWriters(t *testing.T) {
+	b := NewLocalBackendAt(filepath.Join(t.TempDir(), "results", "proj.jsonl"))
+
+	const writers = 8
+	var wg sync.WaitGroup
+	for w := 0; w < writers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			if err := b.Store(map[string]Entry{testKey(w): {Status: "killed"}}); err != nil {
+				t.Error(err)
+			}
+		}(w)
+	}
+	wg.Wait()
+
+	all, err := b.All()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != writers {
+		t.Fatalf("expected %d entries, got %d", writers, len(all))
+	}
+}
+
+// TestHTTPBackend_RoundTrip verifies entries stored through the HTTP
+// protocol are served back by an in-process server
+func TestHTTPBackend_RoundTrip(t *testing.T) {
+	store, err := NewDirBackend(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := httptest.NewServer(Handler(store))
+	defer srv.Close()
+
+	b := NewHTTPBackend(srv.URL + "/cache")
+	want := Entry{Status: "killed", KilledBy: "TestAdd"}
+	if err := b.Store(map[string]Entry{testKey(1): want}); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := b.Fetch([]string{testKey(1), testKey(2)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || got[testKey(1)] != want {
+		t.Fatalf("unexpected fetch result: %+v", got)
+	}
+	if _, ok, _ := store.Get(testKey(1)); !ok {
+		t.Fatal("expected server to persist the entry")
+	}
+}
+
+// TestHTTPBackend_RejectsInvalidKeys verifies the server refuses paths that
+// are not cache keys
+func TestHTTPBackend_RejectsInvalidKeys(t *testing.T) {
+	store, err := NewDirBackend(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := httptest.NewServer(Handler(store))
+	defer srv.Close()
+
+	resp, err := srv.Client().Get(srv.URL + "/..%2f..%2fetc%2fpasswd")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != 400 {
+		t.Fatalf("expected 400, got %d", resp.StatusCode)
+	}
+}
+
+// TestCache_ExportImport verifies a tarball carries every entry to another
+// project cache
+func TestCache_ExportImport(t *testing.T) {
+	src := NewLocalBackendAt(filepath.Join(t.TempDir(), "src.jsonl"))
+	dst := NewLocalBackendAt(filepath.Join(t.TempDir(), "dst.jsonl"))
+	if err := src.Store(map[string]Entry{testKey(1): {Status: "killed"}, testKey(2): {Status: "survived"}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := dst.Store(map[string]Entry{testKey(3): {Status: "untested"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if n, err := Export(src, &buf); err != nil || n != 2 {
+		t.Fatalf("export: n=%d err=%v", n, err)
+	}
+	if n, err := Import(&buf, dst); err != nil || n != 2 {
+		t.Fatalf("import: n=%d err=%v", n, err)
+	}
+
+	all, err := dst.All()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 3 || all[testKey(2)].Status != "survived" {
+		t.Fatalf("unexpected entries after import: %+v", all)
+	}
+}
+
+// TestTieredBackend_FallsBackToShared verifies misses in the local cache are
+// served from the shared one and kept locally
+func TestTieredBackend_FallsBackToShared(t *testing.T) {
+	local := NewLocalBackendAt(filepath.Join(t.TempDir(), "local.jsonl"))
+	shared, err := NewDirBackend(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := shared.Put(testKey(1), Entry{Status: "killed"}); err != nil {
+		t.Fatal(err)
+	}
+	b := &tieredBackend{local: local, shared: shared}
+
+	got, err := b.Fetch([]string{testKey(1)})
+	if err != nil || got[testKey(1)].Status != "killed" {
+		t.Fatalf("expected shared hit, got %+v (%v)", got, err)
+	}
+	if all, _ := local.All(); len(all) != 1 {
+		t.Fatal("expected shared hit to be stored locally")
+	}
+	if !strings.Contains(b.Location(), "shared") {
+		t.Fatalf("unexpected location %q", b.Location())
+	}
+}
@@ -0,0 +1,197 @@
+package cache
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultBinaryCacheBytes bounds the binary store when no size is configured.
+const DefaultBinaryCacheBytes int64 = 2 << 30
+
+const binaryDirName = "bin"
+
+// BinaryStore is a content-addressed store of compiled test binaries under
+// ~/.cache/gorgon/bin. Each binary is stored as a single file named by its
+// key; the file's mtime records its last use and drives LRU eviction once
+// the store grows beyond maxBytes.
+type BinaryStore struct {
+	dir      string
+	maxBytes int64
+	mu       sync.Mutex
+}
+
+// OpenBinaryStore returns the binary store in the default cache dir.
+// maxBytes <= 0 selects DefaultBinaryCacheBytes.
+func OpenBinaryStore(maxBytes int64) (*BinaryStore, error) {
+	dir, err := cacheDir()
+	if err != nil {
+		return nil, err
+	}
+	return OpenBinaryStoreAt(filepath.Join(dir, binaryDirName), maxBytes)
+}
+
+// OpenBinaryStoreAt returns a binary store rooted at dir.
+func OpenBinaryStoreAt(dir string, maxBytes int64) (*BinaryStore, error) {
+	if maxBytes <= 0 {
+		maxBytes = DefaultBinaryCacheBytes
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create binary cache dir: %w", err)
+	}
+	return &BinaryStore{dir: dir, maxBytes: maxBytes}, nil
+}
+
+// Dir returns the directory backing the store.
+func (s *BinaryStore) Dir() string {
+	return s.dir
+}
+
+func (s *BinaryStore) path(key string) string {
+	return filepath.Join(s.dir, key[:2], key)
+}
+
+// Fetch materialises the binary stored under key at dest. It reports false
+// when the key is unknown or the binary cannot be placed.
+func (s *BinaryStore) Fetch(key, dest string) bool {
+	if len(key) < 2 {
+		return false
+	}
+	src := s.path(key)
+	if _, err := os.Stat(src); err != nil {
+		return false
+	}
+	_ = os.Remove(dest)
+	if err := os.Link(src, dest); err != nil {
+		if err := copyExecutable(src, dest); err != nil {
+			return false
+		}
+	}
+	now := time.Now()
+	_ = os.Chtimes(src, now, now)
+	return true
+}
+
+// Put stores the binary at src under key and evicts least-recently-used
+// entries if the store exceeds its size bound. The write goes through a
+// temp file and rename so concurrent readers never see a partial binary.
+func (s *BinaryStore) Put(key, src string) error {
+	if len(key) < 2 {
+		return fmt.Errorf("invalid binary cache key %q", key)
+	}
+	dest := s.path(key)
+	if err := os.MkdirAll(filepath.Dir(dest), 0o755); err != nil {
+		return fmt.Errorf("failed to create binary cache dir: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(dest), ".tmp-"+key[:8]+"-*")
+	if err != nil {
+		return fmt.Errorf("failed to create binary cache entry: %w", err)
+	}
+	tmpName := tmp.Name()
+	tmp.Close()
+	if err := copyExecutable(src, tmpName); err != nil {
+		os.Remove(tmpName)
+		return err
+	}
+	if err := os.Chmod(tmpName, 0o755); err != nil {
+		os.Remove(tmpName)
+		return fmt.Errorf("failed to store binary: %w", err)
+	}
+	if err := os.Rename(tmpName, dest); err != nil {
+		os.Remove(tmpName)
+		return fmt.Errorf("failed to store binary: %w", err)
+	}
+	return s.Evict()
+}
+
+// Evict removes least-recently-used binaries until the store fits within
+// its size bound.
+func (s *BinaryStore) Evict() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	type entry struct {
+		path  string
+		size  int64
+		mtime time.Time
+	}
+	var entries []entry
+	var total int64
+	err := filepath.Walk(s.dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() || strings.HasPrefix(info.Name(), ".tmp-") {
+			return nil
+		}
+		entries = append(entries, entry{path: path, size: info.Size(), mtime: info.ModTime()})
+		total += info.Size()
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to scan binary cache: %w", err)
+	}
+	if total <= s.maxBytes {
+		return nil
+	}
+
+	sort.Slice(entries, func(i, j int) bool { return entries[i].mtime.Before(entries[j].mtime) })
+	for _, e := range entries {
+		if total <= s.maxBytes {
+			break
+		}
+		if err := os.Remove(e.path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to evict %s: %w", e.path, err)
+		}
+		total -= e.size
+	}
+	return nil
+}
+
+func copyExecutable(src, dest string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", src, err)
+	}
+	defer in.Close()
+	out, err := os.OpenFile(dest, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o755)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", dest, err)
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return fmt.Errorf("failed to copy %s: %w", src, err)
+	}
+	return out.Close()
+}
+
+// Usage reports the number of binaries in the store and their total size.
+func (s *BinaryStore) Usage() (count int, bytes int64, err error) {
+	err = filepath.Walk(s.dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() || strings.HasPrefix(info.Name(), ".tmp-") {
+			return nil
+		}
+		count++
+		bytes += info.Size()
+		return nil
+	})
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to scan binary cache: %w", err)
+	}
+	return count, bytes, nil
+}
+
+// Clear removes every binary from the store.
+func (s *BinaryStore) Clear() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := os.RemoveAll(s.dir); err != nil {
+		return fmt.Errorf("failed to clear binary cache: %w", err)
+	}
+	if err := os.MkdirAll(s.dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create binary cache dir: %w", err)
+	}
+	return nil
+}
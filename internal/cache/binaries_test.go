@@ -0,0 +1,90 @@
+//go:build unit
+// +build unit
+
+package cache
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeBinary(t *testing.T, dir, name string, size int) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(strings.Repeat("x", size)), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// TestBinaryStore_PutFetch verifies a stored binary is returned executable
+func TestBinaryStore_PutFetch(t *testing.T) {
+	store, err := OpenBinaryStoreAt(t.TempDir(), 1<<20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	work := t.TempDir()
+	key := strings.Repeat("ab", 32)
+
+	if store.Fetch(key, filepath.Join(work, "miss.test")) {
+		t.Fatal("expected miss on empty store")
+	}
+	if err := store.Put(key, writeBinary(t, work, "package.test", 10)); err != nil {
+		t.Fatal(err)
+	}
+
+	dest := filepath.Join(work, "hit.test")
+	if !store.Fetch(key, dest) {
+		t.Fatal("expected hit after Put")
+	}
+	info, err := os.Stat(dest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Size() != 10 || info.Mode().Perm()&0o100 == 0 {
+		t.Fatalf("unexpected fetched binary: size=%d mode=%v", info.Size(), info.Mode())
+	}
+}
+
+// TestBinaryStore_EvictsLeastRecentlyUsed verifies the size bound is enforced
+// by removing the binaries that were used longest ago
+func TestBinaryStore_EvictsLeastRecentlyUsed(t *testing.T) {
+	store, err := OpenBinaryStoreAt(t.TempDir(), 25)
+	if err != nil {
+		t.Fatal(err)
+	}
+	work := t.TempDir()
+	keyA := strings.Repeat("a", 64)
+	keyB := strings.Repeat("b", 64)
+	keyC := strings.Repeat("c", 64)
+
+	if err := store.Put(keyA, writeBinary(t, work, "a", 10)); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Put(keyB, writeBinary(t, work, "b", 10)); err != nil {
+		t.Fatal(err)
+	}
+	old := time.Now().Add(-time.Hour)
+	_ = os.Chtimes(store.path(keyA), old, old)
+	_ = os.Chtimes(store.path(keyB), old.Add(time.Minute), old.Add(time.Minute))
+
+	// Using A makes B the least recently used entry.
+	if !store.Fetch(keyA, filepath.Join(work, "a.out")) {
+		t.Fatal("expected hit for A")
+	}
+	if err := store.Put(keyC, writeBinary(t, work, "c", 10)); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := os.Stat(store.path(keyB)); !os.IsNotExist(err) {
+		t.Fatal("expected B to be evicted")
+	}
+	for _, k := range []string{keyA, keyC} {
+		if _, err := os.Stat(store.path(k)); err != nil {
+			t.Fatalf("expected %s to survive eviction: %v", k[:1], err)
+		}
+	}
+}
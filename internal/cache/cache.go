@@ -7,25 +7,103 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strconv"
 	"sync"
+	"time"
 )
 
+// SchemaVersion is the format of serialized caches (local files, exported
+// archives). Older versions are migrated on read; newer ones are ignored.
+//
+//	1: status only (unversioned files)
+//	2: full result record
+//	3: keys use module-relative file paths
+//	4: entries record the mutation site they were keyed from
+//	5: mutants inside functions are keyed by function (see FuncKey)
+const SchemaVersion = 5
+
+// Entry is the complete result record of one mutant, enough to reproduce
+// every report without rerunning it.
 type Entry struct {
-	Status string `json:"status"`
+	Status       string        `json:"status"`
+	KilledBy     string        `json:"killed_by,omitempty"`
+	KillDuration time.Duration `json:"kill_duration,omitempty"`
+	KillOutput   string        `json:"kill_output,omitempty"`
+	Error        string        `json:"error,omitempty"`
+	ErrorReason  string        `json:"error_reason,omitempty"`
+	// TestHash fingerprints the tests that produced Status: the package's
+	// _test.go files plus any external suite sources.
+	TestHash string `json:"test_hash,omitempty"`
+
+	// The mutation site the entry was keyed from, so `gorgon cache prune`
+	// and `verify` can check it against the tree without rerunning.
+	File     string `json:"file,omitempty"`
+	Line     int    `json:"line,omitempty"`
+	Column   int    `json:"column,omitempty"`
+	Node     uint8  `json:"node,omitempty"`
+	Operator string `json:"operator,omitempty"`
+	FileHash string `json:"file_hash,omitempty"`
+	DepsHash string `json:"deps_hash,omitempty"`
+	// Func, FuncHash and Path are set for mutants inside a function, which
+	// are keyed by FuncKey rather than Key.
+	Func     string `json:"func,omitempty"`
+	FuncHash string `json:"func_hash,omitempty"`
+	Path     string `json:"path,omitempty"`
+
+	// Fingerprint is the mutant's stable identity, for matching cached
+	// results against reports. It is not part of the key.
+	Fingerprint string `json:"fingerprint,omitempty"`
+}
+
+// HasSite reports whether e records the site it was keyed from. Entries
+// written before schema 4 do not.
+func (e Entry) HasSite() bool {
+	return e.File != "" && e.FileHash != ""
 }
 
+// SiteKey derives the key of the site e records.
+func (e Entry) SiteKey() string {
+	var c Cache
+	if e.Func != "" {
+		return c.FuncKey(e.File, e.Func, e.FuncHash, e.Path, e.Node, e.Operator, e.DepsHash)
+	}
+	return c.Key(e.File, e.Line, e.Column, e.Node, e.Operator, e.FileHash, e.DepsHash)
+}
+
+// Cache is the in-memory view of a Backend. Entries are read up front when
+// the backend can enumerate them and otherwise fetched on demand through
+// Prefetch; Save writes back only the entries set during this run.
 type Cache struct {
+	Version int              `json:"version"`
 	Entries map[string]Entry `json:"entries"`
 	mu      sync.RWMutex
+	backend Backend
+	dirty   map[string]bool
 }
 
 func New() *Cache {
 	return &Cache{
+		Version: SchemaVersion,
 		Entries: make(map[string]Entry),
+		dirty:   make(map[string]bool),
 	}
 }
 
+// Open returns a cache backed by b.
+func Open(b Backend) (*Cache, error) {
+	c := New()
+	c.backend = b
+	if enum, ok := b.(Enumerator); ok {
+		entries, err := enum.All()
+		if err != nil {
+			return nil, err
+		}
+		c.Entries = entries
+	}
+	return c, nil
+}
+
 func cacheDir() (string, error) {
 	home, err := os.UserHomeDir()
 	if err != nil {
@@ -34,6 +112,9 @@ func cacheDir() (string, error) {
 	return filepath.Join(home, ".cache", "gorgon"), nil
 }
 
+// cachePath names the local cache file of a project after its module path
+// and a hash of its absolute location, so two checkouts never share a file
+// by accident.
 func cachePath(projectDir string) (string, error) {
 	dir, err := cacheDir()
 	if err != nil {
@@ -43,61 +124,159 @@ func cachePath(projectDir string) (string, error) {
 	if err != nil {
 		return "", err
 	}
-	name := filepath.Base(abs) + "_cache_gorgon.json"
-	return filepath.Join(dir, name), nil
+	root, module := moduleIdentity(abs)
+	sum := sha256.Sum256([]byte(root))
+	name := sanitizeName(module) + "-" + hex.EncodeToString(sum[:6]) + ".jsonl"
+	return filepath.Join(dir, resultsDirName, name), nil
 }
 
 func Path(projectDir string) (string, error) {
 	return cachePath(projectDir)
 }
 
+// Load opens the local cache of the project at projectDir.
 func Load(projectDir string) (*Cache, error) {
-	path, err := cachePath(projectDir)
+	b, err := NewLocalBackend(projectDir)
 	if err != nil {
 		return nil, err
 	}
-	data, err := os.ReadFile(path)
-	if err != nil {
-		if os.IsNotExist(err) {
-			return New(), nil
+	return Open(b)
+}
+
+// decode parses a serialized cache, migrating older schemas. A cache from
+// a newer schema decodes as empty: results are cheap to recompute.
+func decode(data []byte) (*Cache, error) {
+	c := New()
+	c.Version = 0 // unversioned files predate the field
+	if err := json.Unmarshal(data, c); err != nil {
+		return nil, fmt.Errorf("failed to parse cache: %w", err)
+	}
+	if c.Version > SchemaVersion {
+		return New(), nil
+	}
+	c.migrate()
+	return c, nil
+}
+
+// migrate upgrades entries loaded from an older schema in place.
+func (c *Cache) migrate() {
+	if c.Entries == nil {
+		c.Entries = make(map[string]Entry)
+	}
+	if c.Version == 0 {
+		c.Version = 1
+	}
+	if c.Version == 1 {
+		// v1 kept only the status. Outcomes that reports annotate (killing
+		// test, duration, output) cannot be reconstructed, so drop them and
+		// let the next run record them in full.
+		for k, e := range c.Entries {
+			if e.Status != "survived" && e.Status != "untested" {
+				delete(c.Entries, k)
+			}
 		}
-		return nil, fmt.Errorf("failed to read cache: %w", err)
+		c.Version = 2
 	}
+	if c.Version == 2 {
+		// v2 keys hashed absolute file paths; they can never match again.
+		c.Entries = make(map[string]Entry)
+		c.Version = 3
+	}
+	if c.Version == 3 {
+		// v4 only adds site fields; v3 entries stay valid without them.
+		c.Version = 4
+	}
+	if c.Version == 4 {
+		// v5 keys mutants inside functions differently; v4 keys for them can
+		// never match again and cannot be told apart from the rest.
+		c.Entries = make(map[string]Entry)
+		c.Version = 5
+	}
+}
 
-	var c Cache
-	if err := json.Unmarshal(data, &c); err != nil {
-		return nil, fmt.Errorf("failed to parse cache: %w", err)
+func sortedKeys(entries map[string]Entry) []string {
+	keys := make([]string, 0, len(entries))
+	for k := range entries {
+		keys = append(keys, k)
 	}
-	return &c, nil
+	sort.Strings(keys)
+	return keys
 }
 
-func (c *Cache) Save(projectDir string) error {
-	dir, err := cacheDir()
+func encode(entries map[string]Entry) ([]byte, error) {
+	data, err := json.MarshalIndent(&Cache{Version: SchemaVersion, Entries: entries}, "", "  ")
 	if err != nil {
+		return nil, fmt.Errorf("failed to marshal cache: %w", err)
+	}
+	return data, nil
+}
+
+// Save writes the entries set since the cache was opened to its backend.
+func (c *Cache) Save() error {
+	if c.backend == nil {
+		return nil
+	}
+	c.mu.Lock()
+	pending := make(map[string]Entry, len(c.dirty))
+	for k := range c.dirty {
+		pending[k] = c.Entries[k]
+	}
+	c.mu.Unlock()
+	if len(pending) == 0 {
+		return nil
+	}
+	if err := c.backend.Store(pending); err != nil {
 		return err
 	}
-	if err := os.MkdirAll(dir, 0o755); err != nil {
-		return fmt.Errorf("failed to create cache dir: %w", err)
+	c.mu.Lock()
+	for k := range pending {
+		delete(c.dirty, k)
 	}
+	c.mu.Unlock()
+	return nil
+}
 
-	path, err := cachePath(projectDir)
-	if err != nil {
-		return err
+// Location describes where the cache is stored.
+func (c *Cache) Location() string {
+	if c.backend == nil {
+		return ""
 	}
-	data, err := json.MarshalIndent(c, "", "  ")
-	if err != nil {
-		return fmt.Errorf("failed to marshal cache: %w", err)
+	return c.backend.Location()
+}
+
+// Prefetch asks the backend for keys not yet in memory. Backends that were
+// enumerated on Open have nothing more to return.
+func (c *Cache) Prefetch(keys []string) error {
+	if c.backend == nil {
+		return nil
 	}
-	if err := os.WriteFile(path, data, 0o644); err != nil {
-		return fmt.Errorf("failed to write cache: %w", err)
+	if _, ok := c.backend.(Enumerator); ok {
+		return nil
 	}
-	return nil
+	c.mu.RLock()
+	missing := make([]string, 0, len(keys))
+	for _, k := range keys {
+		if _, ok := c.Entries[k]; !ok {
+			missing = append(missing, k)
+		}
+	}
+	c.mu.RUnlock()
+	if len(missing) == 0 {
+		return nil
+	}
+	found, err := c.backend.Fetch(missing)
+	c.mu.Lock()
+	for k, e := range found {
+		c.Entries[k] = e
+	}
+	c.mu.Unlock()
+	return err
 }
 
-func (c *Cache) Key(filePath string, line, col int, nodeType uint8, operator string, fileHash string) string {
-	// Format: "filePath:line:col:nodeType:operator:fileHash"
+func (c *Cache) Key(filePath string, line, col int, nodeType uint8, operator string, fileHash, depsHash string) string {
+	// Format: "filePath:line:col:nodeType:operator:fileHash:depsHash"
 	const colon = byte(':')
-	cap := len(filePath) + len(operator) + len(fileHash) + 30
+	cap := len(filePath) + len(operator) + len(fileHash) + len(depsHash) + 31
 	buf := make([]byte, 0, cap)
 	buf = append(buf, filePath...)
 	buf = append(buf, colon)
@@ -110,12 +289,27 @@ func (c *Cache) Key(filePath string, line, col int, nodeType uint8, operator str
 	buf = append(buf, operator...)
 	buf = append(buf, colon)
 	buf = append(buf, fileHash...)
+	buf = append(buf, colon)
+	buf = append(buf, depsHash...)
 
 	h := sha256.New()
 	h.Write(buf)
 	return hex.EncodeToString(h.Sum(nil))
 }
 
+// FuncKey derives the key of a mutant inside function fn. The function is
+// identified by its normalised hash and the mutant by its AST path within
+// it, so edits elsewhere in the file (imports, comments, other functions)
+// leave the key unchanged.
+func (c *Cache) FuncKey(filePath, fn, funcHash, path string, nodeType uint8, operator, depsHash string) string {
+	h := sha256.New()
+	for _, part := range []string{"func", filePath, fn, funcHash, path, strconv.Itoa(int(nodeType)), operator, depsHash} {
+		h.Write([]byte(part))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
 func (c *Cache) Get(key string) (Entry, bool) {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
@@ -123,8 +317,14 @@ func (c *Cache) Get(key string) (Entry, bool) {
 	return e, ok
 }
 
-func (c *Cache) Set(key string, status string) {
+// Set records e under key for the next Save. An entry the cache already
+// holds unchanged is not written again.
+func (c *Cache) Set(key string, e Entry) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
-	c.Entries[key] = Entry{Status: status}
+	if old, ok := c.Entries[key]; ok && old == e {
+		return
+	}
+	c.Entries[key] = e
+	c.dirty[key] = true
 }
@@ -3,7 +3,13 @@
 
 package cache
 
-import "testing"
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
 
 // TestCache_New verifies cache initialization
 func TestCache_New(t *testing.T) {
@@ -34,3 +40,121 @@ func TestCache_Exists(t *testing.T) {
 func TestCache_Delete(t *testing.T) {
 	t.Skip("TODO: Verify cache delete removes specific entry")
 }
+
+// TestCache_LoadMigratesOldSchemas verifies caches from older schemas load
+// as the current version without entries whose keys can no longer match
+func TestCache_LoadMigratesOldSchemas(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	project := filepath.Join(t.TempDir(), "proj")
+	path, err := Path(project)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, old := range []string{
+		`{"entries": {"k": {"status": "killed"}, "s": {"status": "survived"}}}`,
+		`{"version": 2, "entries": {"k": {"status": "killed", "killed_by": "TestX"}}}`,
+	} {
+		if err := os.WriteFile(path, []byte(old), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		c, err := Load(project)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if c.Version != SchemaVersion {
+			t.Fatalf("expected version %d, got %d", SchemaVersion, c.Version)
+		}
+		if len(c.Entries) != 0 {
+			t.Fatalf("expected entries with stale keys to be dropped, got %v", c.Entries)
+		}
+	}
+}
+
+// TestCache_SaveLoadRoundTrip verifies the full result record is persisted
+func TestCache_SaveLoadRoundTrip(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	project := filepath.Join(t.TempDir(), "proj")
+
+	want := Entry{
+		Status:       "killed",
+		KilledBy:     "TestAdd",
+		KillDuration: 1500 * time.Millisecond,
+		KillOutput:   "--- FAIL: TestAdd",
+		Error:        "boom",
+		TestHash:     "abc",
+	}
+	c, err := Load(project)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.Set("k", want)
+	if err := c.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := Load(project)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, ok := loaded.Get("k"); !ok || got != want {
+		t.Fatalf("round trip mismatch: got %+v, want %+v", got, want)
+	}
+}
+
+// TestCache_SaveSkipsUnchangedEntries verifies re-setting the results a run
+// found in the cache appends nothing to the log
+func TestCache_SaveSkipsUnchangedEntries(t *testing.T) {
+	local := NewLocalBackendAt(filepath.Join(t.TempDir(), "proj.jsonl"))
+	e := Entry{Status: "killed", KilledBy: "TestAdd"}
+	if err := local.Store(map[string]Entry{"a": e, "b": e}); err != nil {
+		t.Fatal(err)
+	}
+
+	c, err := Open(local)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Prefetch([]string{"a", "b"}); err != nil {
+		t.Fatal(err)
+	}
+	c.Set("a", e)
+	c.Set("b", Entry{Status: "survived"})
+	if err := c.Save(); err != nil {
+		t.Fatal(err)
+	}
+	stats, err := local.Stats()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Records != 3 || stats.Live != 2 {
+		t.Fatalf("expected only the changed entry appended, got %+v", stats)
+	}
+}
+
+// TestCache_PathDistinguishesCheckouts verifies two checkouts with the same
+// directory name do not share a cache file
+func TestCache_PathDistinguishesCheckouts(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	a := filepath.Join(t.TempDir(), "api")
+	b := filepath.Join(t.TempDir(), "api")
+	for _, dir := range []string{a, b} {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module example.com/api\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	pa, _ := Path(a)
+	pb, _ := Path(b)
+	if pa == pb {
+		t.Fatalf("expected distinct cache paths, both are %s", pa)
+	}
+	if !strings.Contains(filepath.Base(pa), "example.com_api") {
+		t.Fatalf("expected module path in cache file name, got %s", pa)
+	}
+}
@@ -0,0 +1,150 @@
+package cache
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	httpFetchConcurrency = 8
+	maxEntryBytes        = 4 << 20
+)
+
+// HTTPBackend talks to a content-addressed cache server: GET <base>/<key>
+// returns an entry as JSON (404 when unknown) and PUT <base>/<key> stores
+// one. Any server following that contract works, including Handler.
+type HTTPBackend struct {
+	base   string
+	client *http.Client
+}
+
+// NewHTTPBackend returns a backend for the server at base.
+func NewHTTPBackend(base string) *HTTPBackend {
+	return &HTTPBackend{
+		base:   strings.TrimRight(base, "/"),
+		client: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+func (b *HTTPBackend) Location() string { return b.base }
+
+func (b *HTTPBackend) Fetch(keys []string) (map[string]Entry, error) {
+	found := make(map[string]Entry)
+	var (
+		mu   sync.Mutex
+		wg   sync.WaitGroup
+		errs []error
+	)
+	sem := make(chan struct{}, httpFetchConcurrency)
+	for _, k := range keys {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(key string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			e, ok, err := b.get(key)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				errs = append(errs, err)
+				return
+			}
+			if ok {
+				found[key] = e
+			}
+		}(k)
+	}
+	wg.Wait()
+	return found, errors.Join(errs...)
+}
+
+func (b *HTTPBackend) get(key string) (Entry, bool, error) {
+	resp, err := b.client.Get(b.base + "/" + key)
+	if err != nil {
+		return Entry{}, false, fmt.Errorf("failed to fetch cache entry: %w", err)
+	}
+	defer resp.Body.Close()
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return Entry{}, false, nil
+	default:
+		return Entry{}, false, fmt.Errorf("failed to fetch cache entry %s: %s", key, resp.Status)
+	}
+	var e Entry
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxEntryBytes)).Decode(&e); err != nil {
+		return Entry{}, false, fmt.Errorf("failed to parse cache entry %s: %w", key, err)
+	}
+	return e, true, nil
+}
+
+func (b *HTTPBackend) Store(entries map[string]Entry) error {
+	var errs []error
+	for k, e := range entries {
+		data, err := json.Marshal(e)
+		if err != nil {
+			return fmt.Errorf("failed to marshal cache entry: %w", err)
+		}
+		req, err := http.NewRequest(http.MethodPut, b.base+"/"+k, bytes.NewReader(data))
+		if err != nil {
+			return fmt.Errorf("failed to store cache entry: %w", err)
+		}
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := b.client.Do(req)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to store cache entry: %w", err))
+			continue
+		}
+		resp.Body.Close()
+		if resp.StatusCode/100 != 2 {
+			errs = append(errs, fmt.Errorf("failed to store cache entry %s: %s", k, resp.Status))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Handler serves the HTTPBackend protocol from a DirBackend.
+func Handler(store *DirBackend) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := strings.TrimPrefix(r.URL.Path[strings.LastIndex(r.URL.Path, "/"):], "/")
+		if !ValidKey(key) {
+			http.Error(w, "invalid cache key", http.StatusBadRequest)
+			return
+		}
+		switch r.Method {
+		case http.MethodGet, http.MethodHead:
+			e, ok, err := store.Get(key)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			if !ok {
+				http.NotFound(w, r)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(e)
+		case http.MethodPut:
+			var e Entry
+			if err := json.NewDecoder(io.LimitReader(r.Body, maxEntryBytes)).Decode(&e); err != nil {
+				http.Error(w, "invalid cache entry: "+err.Error(), http.StatusBadRequest)
+				return
+			}
+			if err := store.Put(key, e); err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.Header().Set("Allow", "GET, HEAD, PUT")
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		}
+	})
+}
@@ -0,0 +1,409 @@
+package cache
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// Compaction runs automatically once the log holds at least this many
+// lines and more than half of them are superseded.
+const autoCompactMinLines = 4096
+
+// LocalBackend keeps one project's entries in an append-only log under
+// ~/.cache/gorgon/results. The first line is a header carrying the schema
+// version; every later line records one entry (or a deletion) and later
+// lines win on replay. Store only appends, so a run's save costs as much
+// as the entries it wrote; Compact rewrites the log with live entries only.
+type LocalBackend struct {
+	path string
+
+	mu    sync.Mutex
+	tally *logTally // nil until the log is first replayed
+}
+
+// logTally follows the size of the log from its last replay through this
+// process's own appends, so deciding whether to compact after a save does
+// not replay the log again. Appends by concurrent runs are missed until
+// the next replay; compaction replays under the lock regardless.
+type logTally struct {
+	records int
+	keys    map[string]bool // live keys
+}
+
+// logHeader is the first line of a log.
+type logHeader struct {
+	Version int `json:"version"`
+}
+
+// logRecord is one line of a log after the header.
+type logRecord struct {
+	Key     string `json:"key"`
+	Entry   *Entry `json:"entry,omitempty"`
+	Deleted bool   `json:"deleted,omitempty"`
+}
+
+// LogStats describes the physical state of a log.
+type LogStats struct {
+	Version int   // schema version in the header
+	Bytes   int64 // size on disk
+	Records int   // entry and deletion lines
+	Live    int   // entries after replay
+	Corrupt int   // lines that failed to parse (e.g. torn by a crash)
+}
+
+// Garbage is the number of records a compaction would drop.
+func (s LogStats) Garbage() int {
+	return s.Records - s.Live
+}
+
+// NewLocalBackend returns the local backend of the project at projectDir.
+func NewLocalBackend(projectDir string) (*LocalBackend, error) {
+	path, err := cachePath(projectDir)
+	if err != nil {
+		return nil, err
+	}
+	return &LocalBackend{path: path}, nil
+}
+
+// NewLocalBackendAt returns a local backend reading and writing path.
+func NewLocalBackendAt(path string) *LocalBackend {
+	return &LocalBackend{path: path}
+}
+
+func (b *LocalBackend) Location() string { return b.path }
+
+// legacyPath is where caches were stored as a single JSON document.
+func (b *LocalBackend) legacyPath() string {
+	return strings.TrimSuffix(b.path, filepath.Ext(b.path)) + ".json"
+}
+
+func (b *LocalBackend) All() (map[string]Entry, error) {
+	entries, _, err := b.replay()
+	return entries, err
+}
+
+// Fetch returns the entries stored under keys. A log has no index, so this
+// reads it whole; a run fetches once, and its Store then works from the
+// tally this leaves.
+func (b *LocalBackend) Fetch(keys []string) (map[string]Entry, error) {
+	all, err := b.All()
+	if err != nil {
+		return nil, err
+	}
+	found := make(map[string]Entry)
+	for _, k := range keys {
+		if e, ok := all[k]; ok {
+			found[k] = e
+		}
+	}
+	return found, nil
+}
+
+// Store appends entries to the log. The lock is held only for the append,
+// so concurrent runs interleave whole records and none are lost.
+func (b *LocalBackend) Store(entries map[string]Entry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+	records := make([]logRecord, 0, len(entries))
+	for k, e := range entries {
+		e := e
+		records = append(records, logRecord{Key: k, Entry: &e})
+	}
+	if err := b.append(records); err != nil {
+		return err
+	}
+	return b.maybeCompact()
+}
+
+// Delete appends deletion records for keys.
+func (b *LocalBackend) Delete(keys []string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	records := make([]logRecord, 0, len(keys))
+	for _, k := range keys {
+		records = append(records, logRecord{Key: k, Deleted: true})
+	}
+	return b.append(records)
+}
+
+// Stats replays the log and reports its physical state.
+func (b *LocalBackend) Stats() (LogStats, error) {
+	_, stats, err := b.replay()
+	return stats, err
+}
+
+// Compact rewrites the log with only live entries.
+func (b *LocalBackend) Compact() (LogStats, error) {
+	unlock, err := b.lock()
+	if err != nil {
+		return LogStats{}, err
+	}
+	defer unlock()
+	return b.compactLocked()
+}
+
+// Clear removes the log.
+func (b *LocalBackend) Clear() error {
+	unlock, err := b.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	for _, p := range []string{b.path, b.legacyPath()} {
+		if err := os.Remove(p); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to clear cache: %w", err)
+		}
+	}
+	return nil
+}
+
+func (b *LocalBackend) lock() (func(), error) {
+	if err := os.MkdirAll(filepath.Dir(b.path), 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create cache dir: %w", err)
+	}
+	return lockFile(b.path + ".lock")
+}
+
+func (b *LocalBackend) append(records []logRecord) error {
+	unlock, err := b.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if err := b.importLegacy(); err != nil {
+		return err
+	}
+	if v := b.headerVersion(); v != 0 && v < SchemaVersion {
+		// Appending current records under an old header would have them
+		// migrated away on the next replay; rewrite the log first.
+		if _, err := b.compactLocked(); err != nil {
+			return err
+		}
+	}
+
+	var buf bytes.Buffer
+	info, statErr := os.Stat(b.path)
+	if statErr != nil || info.Size() == 0 {
+		writeJSONLine(&buf, logHeader{Version: SchemaVersion})
+	}
+	for _, r := range records {
+		writeJSONLine(&buf, r)
+	}
+
+	f, err := os.OpenFile(b.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to write cache: %w", err)
+	}
+	if _, err := f.Write(buf.Bytes()); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write cache: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to write cache: %w", err)
+	}
+
+	b.mu.Lock()
+	if t := b.tally; t != nil {
+		t.records += len(records)
+		for _, r := range records {
+			if r.Deleted {
+				delete(t.keys, r.Key)
+			} else {
+				t.keys[r.Key] = true
+			}
+		}
+	}
+	b.mu.Unlock()
+	return nil
+}
+
+func (b *LocalBackend) maybeCompact() error {
+	b.mu.Lock()
+	t := b.tally
+	b.mu.Unlock()
+	if t == nil {
+		if _, _, err := b.replay(); err != nil {
+			return err
+		}
+	}
+	b.mu.Lock()
+	records, live := b.tally.records, len(b.tally.keys)
+	b.mu.Unlock()
+	if records < autoCompactMinLines || records-live <= live {
+		return nil
+	}
+	_, err := b.Compact()
+	return err
+}
+
+// setTally records the state of the log as just replayed or rewritten.
+func (b *LocalBackend) setTally(records int, entries map[string]Entry) {
+	keys := make(map[string]bool, len(entries))
+	for k := range entries {
+		keys[k] = true
+	}
+	b.mu.Lock()
+	b.tally = &logTally{records: records, keys: keys}
+	b.mu.Unlock()
+}
+
+func (b *LocalBackend) compactLocked() (LogStats, error) {
+	entries, stats, err := b.replay()
+	if err != nil {
+		return stats, err
+	}
+	var buf bytes.Buffer
+	writeJSONLine(&buf, logHeader{Version: SchemaVersion})
+	for _, k := range sortedKeys(entries) {
+		e := entries[k]
+		writeJSONLine(&buf, logRecord{Key: k, Entry: &e})
+	}
+	if err := writeFileAtomic(b.path, buf.Bytes()); err != nil {
+		return stats, err
+	}
+	b.setTally(len(entries), entries)
+	return LogStats{Version: SchemaVersion, Bytes: int64(buf.Len()), Records: len(entries), Live: len(entries)}, nil
+}
+
+// importLegacy converts a single-document cache written by older versions
+// into the log. Callers hold the lock.
+func (b *LocalBackend) importLegacy() error {
+	legacy := b.legacyPath()
+	if legacy == b.path {
+		return nil
+	}
+	data, err := os.ReadFile(legacy)
+	if err != nil {
+		return nil
+	}
+	if _, err := os.Stat(b.path); err == nil {
+		return os.Remove(legacy)
+	}
+	c, err := decode(data)
+	if err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	writeJSONLine(&buf, logHeader{Version: SchemaVersion})
+	for _, k := range sortedKeys(c.Entries) {
+		e := c.Entries[k]
+		writeJSONLine(&buf, logRecord{Key: k, Entry: &e})
+	}
+	if err := writeFileAtomic(b.path, buf.Bytes()); err != nil {
+		return err
+	}
+	return os.Remove(legacy)
+}
+
+// replay reads the log (or a legacy single-document cache) into memory.
+func (b *LocalBackend) replay() (map[string]Entry, LogStats, error) {
+	entries := make(map[string]Entry)
+	var stats LogStats
+
+	f, err := os.Open(b.path)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			return nil, stats, fmt.Errorf("failed to read cache: %w", err)
+		}
+		data, legacyErr := os.ReadFile(b.legacyPath())
+		if legacyErr != nil || b.legacyPath() == b.path {
+			b.setTally(0, entries)
+			return entries, stats, nil
+		}
+		c, err := decode(data)
+		if err != nil {
+			return nil, stats, err
+		}
+		stats.Version = c.Version
+		stats.Bytes = int64(len(data))
+		stats.Records = len(c.Entries)
+		stats.Live = len(c.Entries)
+		b.setTally(stats.Records, c.Entries)
+		return c.Entries, stats, nil
+	}
+	defer f.Close()
+	if info, err := f.Stat(); err == nil {
+		stats.Bytes = info.Size()
+	}
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 2*maxEntryBytes)
+	first := true
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+		if first {
+			first = false
+			var h logHeader
+			if err := json.Unmarshal(line, &h); err != nil || h.Version == 0 {
+				stats.Corrupt++
+				continue
+			}
+			if h.Version > SchemaVersion {
+				// Written by a newer gorgon; results are cheap to recompute.
+				b.setTally(0, nil)
+				return make(map[string]Entry), LogStats{Version: h.Version, Bytes: stats.Bytes}, nil
+			}
+			stats.Version = h.Version
+			continue
+		}
+		var r logRecord
+		if err := json.Unmarshal(line, &r); err != nil || r.Key == "" || (r.Entry == nil && !r.Deleted) {
+			stats.Corrupt++
+			continue
+		}
+		stats.Records++
+		if r.Deleted {
+			delete(entries, r.Key)
+		} else {
+			entries[r.Key] = *r.Entry
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, stats, fmt.Errorf("failed to read cache: %w", err)
+	}
+	if stats.Version != 0 && stats.Version < SchemaVersion {
+		c := &Cache{Version: stats.Version, Entries: entries}
+		c.migrate()
+		entries = c.Entries
+	}
+	stats.Live = len(entries)
+	b.setTally(stats.Records, entries)
+	return entries, stats, nil
+}
+
+// headerVersion returns the schema version of the log, or 0 when there is
+// no readable header.
+func (b *LocalBackend) headerVersion() int {
+	f, err := os.Open(b.path)
+	if err != nil {
+		return 0
+	}
+	defer f.Close()
+	line, err := bufio.NewReader(f).ReadBytes('\n')
+	if err != nil {
+		return 0
+	}
+	var h logHeader
+	if json.Unmarshal(line, &h) != nil {
+		return 0
+	}
+	return h.Version
+}
+
+func writeJSONLine(buf *bytes.Buffer, v any) {
+	data, _ := json.Marshal(v)
+	buf.Write(data)
+	buf.WriteByte('\n')
+}
@@ -0,0 +1,339 @@
+//go:build unit
+// +build unit
+
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/aclfe/gorgon/internal/engine"
+)
+
+// TestLocalBackend_ReplayLastWriteWins verifies later records override and
+// delete earlier ones, and compaction keeps only the live entries
+func TestLocalBackend_ReplayLastWriteWins(t *testing.T) {
+	b := NewLocalBackendAt(filepath.Join(t.TempDir(), "proj.jsonl"))
+	if err := b.Store(map[string]Entry{testKey(1): {Status: "survived"}, testKey(2): {Status: "killed"}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Store(map[string]Entry{testKey(1): {Status: "killed"}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Delete([]string{testKey(2)}); err != nil {
+		t.Fatal(err)
+	}
+
+	all, err := b.All()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 1 || all[testKey(1)].Status != "killed" {
+		t.Fatalf("unexpected entries: %+v", all)
+	}
+	stats, err := b.Stats()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Records != 4 || stats.Garbage() != 3 {
+		t.Fatalf("unexpected stats before compaction: %+v", stats)
+	}
+
+	after, err := b.Compact()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if after.Records != 1 || after.Bytes >= stats.Bytes {
+		t.Fatalf("unexpected stats after compaction: %+v (before %+v)", after, stats)
+	}
+	if all, _ := b.All(); len(all) != 1 || all[testKey(1)].Status != "killed" {
+		t.Fatalf("compaction changed entries: %+v", all)
+	}
+}
+
+// TestLocalBackend_CompactsOnceMostlyGarbage verifies Store compacts the log
+// once superseded records outnumber live ones, and not before
+func TestLocalBackend_CompactsOnceMostlyGarbage(t *testing.T) {
+	b := NewLocalBackendAt(filepath.Join(t.TempDir(), "proj.jsonl"))
+	entries := make(map[string]Entry, autoCompactMinLines)
+	for i := 0; i < autoCompactMinLines; i++ {
+		entries[testKey(i)] = Entry{Status: "killed"}
+	}
+	for round, want := range []int{autoCompactMinLines, 2 * autoCompactMinLines, autoCompactMinLines} {
+		if err := b.Store(entries); err != nil {
+			t.Fatal(err)
+		}
+		stats, err := b.Stats()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if stats.Records != want || stats.Live != autoCompactMinLines {
+			t.Fatalf("store %d: expected %d records, got %+v", round+1, want, stats)
+		}
+	}
+}
+
+// TestLocalBackend_SkipsTornRecords verifies a partially written record (e.g.
+// from a crash mid-append) costs only that record
+func TestLocalBackend_SkipsTornRecords(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "proj.jsonl")
+	b := NewLocalBackendAt(path)
+	if err := b.Store(map[string]Entry{testKey(1): {Status: "killed"}}); err != nil {
+		t.Fatal(err)
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.WriteString(`{"key":"` + testKey(2) + `","entry":{"sta` + "\n")
+	f.Close()
+	if err := b.Store(map[string]Entry{testKey(3): {Status: "survived"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	all, err := b.All()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 2 {
+		t.Fatalf("expected the two intact entries, got %+v", all)
+	}
+	if stats, _ := b.Stats(); stats.Corrupt != 1 {
+		t.Fatalf("expected one corrupt record, got %+v", stats)
+	}
+}
+
+// TestLocalBackend_ImportsLegacyFile verifies a single-document cache from
+// an older version is read and converted to the log on first write
+func TestLocalBackend_ImportsLegacyFile(t *testing.T) {
+	dir := t.TempDir()
+	legacy := `{"version":` + strconv.Itoa(SchemaVersion) + `,"entries":{"` + testKey(1) + `":{"status":"killed","killed_by":"TestA"}}}`
+	if err := os.WriteFile(filepath.Join(dir, "proj.json"), []byte(legacy), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	b := NewLocalBackendAt(filepath.Join(dir, "proj.jsonl"))
+
+	if all, err := b.All(); err != nil || all[testKey(1)].KilledBy != "TestA" {
+		t.Fatalf("expected legacy entry, got %+v (%v)", all, err)
+	}
+	if err := b.Store(map[string]Entry{testKey(2): {Status: "survived"}}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "proj.json")); !os.IsNotExist(err) {
+		t.Fatal("expected legacy file to be removed after conversion")
+	}
+	if all, _ := b.All(); len(all) != 2 {
+		t.Fatalf("expected legacy and new entries, got %+v", all)
+	}
+}
+
+// siteEntry returns an entry for file content and its matching key.
+func siteEntry(file, content, deps string) (string, Entry) {
+	sum := sha256.Sum256([]byte(content))
+	e := Entry{Status: "killed", File: file, Line: 3, Column: 2, Operator: "arithmetic_flip", FileHash: hex.EncodeToString(sum[:]), DepsHash: deps}
+	var c Cache
+	return c.Key(e.File, e.Line, e.Column, e.Node, e.Operator, e.FileHash, e.DepsHash), e
+}
+
+// TestPrune_DropsChangedAndMissingFiles verifies only entries still keyed to
+// the current sources survive a prune
+func TestPrune_DropsChangedAndMissingFiles(t *testing.T) {
+	proj := t.TempDir()
+	writeFile(t, filepath.Join(proj, "go.mod"), "module example.com/p\n")
+	writeFile(t, filepath.Join(proj, "a.go"), "package p\n")
+	writeFile(t, filepath.Join(proj, "b.go"), "package p // edited\n")
+
+	deps := DepsHash(proj)
+	keyA, a := siteEntry("a.go", "package p\n", deps)
+	keyB, b := siteEntry("b.go", "package p\n", deps)
+	keyC, c := siteEntry("gone.go", "package p\n", deps)
+	local := NewLocalBackendAt(filepath.Join(t.TempDir(), "proj.jsonl"))
+	if err := local.Store(map[string]Entry{keyA: a, keyB: b, keyC: c, testKey(9): {Status: "killed"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := Prune(local, proj)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Kept != 1 || res.Stale != 1 || res.Missing != 1 || res.NoSite != 1 {
+		t.Fatalf("unexpected prune result: %+v", res)
+	}
+	all, _ := local.All()
+	if _, ok := all[keyA]; !ok || len(all) != 1 {
+		t.Fatalf("expected only %s to remain, got %+v", keyA, all)
+	}
+	if res.After.Garbage() != 0 {
+		t.Fatalf("expected prune to compact, got %+v", res.After)
+	}
+}
+
+// TestPrune_DropsEntriesKeyedOnOldDependencies verifies a go.sum change
+// makes every entry keyed on the old one stale, unchanged code or not
+func TestPrune_DropsEntriesKeyedOnOldDependencies(t *testing.T) {
+	proj := t.TempDir()
+	writeFile(t, filepath.Join(proj, "go.mod"), "module example.com/p\n")
+	writeFile(t, filepath.Join(proj, "a.go"), "package p\n")
+	keyOld, old := siteEntry("a.go", "package p\n", DepsHash(proj))
+	writeFile(t, filepath.Join(proj, "go.sum"), "example.com/dep v1.0.0 h1:x\n")
+	keyNew, cur := siteEntry("a.go", "package p\n", DepsHash(proj))
+
+	local := NewLocalBackendAt(filepath.Join(t.TempDir(), "proj.jsonl"))
+	if err := local.Store(map[string]Entry{keyOld: old, keyNew: cur}); err != nil {
+		t.Fatal(err)
+	}
+	current, err := CurrentEntries(local, proj)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := current[keyNew]; !ok || len(current) != 1 {
+		t.Fatalf("expected only the entry for the current go.sum, got %+v", current)
+	}
+	res, err := Prune(local, proj)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Kept != 1 || res.Stale != 1 {
+		t.Fatalf("unexpected prune result: %+v", res)
+	}
+}
+
+// TestVerify_ReportsMismatchedKeys verifies entries whose key no longer
+// matches their recorded site or whose status is unknown are reported
+func TestVerify_ReportsMismatchedKeys(t *testing.T) {
+	keyA, a := siteEntry("a.go", "package p\n", "d")
+	_, b := siteEntry("b.go", "package p\n", "d")
+	local := NewLocalBackendAt(filepath.Join(t.TempDir(), "proj.jsonl"))
+	if err := local.Store(map[string]Entry{
+		keyA:       a,
+		testKey(1): b,
+		testKey(2): {Status: "exploded"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := Verify(local)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Checked != 3 || len(res.Problems) != 2 || res.NoSite != 1 {
+		t.Fatalf("unexpected verify result: %+v", res)
+	}
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestPrune_KeepsFunctionEntriesAcrossUnrelatedEdits verifies entries keyed
+// by function survive edits elsewhere in their file and go when the function
+// itself changes
+func TestPrune_KeepsFunctionEntriesAcrossUnrelatedEdits(t *testing.T) {
+	proj := t.TempDir()
+	writeFile(t, filepath.Join(proj, "go.mod"), "module example.com/p\n")
+	writeFile(t, filepath.Join(proj, "a.go"), "package p\n\n// F and G changed around.\nfunc G() {}\n\nfunc F(x int) int { return x + 1 }\n")
+
+	fn := func(src, name string) string {
+		f, err := parser.ParseFile(token.NewFileSet(), "a.go", src, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, d := range f.Decls {
+			if fd, ok := d.(*ast.FuncDecl); ok && fd.Name.Name == name {
+				return engine.FuncHash(fd)
+			}
+		}
+		return ""
+	}
+	entry := func(body string) (string, Entry) {
+		e := Entry{Status: "killed", File: "a.go", FileHash: "old", Func: "F", Path: "FuncDecl.0/BlockStmt.2", Operator: "op", DepsHash: DepsHash(proj),
+			FuncHash: fn("package p\n\nfunc F(x int) int { "+body+" }\n", "F")}
+		return e.SiteKey(), e
+	}
+	keyKept, kept := entry("return x + 1")
+	keyGone, gone := entry("return x - 1")
+	local := NewLocalBackendAt(filepath.Join(t.TempDir(), "proj.jsonl"))
+	if err := local.Store(map[string]Entry{keyKept: kept, keyGone: gone}); err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := Prune(local, proj)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Kept != 1 || res.Stale != 1 {
+		t.Fatalf("unexpected prune result: %+v", res)
+	}
+	if all, _ := local.All(); len(all) != 1 || all[keyKept].Func != "F" {
+		t.Fatalf("expected only the unchanged function's entry, got %+v", all)
+	}
+}
+
+// TestPrune_KeepsEntriesForEveryInit verifies that entries for several
+// functions sharing an identity, like init, are all kept while unchanged
+func TestPrune_KeepsEntriesForEveryInit(t *testing.T) {
+	proj := t.TempDir()
+	src := "package p\n\nvar x int\n\nfunc init() { x++ }\n\nfunc init() { x-- }\n"
+	writeFile(t, filepath.Join(proj, "go.mod"), "module example.com/p\n")
+	writeFile(t, filepath.Join(proj, "a.go"), src+"\n// edited\n")
+
+	f, err := parser.ParseFile(token.NewFileSet(), "a.go", src, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	entries := make(map[string]Entry)
+	for i, d := range f.Decls {
+		fd, ok := d.(*ast.FuncDecl)
+		if !ok {
+			continue
+		}
+		e := Entry{Status: "killed", File: "a.go", FileHash: "old", Func: engine.FuncIdentity(fd), FuncHash: engine.FuncHash(fd),
+			Path: fmt.Sprintf("FuncDecl.%d/BlockStmt.2", i), Operator: "op", DepsHash: DepsHash(proj)}
+		entries[e.SiteKey()] = e
+	}
+	local := NewLocalBackendAt(filepath.Join(t.TempDir(), "proj.jsonl"))
+	if err := local.Store(entries); err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := Prune(local, proj)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 || res.Kept != 2 || res.Stale != 0 {
+		t.Fatalf("expected both init entries kept, got %+v", res)
+	}
+}
+
+// TestLocalBackend_MigratesOldLogs verifies entries under an older log
+// header are migrated on read and the log is rewritten before appending
+func TestLocalBackend_MigratesOldLogs(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "proj.jsonl")
+	writeFile(t, path, `{"version":4}`+"\n"+`{"key":"`+testKey(1)+`","entry":{"status":"killed"}}`+"\n")
+	b := NewLocalBackendAt(path)
+
+	if all, _ := b.All(); len(all) != 0 {
+		t.Fatalf("expected v4 entries to be dropped, got %+v", all)
+	}
+	if err := b.Store(map[string]Entry{testKey(2): {Status: "survived"}}); err != nil {
+		t.Fatal(err)
+	}
+	stats, err := b.Stats()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Version != SchemaVersion || stats.Live != 1 {
+		t.Fatalf("expected a rewritten current log with one entry, got %+v", stats)
+	}
+}
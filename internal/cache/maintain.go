@@ -0,0 +1,254 @@
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/aclfe/gorgon/internal/engine"
+)
+
+// knownStatuses are the mutant statuses an entry may record.
+var knownStatuses = map[string]bool{
+	"killed":   true,
+	"survived": true,
+	"untested": true,
+	"invalid":  true,
+	"timeout":  true,
+	"error":    true,
+}
+
+// PruneResult summarizes a Prune.
+type PruneResult struct {
+	Kept    int
+	Stale   int // the file or the module's dependencies changed since the entry was written
+	Missing int // the file no longer exists
+	NoSite  int // written before entries recorded their site
+	Before  LogStats
+	After   LogStats
+}
+
+// Removed is the number of entries Prune dropped.
+func (r PruneResult) Removed() int {
+	return r.Stale + r.Missing + r.NoSite
+}
+
+// Prune drops every entry that can never be hit again: its file is gone, or
+// the code it was keyed on changed (the enclosing function for mutants
+// inside one, the whole file otherwise). Entry paths resolve against the
+// module enclosing projectDir. The log is compacted afterwards.
+func Prune(b *LocalBackend, projectDir string) (PruneResult, error) {
+	var res PruneResult
+	abs, err := filepath.Abs(projectDir)
+	if err != nil {
+		return res, err
+	}
+	root, _ := moduleIdentity(abs)
+	entries, before, err := b.replay()
+	if err != nil {
+		return res, err
+	}
+	res.Before = before
+
+	tree := newTreeState(root)
+	var drop []string
+	for _, k := range sortedKeys(entries) {
+		switch tree.check(entries[k]) {
+		case siteNone:
+			res.NoSite++
+			drop = append(drop, k)
+		case siteMissing:
+			res.Missing++
+			drop = append(drop, k)
+		case siteStale:
+			res.Stale++
+			drop = append(drop, k)
+		default:
+			res.Kept++
+		}
+	}
+
+	if err := b.Delete(drop); err != nil {
+		return res, err
+	}
+	res.After, err = b.Compact()
+	return res, err
+}
+
+// CurrentEntries returns the entries whose code is unchanged in the module
+// enclosing projectDir: the results a run there would reuse.
+func CurrentEntries(b *LocalBackend, projectDir string) (map[string]Entry, error) {
+	abs, err := filepath.Abs(projectDir)
+	if err != nil {
+		return nil, err
+	}
+	root, _ := moduleIdentity(abs)
+	entries, err := b.All()
+	if err != nil {
+		return nil, err
+	}
+	tree := newTreeState(root)
+	current := make(map[string]Entry, len(entries))
+	for k, e := range entries {
+		if tree.check(e) == siteCurrent {
+			current[k] = e
+		}
+	}
+	return current, nil
+}
+
+// siteCheck is how an entry's recorded site relates to the tree.
+type siteCheck int
+
+const (
+	siteCurrent siteCheck = iota // the code it was keyed on is unchanged
+	siteNone                     // written before entries recorded their site
+	siteMissing                  // the file no longer exists
+	siteStale                    // the file or the dependencies changed since the entry was written
+)
+
+// treeState checks entries against the source files under root, hashing
+// each file once.
+type treeState struct {
+	root  string
+	deps  string // DepsHash of the module as it is now
+	files map[string]*sourceState
+}
+
+func newTreeState(root string) *treeState {
+	t := &treeState{root: root, files: make(map[string]*sourceState)}
+	if _, err := os.Stat(filepath.Join(root, "go.mod")); err == nil {
+		t.deps = DepsHash(root)
+	}
+	return t
+}
+
+func (t *treeState) check(e Entry) siteCheck {
+	if !e.HasSite() {
+		return siteNone
+	}
+	src, ok := t.files[e.File]
+	if !ok {
+		src = &sourceState{path: filepath.Join(t.root, filepath.FromSlash(e.File))}
+		src.hash, _ = hashFile(src.path)
+		t.files[e.File] = src
+	}
+	switch {
+	case src.hash == "":
+		return siteMissing
+	case e.DepsHash != t.deps:
+		// Keyed on go.mod and go.sum as they were; a run never looks it up.
+		return siteStale
+	case src.hash == e.FileHash:
+		return siteCurrent
+	case e.Func != "" && src.hasFunc(e.Func, e.FuncHash):
+		return siteCurrent
+	}
+	return siteStale
+}
+
+// sourceState is the current state of one source file, parsed lazily.
+type sourceState struct {
+	path  string
+	hash  string
+	funcs map[string]map[string]bool // FuncIdentity -> FuncHashes
+}
+
+// hasFunc reports whether the file declares fn with hash. A file may
+// declare several functions of one identity (init, _), so each keeps all
+// of its hashes.
+func (s *sourceState) hasFunc(fn, hash string) bool {
+	if s.funcs == nil {
+		s.funcs = make(map[string]map[string]bool)
+		f, err := parser.ParseFile(token.NewFileSet(), s.path, nil, parser.SkipObjectResolution)
+		if err != nil {
+			return false
+		}
+		for _, d := range f.Decls {
+			if fd, ok := d.(*ast.FuncDecl); ok {
+				id := engine.FuncIdentity(fd)
+				if s.funcs[id] == nil {
+					s.funcs[id] = make(map[string]bool)
+				}
+				s.funcs[id][engine.FuncHash(fd)] = true
+			}
+		}
+	}
+	return s.funcs[fn][hash]
+}
+
+// Problem is one inconsistency found by Verify.
+type Problem struct {
+	Key    string
+	Reason string
+}
+
+// VerifyResult summarizes a Verify.
+type VerifyResult struct {
+	Stats    LogStats
+	Checked  int
+	NoSite   int
+	Problems []Problem
+}
+
+// Verify checks every entry for a well-formed key, a known status and, when
+// the entry records its site, a key that matches the site it claims. Lines
+// that failed to parse are reported through Stats.Corrupt.
+func Verify(b *LocalBackend) (VerifyResult, error) {
+	var res VerifyResult
+	entries, stats, err := b.replay()
+	if err != nil {
+		return res, err
+	}
+	res.Stats = stats
+
+	for _, k := range sortedKeys(entries) {
+		e := entries[k]
+		res.Checked++
+		if !ValidKey(k) {
+			res.Problems = append(res.Problems, Problem{Key: k, Reason: "malformed key"})
+			continue
+		}
+		if !knownStatuses[e.Status] {
+			res.Problems = append(res.Problems, Problem{Key: k, Reason: "unknown status " + `"` + e.Status + `"`})
+		}
+		if !e.HasSite() {
+			res.NoSite++
+			continue
+		}
+		if e.SiteKey() != k {
+			res.Problems = append(res.Problems, Problem{Key: k, Reason: "key does not match recorded site " + e.File})
+		}
+	}
+	return res, nil
+}
+
+// DepsHash fingerprints the dependencies of the module rooted at modDir,
+// its go.mod and go.sum, as recorded in Entry.DepsHash.
+func DepsHash(modDir string) string {
+	h := sha256.New()
+	for _, name := range []string{"go.mod", "go.sum"} {
+		sum, _ := hashFile(filepath.Join(modDir, name))
+		io.WriteString(h, name+" "+sum+"\n")
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// hashFile returns the hex sha256 of a file, as used for cache keys.
+func hashFile(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
@@ -0,0 +1,350 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+
+	"github.com/aclfe/gorgon/internal/cache"
+)
+
+// RunCache implements `gorgon cache <command>`.
+func RunCache(args []string, stdout io.Writer) error {
+	if len(args) == 0 {
+		printCacheUsage()
+		return errors.New("missing cache command")
+	}
+	switch args[0] {
+	case "stats":
+		return runCacheStats(args[1:], stdout)
+	case "list":
+		return runCacheList(args[1:], stdout)
+	case "prune":
+		return runCachePrune(args[1:], stdout)
+	case "clear":
+		return runCacheClear(args[1:], stdout)
+	case "verify":
+		return runCacheVerify(args[1:], stdout)
+	case "export":
+		return runCacheExport(args[1:], stdout)
+	case "import":
+		return runCacheImport(args[1:], stdout)
+	case "serve":
+		return runCacheServe(args[1:], stdout)
+	case "help", "-h", "-help", "--help":
+		printCacheUsage()
+		return nil
+	default:
+		printCacheUsage()
+		return fmt.Errorf("unknown cache command %q", args[0])
+	}
+}
+
+func printCacheUsage() {
+	fmt.Fprintln(os.Stderr, "Usage: gorgon cache <command> [flags]")
+	fmt.Fprintln(os.Stderr, "")
+	fmt.Fprintln(os.Stderr, "Commands:")
+	fmt.Fprintln(os.Stderr, "  stats [dir]                   summarize the project's cached results and binaries")
+	fmt.Fprintln(os.Stderr, "  list [-status s] [-json] [dir] list cached results")
+	fmt.Fprintln(os.Stderr, "  prune [dir]                   drop results for code that has since changed, then compact")
+	fmt.Fprintln(os.Stderr, "  clear [-binaries] [dir]       delete the project's cached results (and all binaries)")
+	fmt.Fprintln(os.Stderr, "  verify [dir]                  check cached results for corruption; exits 1 on problems")
+	fmt.Fprintln(os.Stderr, "  export -o file.tar.gz [dir]   write the project's cached results to a tarball")
+	fmt.Fprintln(os.Stderr, "  import file.tar.gz [dir]      merge a tarball into the project's cache")
+	fmt.Fprintln(os.Stderr, "  serve -root dir [-addr addr]  run a shared HTTP cache server (GET/PUT /<key>)")
+}
+
+// projectArg returns the optional trailing project directory argument.
+func projectArg(fs *flag.FlagSet, skip int) string {
+	if fs.NArg() > skip {
+		return fs.Arg(skip)
+	}
+	return "."
+}
+
+func runCacheExport(args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("gorgon cache export", flag.ContinueOnError)
+	out := fs.String("o", "gorgon-cache.tar.gz", "Output tarball")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	local, err := cache.NewLocalBackend(projectArg(fs, 0))
+	if err != nil {
+		return err
+	}
+	f, err := os.Create(*out)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", *out, err)
+	}
+	n, err := cache.Export(local, f)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(stdout, "Exported %d entries from %s to %s\n", n, local.Location(), *out)
+	return nil
+}
+
+func runCacheImport(args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("gorgon cache import", flag.ContinueOnError)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		return errors.New("usage: gorgon cache import file.tar.gz [dir]")
+	}
+	local, err := cache.NewLocalBackend(projectArg(fs, 1))
+	if err != nil {
+		return err
+	}
+	f, err := os.Open(fs.Arg(0))
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", fs.Arg(0), err)
+	}
+	defer f.Close()
+	n, err := cache.Import(f, local)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(stdout, "Imported %d entries into %s\n", n, local.Location())
+	return nil
+}
+
+func runCacheServe(args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("gorgon cache serve", flag.ContinueOnError)
+	addr := fs.String("addr", "127.0.0.1:7070", "Listen address")
+	root := fs.String("root", "", "Directory holding cache entries")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *root == "" {
+		return errors.New("gorgon cache serve: -root is required")
+	}
+	store, err := cache.NewDirBackend(*root)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(stdout, "Serving gorgon cache from %s on http://%s\n", *root, *addr)
+	return http.ListenAndServe(*addr, cache.Handler(store))
+}
+
+func runCacheStats(args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("gorgon cache stats", flag.ContinueOnError)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	local, err := cache.NewLocalBackend(projectArg(fs, 0))
+	if err != nil {
+		return err
+	}
+	entries, err := local.All()
+	if err != nil {
+		return err
+	}
+	stats, err := local.Stats()
+	if err != nil {
+		return err
+	}
+
+	byStatus := make(map[string]int)
+	var noSite int
+	for _, e := range entries {
+		byStatus[e.Status]++
+		if !e.HasSite() {
+			noSite++
+		}
+	}
+	statuses := make([]string, 0, len(byStatus))
+	for s := range byStatus {
+		statuses = append(statuses, s)
+	}
+	sort.Strings(statuses)
+
+	fmt.Fprintf(stdout, "Results: %s\n", local.Location())
+	fmt.Fprintf(stdout, "  entries:  %d\n", len(entries))
+	for _, s := range statuses {
+		fmt.Fprintf(stdout, "    %-10s %d\n", s+":", byStatus[s])
+	}
+	if noSite > 0 {
+		fmt.Fprintf(stdout, "  without site (pruned by `gorgon cache prune`): %d\n", noSite)
+	}
+	fmt.Fprintf(stdout, "  size:     %s (%d records, %d superseded", formatBytes(stats.Bytes), stats.Records, stats.Garbage())
+	if stats.Corrupt > 0 {
+		fmt.Fprintf(stdout, ", %d corrupt", stats.Corrupt)
+	}
+	fmt.Fprintln(stdout, ")")
+
+	bins, err := cache.OpenBinaryStore(0)
+	if err != nil {
+		return err
+	}
+	n, size, err := bins.Usage()
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(stdout, "Binaries: %s\n", bins.Dir())
+	fmt.Fprintf(stdout, "  entries:  %d\n", n)
+	fmt.Fprintf(stdout, "  size:     %s\n", formatBytes(size))
+	return nil
+}
+
+// cacheListItem is one row of `gorgon cache list -json`.
+type cacheListItem struct {
+	Key string `json:"key"`
+	cache.Entry
+}
+
+func runCacheList(args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("gorgon cache list", flag.ContinueOnError)
+	status := fs.String("status", "", "Only list entries with this status")
+	asJSON := fs.Bool("json", false, "Print entries as JSON")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	local, err := cache.NewLocalBackend(projectArg(fs, 0))
+	if err != nil {
+		return err
+	}
+	entries, err := local.All()
+	if err != nil {
+		return err
+	}
+
+	items := make([]cacheListItem, 0, len(entries))
+	for k, e := range entries {
+		if *status != "" && e.Status != *status {
+			continue
+		}
+		items = append(items, cacheListItem{Key: k, Entry: e})
+	}
+	sort.Slice(items, func(i, j int) bool {
+		a, b := items[i], items[j]
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		if a.Column != b.Column {
+			return a.Column < b.Column
+		}
+		if a.Operator != b.Operator {
+			return a.Operator < b.Operator
+		}
+		return a.Key < b.Key
+	})
+
+	if *asJSON {
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(items)
+	}
+	for _, it := range items {
+		site := "(no site)"
+		if it.HasSite() {
+			site = fmt.Sprintf("%s:%d:%d", it.File, it.Line, it.Column)
+		}
+		line := fmt.Sprintf("%s  %-8s  %s  %s", it.Key[:12], it.Status, site, it.Operator)
+		if it.KilledBy != "" {
+			line += "  " + it.KilledBy
+		}
+		fmt.Fprintln(stdout, line)
+	}
+	return nil
+}
+
+func runCachePrune(args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("gorgon cache prune", flag.ContinueOnError)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	dir := projectArg(fs, 0)
+	local, err := cache.NewLocalBackend(dir)
+	if err != nil {
+		return err
+	}
+	res, err := cache.Prune(local, dir)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(stdout, "Pruned %d of %d entries (%d changed, %d deleted files, %d without site)\n",
+		res.Removed(), res.Kept+res.Removed(), res.Stale, res.Missing, res.NoSite)
+	fmt.Fprintf(stdout, "Compacted %s: %s -> %s\n", local.Location(), formatBytes(res.Before.Bytes), formatBytes(res.After.Bytes))
+	return nil
+}
+
+func runCacheClear(args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("gorgon cache clear", flag.ContinueOnError)
+	binaries := fs.Bool("binaries", false, "Also clear the compiled test binary store (shared by all projects)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	local, err := cache.NewLocalBackend(projectArg(fs, 0))
+	if err != nil {
+		return err
+	}
+	if err := local.Clear(); err != nil {
+		return err
+	}
+	fmt.Fprintf(stdout, "Cleared %s\n", local.Location())
+	if *binaries {
+		bins, err := cache.OpenBinaryStore(0)
+		if err != nil {
+			return err
+		}
+		if err := bins.Clear(); err != nil {
+			return err
+		}
+		fmt.Fprintf(stdout, "Cleared %s\n", bins.Dir())
+	}
+	return nil
+}
+
+func runCacheVerify(args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("gorgon cache verify", flag.ContinueOnError)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	local, err := cache.NewLocalBackend(projectArg(fs, 0))
+	if err != nil {
+		return err
+	}
+	res, err := cache.Verify(local)
+	if err != nil {
+		return err
+	}
+	for _, p := range res.Problems {
+		fmt.Fprintf(stdout, "%s: %s\n", p.Key, p.Reason)
+	}
+	if res.Stats.Corrupt > 0 {
+		fmt.Fprintf(stdout, "%d unreadable records (skipped on load; removed by `gorgon cache prune`)\n", res.Stats.Corrupt)
+	}
+	fmt.Fprintf(stdout, "Verified %d entries in %s: %d problems", res.Checked, local.Location(), len(res.Problems))
+	if res.NoSite > 0 {
+		fmt.Fprintf(stdout, ", %d without site", res.NoSite)
+	}
+	fmt.Fprintln(stdout)
+	if len(res.Problems) > 0 || res.Stats.Corrupt > 0 {
+		return fmt.Errorf("cache verification failed")
+	}
+	return nil
+}
+
+func formatBytes(n int64) string {
+	switch {
+	case n >= 1<<30:
+		return fmt.Sprintf("%.1f GiB", float64(n)/(1<<30))
+	case n >= 1<<20:
+		return fmt.Sprintf("%.1f MiB", float64(n)/(1<<20))
+	case n >= 1<<10:
+		return fmt.Sprintf("%.1f KiB", float64(n)/(1<<10))
+	}
+	return fmt.Sprintf("%d B", n)
+}
@@ -27,6 +27,7 @@ type Flags struct {
 	ShowKilled   bool
 	ShowSurvived bool
 	Diff         string
+	Mutant       string // fingerprint of the only mutant to run
 	Targets      []string
 }
 
@@ -48,6 +49,7 @@ func Parse(args []string) (*Flags, error) {
 	fs.BoolVar(&f.ShowKilled, "show-killed", false, "Show killed mutants with test attribution")
 	fs.BoolVar(&f.ShowSurvived, "show-survived", false, "Show survived mutants in output")
 	fs.StringVar(&f.MemProfile, "mem-profile", "", "Write periodic heap profiles to this directory (e.g. profiles)")
+	fs.StringVar(&f.Mutant, "mutant", "", "Re-run only the mutant with this fingerprint; no reports are written (may be used with -config)")
 
 	if err := fs.Parse(args); err != nil {
 		return nil, err
@@ -139,6 +141,10 @@ func ParseConcurrent(val string) int {
 
 func PrintUsage() {
 	fmt.Fprintln(os.Stderr, "Usage: gorgon [flags] <path>")
+	fmt.Fprintln(os.Stderr, "       gorgon cache <command>   (see gorgon cache help)")
+	fmt.Fprintln(os.Stderr, "       gorgon report --from results.json [flags]")
+	fmt.Fprintln(os.Stderr, "       gorgon compare [flags] <old> <new>")
+	fmt.Fprintln(os.Stderr, "       gorgon tui --from results.json [flags] [path...]")
 	fmt.Fprintln(os.Stderr, "")
 	fmt.Fprintln(os.Stderr, "  <path>   File or directory to mutate (e.g. examples/mutations)")
 	fmt.Fprintln(os.Stderr, "")
@@ -156,6 +162,7 @@ func PrintUsage() {
 	fmt.Fprintln(os.Stderr, "  -show-killed          show killed mutants with test attribution")
 	fmt.Fprintln(os.Stderr, "  -show-survived        show survived mutants in output")
 	fmt.Fprintln(os.Stderr, "  -mem-profile string  write periodic heap profiles to this directory (e.g. profiles)")
+	fmt.Fprintln(os.Stderr, "  -mutant string        re-run only the mutant with this fingerprint (may be used with -config)")
 	fmt.Fprintln(os.Stderr, "")
 	fmt.Fprintln(os.Stderr, "Examples:")
 	fmt.Fprintln(os.Stderr, "  gorgon examples/mutations")
@@ -0,0 +1,89 @@
+package cli
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/aclfe/gorgon/internal/compare"
+)
+
+// RunCompare implements `gorgon compare OLD NEW`: it matches the mutants of
+// two runs by fingerprint and reports what changed between them. Each run is
+// a JSON report, or "cache" / "cache:<dir>" for the results a cached run of
+// that project would report now.
+func RunCompare(args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("gorgon compare", flag.ContinueOnError)
+	format := fs.String("format", "text", "Output format: "+strings.Join(compare.Formats, ", "))
+	output := fs.String("o", "", "Write the comparison to this file instead of stdout")
+	failOnRegression := fs.Bool("fail-on-regression", false, "Exit non-zero when a mutant survives that did not survive before")
+	fs.Usage = printCompareUsage
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 2 {
+		printCompareUsage()
+		return errors.New("gorgon compare: expected two runs to compare")
+	}
+
+	old, err := loadRun(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	cur, err := loadRun(fs.Arg(1))
+	if err != nil {
+		return err
+	}
+	for _, run := range []*compare.Run{old, cur} {
+		if run.Unidentified > 0 {
+			fmt.Fprintf(os.Stderr, "Warning: %d cached results in %s predate fingerprints and were skipped\n", run.Unidentified, run.Source)
+		}
+	}
+
+	res := compare.Compare(old, cur)
+	w := stdout
+	if *output != "" {
+		f, err := os.Create(*output)
+		if err != nil {
+			return fmt.Errorf("failed to create %s: %w", *output, err)
+		}
+		defer f.Close()
+		w = f
+	}
+	if err := compare.Write(w, res, *format); err != nil {
+		return err
+	}
+
+	if regs := res.Regressions(); *failOnRegression && len(regs) > 0 {
+		return fmt.Errorf("%d mutant(s) survive in %s that did not survive in %s", len(regs), cur.Source, old.Source)
+	}
+	return nil
+}
+
+func loadRun(arg string) (*compare.Run, error) {
+	if arg == "cache" {
+		return compare.LoadCache(".")
+	}
+	if dir, ok := strings.CutPrefix(arg, "cache:"); ok {
+		return compare.LoadCache(dir)
+	}
+	return compare.LoadReport(arg)
+}
+
+func printCompareUsage() {
+	fmt.Fprintln(os.Stderr, "Usage: gorgon compare [flags] <old> <new>")
+	fmt.Fprintln(os.Stderr, "")
+	fmt.Fprintln(os.Stderr, "Compares two runs mutant by mutant. Each run is a JSON report (written by a")
+	fmt.Fprintln(os.Stderr, "json: output), or cache / cache:<dir> for the current cached results.")
+	fmt.Fprintln(os.Stderr, "")
+	fmt.Fprintln(os.Stderr, "Flags:")
+	fmt.Fprintln(os.Stderr, "  -format string         text (default), markdown or json")
+	fmt.Fprintln(os.Stderr, "  -o string              write to this file instead of stdout")
+	fmt.Fprintln(os.Stderr, "  -fail-on-regression    exit 1 when a mutant survives that did not before")
+	fmt.Fprintln(os.Stderr, "")
+	fmt.Fprintln(os.Stderr, "Example:")
+	fmt.Fprintln(os.Stderr, "  gorgon compare -format markdown -fail-on-regression main.json pr.json")
+}
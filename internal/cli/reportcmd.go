@@ -0,0 +1,126 @@
+package cli
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/aclfe/gorgon/internal/reporter"
+	"github.com/aclfe/gorgon/internal/subconfig"
+	"github.com/aclfe/gorgon/pkg/config"
+)
+
+// outputList collects repeated -o format:file flags.
+type outputList []string
+
+func (o *outputList) String() string { return strings.Join(*o, ",") }
+
+func (o *outputList) Set(v string) error {
+	if !strings.Contains(v, ":") {
+		return fmt.Errorf("output %q must be format:file", v)
+	}
+	*o = append(*o, v)
+	return nil
+}
+
+// RunReport implements `gorgon report --from results.json`: it renders
+// reports from a saved JSON result without re-running any tests, and fails
+// like a run would when the score is below the threshold.
+func RunReport(args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("gorgon report", flag.ContinueOnError)
+	from := fs.String("from", "", "JSON report to render (written by a json: output)")
+	configFile := fs.String("config", "", "Config whose outputs, threshold, show_* and sarif settings apply")
+	var outputs outputList
+	fs.Var(&outputs, "o", "Output as format:file, repeatable (default: the config's outputs)")
+	threshold := fs.Float64("threshold", 0, "Minimum mutation score (default: the config's, else the one recorded in the report)")
+	showKilled := fs.Bool("show-killed", false, "Show killed mutants with test attribution")
+	showSurvived := fs.Bool("show-survived", false, "Show survived mutants in output")
+	fs.Usage = printReportUsage
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *from == "" {
+		printReportUsage()
+		return errors.New("gorgon report: --from is required")
+	}
+
+	res, err := reporter.LoadJSONReport(*from)
+	if err != nil {
+		return err
+	}
+
+	cfg := config.Default()
+	cfg.Threshold = res.Threshold
+	if *configFile != "" {
+		cfg, err = config.Load(*configFile)
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+		if err := cfg.Validate(); err != nil {
+			return fmt.Errorf("invalid config: %w", err)
+		}
+		// A config without threshold: loads as 0, which would turn the
+		// check off; the report's own threshold applies instead.
+		if cfg.Threshold == 0 {
+			cfg.Threshold = res.Threshold
+		}
+	}
+	fs.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "threshold":
+			cfg.Threshold = *threshold
+		case "show-killed":
+			cfg.ShowKilled = *showKilled
+		case "show-survived":
+			cfg.ShowSurvived = *showSurvived
+		}
+	})
+	if len(outputs) > 0 {
+		cfg.Outputs = outputs
+	}
+
+	// Files were restored under the working directory when the recorded
+	// root does not exist here.
+	root := res.ProjectRoot
+	if info, err := os.Stat(root); root == "" || err != nil || !info.IsDir() {
+		root, _ = os.Getwd()
+	}
+	var resolver *subconfig.Resolver
+	if r, err := subconfig.Discover(root, *configFile); err == nil {
+		resolver = r
+	}
+
+	fmt.Fprintf(stdout, "Rendering %d mutants from %s\n", len(res.Mutants), *from)
+	_, err = reporter.Report(res.Mutants, res.Total, cfg.Threshold, resolver, false, cfg.ShowKilled, cfg.ShowSurvived, "", "", "textfile", reporter.BaselineOptions{
+		Dir:          ".",
+		File:         cfg.Baseline.File,
+		MultiOutputs: cfg.Outputs,
+		ProjectRoot:  root,
+		SARIF: reporter.SARIFOptions{
+			TimeoutLevel:  cfg.SARIF.TimeoutLevel,
+			UntestedLevel: cfg.SARIF.UntestedLevel,
+		},
+		History: reporter.HistoryOptions{File: cfg.History.File},
+	})
+	return err
+}
+
+func printReportUsage() {
+	fmt.Fprintln(os.Stderr, "Usage: gorgon report --from results.json [flags]")
+	fmt.Fprintln(os.Stderr, "")
+	fmt.Fprintln(os.Stderr, "Renders reports from a JSON result without re-running tests.")
+	fmt.Fprintln(os.Stderr, "")
+	fmt.Fprintln(os.Stderr, "Flags:")
+	fmt.Fprintln(os.Stderr, "  -from string        JSON report to render (written by a json: output)")
+	fmt.Fprintln(os.Stderr, "  -config string      config whose outputs, threshold, show_* and sarif settings apply")
+	fmt.Fprintln(os.Stderr, "  -o format:file      output to write, repeatable (default: the config's outputs)")
+	fmt.Fprintln(os.Stderr, "  -threshold float    minimum mutation score (default: config, else the report's)")
+	fmt.Fprintln(os.Stderr, "  -show-killed        show killed mutants with test attribution")
+	fmt.Fprintln(os.Stderr, "  -show-survived      show survived mutants in output")
+	fmt.Fprintln(os.Stderr, "")
+	fmt.Fprintln(os.Stderr, "Example:")
+	fmt.Fprintln(os.Stderr, "  gorgon report --from results.json -o html:gorgon-report -o sarif:results.sarif")
+}
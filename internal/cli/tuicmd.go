@@ -0,0 +1,78 @@
+package cli
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/aclfe/gorgon/internal/reporter"
+	"github.com/aclfe/gorgon/internal/tui"
+)
+
+// RunTUI implements `gorgon tui --from results.json [path...]`: it opens an
+// interactive browser over a saved JSON result for triaging survivors. The
+// paths are what a re-run mutates, the report's project root by default.
+func RunTUI(args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("gorgon tui", flag.ContinueOnError)
+	from := fs.String("from", "", "JSON report to browse (written by a json: output)")
+	configFile := fs.String("config", "", "Config that accepted mutants are suppressed in and re-runs use")
+	fs.Usage = printTUIUsage
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *from == "" {
+		printTUIUsage()
+		return errors.New("gorgon tui: --from is required")
+	}
+
+	res, err := reporter.LoadJSONReport(*from)
+	if err != nil {
+		return err
+	}
+
+	root := res.ProjectRoot
+	if info, err := os.Stat(root); root == "" || err != nil || !info.IsDir() {
+		root, _ = os.Getwd()
+	}
+	targets := fs.Args()
+	if len(targets) == 0 {
+		targets = []string{root}
+	}
+
+	accepted, err := tui.Run(tui.Options{
+		Mutants:    res.Mutants,
+		Root:       root,
+		Source:     *from,
+		ConfigFile: *configFile,
+		Targets:    targets,
+	})
+	if accepted > 0 {
+		fmt.Fprintf(stdout, "Suppressed %d mutant(s) in %s\n", accepted, *configFile)
+	}
+	return err
+}
+
+func printTUIUsage() {
+	fmt.Fprintln(os.Stderr, "Usage: gorgon tui --from results.json [flags] [path...]")
+	fmt.Fprintln(os.Stderr, "")
+	fmt.Fprintln(os.Stderr, "Browses a JSON result in the terminal to triage survivors.")
+	fmt.Fprintln(os.Stderr, "")
+	fmt.Fprintln(os.Stderr, "Flags:")
+	fmt.Fprintln(os.Stderr, "  -from string      JSON report to browse (written by a json: output)")
+	fmt.Fprintln(os.Stderr, "  -config string    config that accepted mutants are suppressed in and re-runs use")
+	fmt.Fprintln(os.Stderr, "")
+	fmt.Fprintln(os.Stderr, "  [path...]         what a re-run mutates (default: the report's project root)")
+	fmt.Fprintln(os.Stderr, "")
+	fmt.Fprintln(os.Stderr, "Keys:")
+	fmt.Fprintln(os.Stderr, "  ↑/↓ j/k           move            ⏎/→ open, ← close")
+	fmt.Fprintln(os.Stderr, "  t                 list every mutant or only survivors")
+	fmt.Fprintln(os.Stderr, "  a                 accept the mutant: suppress it in -config with a reason")
+	fmt.Fprintln(os.Stderr, "  e                 open the mutant in $VISUAL or $EDITOR")
+	fmt.Fprintln(os.Stderr, "  r                 re-run the mutant alone (gorgon -mutant)")
+	fmt.Fprintln(os.Stderr, "  q                 quit")
+	fmt.Fprintln(os.Stderr, "")
+	fmt.Fprintln(os.Stderr, "Example:")
+	fmt.Fprintln(os.Stderr, "  gorgon tui --from results.json -config gorgon.yml")
+}
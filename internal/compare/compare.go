@@ -0,0 +1,323 @@
+// Package compare diffs two mutation runs mutant by mutant, matching
+// mutants by fingerprint so that edits elsewhere in the tree do not show up
+// as changes.
+package compare
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/aclfe/gorgon/internal/cache"
+	testing "github.com/aclfe/gorgon/internal/core"
+	"github.com/aclfe/gorgon/internal/reporter"
+)
+
+// Mutant is the part of a mutant's outcome a comparison looks at.
+type Mutant struct {
+	Fingerprint string `json:"fingerprint"`
+	File        string `json:"file"` // slash-separated, relative to the project root
+	Line        int    `json:"line"`
+	Column      int    `json:"column"`
+	Operator    string `json:"operator"`
+	Status      string `json:"status"`
+	KilledBy    string `json:"killed_by,omitempty"`
+}
+
+func (m Mutant) String() string {
+	return fmt.Sprintf("%s:%d:%d %s [%s]", m.File, m.Line, m.Column, m.Operator, m.Fingerprint)
+}
+
+// Run is one side of a comparison.
+type Run struct {
+	Source  string
+	Mutants []Mutant
+	// Unidentified counts cached results written before the cache recorded
+	// fingerprints; they cannot be matched and are left out.
+	Unidentified int
+}
+
+// LoadReport reads a run from a JSON report.
+func LoadReport(path string) (*Run, error) {
+	res, err := reporter.LoadJSONReport(path)
+	if err != nil {
+		return nil, err
+	}
+	cwd, _ := os.Getwd()
+	run := &Run{Source: path, Mutants: make([]Mutant, 0, len(res.Mutants))}
+	for _, m := range res.Mutants {
+		run.Mutants = append(run.Mutants, Mutant{
+			Fingerprint: m.Fingerprint,
+			File:        relativePath(m.Site.File.Name(), res.ProjectRoot, cwd),
+			Line:        m.Site.Line,
+			Column:      m.Site.Column,
+			Operator:    m.Operator.Name(),
+			Status:      m.Status,
+			KilledBy:    m.KilledBy,
+		})
+	}
+	sortMutants(run.Mutants)
+	return run, nil
+}
+
+// relativePath makes a restored file relative to the project root it was
+// recorded under, or to the working directory it was relocated to.
+func relativePath(path string, roots ...string) string {
+	for _, root := range roots {
+		if root == "" {
+			continue
+		}
+		if r, err := filepath.Rel(root, path); err == nil && !strings.HasPrefix(r, "..") {
+			return filepath.ToSlash(r)
+		}
+	}
+	return filepath.ToSlash(path)
+}
+
+// LoadCache reads the cached results that are still current for the
+// project in dir, i.e. the outcome a cached run there would report.
+func LoadCache(dir string) (*Run, error) {
+	local, err := cache.NewLocalBackend(dir)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := cache.CurrentEntries(local, dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cache %s: %w", local.Location(), err)
+	}
+	run := &Run{Source: "cache " + local.Location()}
+	for _, e := range entries {
+		if e.Fingerprint == "" {
+			run.Unidentified++
+			continue
+		}
+		run.Mutants = append(run.Mutants, Mutant{
+			Fingerprint: e.Fingerprint,
+			File:        e.File,
+			Line:        e.Line,
+			Column:      e.Column,
+			Operator:    e.Operator,
+			Status:      e.Status,
+			KilledBy:    e.KilledBy,
+		})
+	}
+	sortMutants(run.Mutants)
+	return run, nil
+}
+
+// Change is a mutant whose status differs between the runs. Mutant is as
+// it is in the new run.
+type Change struct {
+	Mutant
+	From string `json:"from"`
+}
+
+// ScoreDelta compares the score of a group of mutants, a package or an
+// operator, between the runs.
+type ScoreDelta struct {
+	Name       string  `json:"name"`
+	OldScore   float64 `json:"old_score"`
+	NewScore   float64 `json:"new_score"`
+	Delta      float64 `json:"delta"`
+	OldMutants int     `json:"old_mutants"`
+	NewMutants int     `json:"new_mutants"`
+}
+
+// Changed reports whether anything about the group moved.
+func (d ScoreDelta) Changed() bool {
+	return d.OldScore != d.NewScore || d.OldMutants != d.NewMutants
+}
+
+// TestDelta is a test whose number of kills changed.
+type TestDelta struct {
+	Test string `json:"test"`
+	Old  int    `json:"old"`
+	New  int    `json:"new"`
+}
+
+// Result is the mutant-by-mutant difference between two runs.
+type Result struct {
+	Old            string       `json:"old"`
+	New            string       `json:"new"`
+	OldScore       float64      `json:"old_score"`
+	NewScore       float64      `json:"new_score"`
+	NewlySurviving []Change     `json:"newly_surviving"` // survive now, did not before
+	NewlyKilled    []Change     `json:"newly_killed"`    // killed now, were not before
+	OtherChanges   []Change     `json:"other_changes"`   // any other status change
+	Added          []Mutant     `json:"added"`           // only in the new run
+	Disappeared    []Mutant     `json:"disappeared"`     // only in the old run
+	Packages       []ScoreDelta `json:"packages"`
+	Operators      []ScoreDelta `json:"operators"`
+	Tests          []TestDelta  `json:"tests"`
+}
+
+// Compare matches the mutants of two runs by fingerprint.
+func Compare(old, cur *Run) *Result {
+	res := &Result{
+		Old:            old.Source,
+		New:            cur.Source,
+		OldScore:       score(old.Mutants),
+		NewScore:       score(cur.Mutants),
+		NewlySurviving: []Change{},
+		NewlyKilled:    []Change{},
+		OtherChanges:   []Change{},
+		Added:          []Mutant{},
+		Disappeared:    []Mutant{},
+	}
+
+	before := make(map[string]Mutant, len(old.Mutants))
+	for _, m := range old.Mutants {
+		before[m.Fingerprint] = m
+	}
+	seen := make(map[string]bool, len(cur.Mutants))
+	for _, m := range cur.Mutants {
+		seen[m.Fingerprint] = true
+		prev, ok := before[m.Fingerprint]
+		switch {
+		case !ok:
+			res.Added = append(res.Added, m)
+		case prev.Status == m.Status:
+		case m.Status == testing.StatusSurvived:
+			res.NewlySurviving = append(res.NewlySurviving, Change{Mutant: m, From: prev.Status})
+		case m.Status == testing.StatusKilled:
+			res.NewlyKilled = append(res.NewlyKilled, Change{Mutant: m, From: prev.Status})
+		default:
+			res.OtherChanges = append(res.OtherChanges, Change{Mutant: m, From: prev.Status})
+		}
+	}
+	for _, m := range old.Mutants {
+		if !seen[m.Fingerprint] {
+			res.Disappeared = append(res.Disappeared, m)
+		}
+	}
+
+	res.Packages = scoreDeltas(old.Mutants, cur.Mutants, func(m Mutant) string { return filepath.ToSlash(filepath.Dir(m.File)) })
+	res.Operators = scoreDeltas(old.Mutants, cur.Mutants, func(m Mutant) string { return m.Operator })
+	res.Tests = testDeltas(old.Mutants, cur.Mutants)
+	return res
+}
+
+// Regressions are the mutants that survive in the new run but did not in
+// the old one, including survivors the old run did not have at all.
+func (r *Result) Regressions() []Mutant {
+	regs := make([]Mutant, 0, len(r.NewlySurviving))
+	for _, c := range r.NewlySurviving {
+		regs = append(regs, c.Mutant)
+	}
+	for _, m := range r.Added {
+		if m.Status == testing.StatusSurvived {
+			regs = append(regs, m)
+		}
+	}
+	sortMutants(regs)
+	return regs
+}
+
+func score(mutants []Mutant) float64 {
+	var killed, survived, untested, timeout int
+	for _, m := range mutants {
+		switch m.Status {
+		case testing.StatusKilled:
+			killed++
+		case testing.StatusSurvived:
+			survived++
+		case testing.StatusUntested:
+			untested++
+		case testing.StatusTimeout:
+			timeout++
+		}
+	}
+	return reporter.CalculateScore(killed, survived, untested, timeout)
+}
+
+// scoreDeltas groups both runs by key and compares the groups, largest
+// drop first.
+func scoreDeltas(old, cur []Mutant, key func(Mutant) string) []ScoreDelta {
+	group := func(mutants []Mutant) map[string][]Mutant {
+		g := make(map[string][]Mutant)
+		for _, m := range mutants {
+			g[key(m)] = append(g[key(m)], m)
+		}
+		return g
+	}
+	oldGroups, newGroups := group(old), group(cur)
+	names := make(map[string]bool)
+	for n := range oldGroups {
+		names[n] = true
+	}
+	for n := range newGroups {
+		names[n] = true
+	}
+
+	deltas := make([]ScoreDelta, 0, len(names))
+	for n := range names {
+		d := ScoreDelta{
+			Name:       n,
+			OldScore:   score(oldGroups[n]),
+			NewScore:   score(newGroups[n]),
+			OldMutants: len(oldGroups[n]),
+			NewMutants: len(newGroups[n]),
+		}
+		d.Delta = d.NewScore - d.OldScore
+		deltas = append(deltas, d)
+	}
+	sort.Slice(deltas, func(i, j int) bool {
+		if deltas[i].Delta != deltas[j].Delta {
+			return deltas[i].Delta < deltas[j].Delta
+		}
+		return deltas[i].Name < deltas[j].Name
+	})
+	return deltas
+}
+
+// testDeltas lists the tests whose number of kills changed, biggest loss
+// first.
+func testDeltas(old, cur []Mutant) []TestDelta {
+	kills := func(mutants []Mutant) map[string]int {
+		k := make(map[string]int)
+		for _, m := range mutants {
+			if m.Status == testing.StatusKilled && m.KilledBy != "" {
+				k[m.KilledBy]++
+			}
+		}
+		return k
+	}
+	oldKills, newKills := kills(old), kills(cur)
+	deltas := []TestDelta{}
+	for t, n := range oldKills {
+		if newKills[t] != n {
+			deltas = append(deltas, TestDelta{Test: t, Old: n, New: newKills[t]})
+		}
+	}
+	for t, n := range newKills {
+		if _, ok := oldKills[t]; !ok {
+			deltas = append(deltas, TestDelta{Test: t, New: n})
+		}
+	}
+	sort.Slice(deltas, func(i, j int) bool {
+		di, dj := deltas[i].New-deltas[i].Old, deltas[j].New-deltas[j].Old
+		if di != dj {
+			return di < dj
+		}
+		return deltas[i].Test < deltas[j].Test
+	})
+	return deltas
+}
+
+func sortMutants(mutants []Mutant) {
+	sort.SliceStable(mutants, func(i, j int) bool {
+		a, b := mutants[i], mutants[j]
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		if a.Column != b.Column {
+			return a.Column < b.Column
+		}
+		return a.Operator < b.Operator
+	})
+}
@@ -0,0 +1,118 @@
+//go:build unit
+// +build unit
+
+package compare
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	core "github.com/aclfe/gorgon/internal/core"
+)
+
+func mutant(fp, file, op, status, killedBy string) Mutant {
+	return Mutant{Fingerprint: fp, File: file, Line: 1, Column: 1, Operator: op, Status: status, KilledBy: killedBy}
+}
+
+func testRuns() (*Run, *Run) {
+	old := &Run{Source: "old.json", Mutants: []Mutant{
+		mutant("a", "calc/calc.go", "arithmetic_flip", core.StatusKilled, "TestAdd"),
+		mutant("b", "calc/calc.go", "arithmetic_flip", core.StatusSurvived, ""),
+		mutant("c", "calc/calc.go", "condition_negation", core.StatusKilled, "TestAdd"),
+		mutant("d", "util/util.go", "sign_toggle", core.StatusKilled, "TestUtil"),
+	}}
+	cur := &Run{Source: "new.json", Mutants: []Mutant{
+		mutant("a", "calc/calc.go", "arithmetic_flip", core.StatusSurvived, ""),
+		mutant("b", "calc/calc.go", "arithmetic_flip", core.StatusKilled, "TestSub"),
+		mutant("c", "calc/calc.go", "condition_negation", core.StatusKilled, "TestAdd"),
+		mutant("e", "util/util.go", "sign_toggle", core.StatusSurvived, ""),
+	}}
+	return old, cur
+}
+
+func TestCompare(t *testing.T) {
+	res := Compare(testRuns())
+
+	if len(res.NewlySurviving) != 1 || res.NewlySurviving[0].Fingerprint != "a" || res.NewlySurviving[0].From != core.StatusKilled {
+		t.Errorf("newly surviving = %+v", res.NewlySurviving)
+	}
+	if len(res.NewlyKilled) != 1 || res.NewlyKilled[0].Fingerprint != "b" {
+		t.Errorf("newly killed = %+v", res.NewlyKilled)
+	}
+	if len(res.Added) != 1 || res.Added[0].Fingerprint != "e" {
+		t.Errorf("added = %+v", res.Added)
+	}
+	if len(res.Disappeared) != 1 || res.Disappeared[0].Fingerprint != "d" {
+		t.Errorf("disappeared = %+v", res.Disappeared)
+	}
+	if res.OldScore != 75 || res.NewScore != 50 {
+		t.Errorf("scores = %v → %v", res.OldScore, res.NewScore)
+	}
+
+	regs := res.Regressions()
+	if len(regs) != 2 || regs[0].Fingerprint != "a" || regs[1].Fingerprint != "e" {
+		t.Errorf("regressions = %+v", regs)
+	}
+
+	if p := res.Packages[0]; p.Name != "util" || p.OldScore != 100 || p.NewScore != 0 {
+		t.Errorf("worst package first, got %+v", p)
+	}
+	for _, op := range res.Operators {
+		if op.Name == "condition_negation" && op.Changed() {
+			t.Errorf("unchanged operator reported as changed: %+v", op)
+		}
+	}
+
+	want := map[string][2]int{"TestAdd": {2, 1}, "TestUtil": {1, 0}, "TestSub": {0, 1}}
+	if len(res.Tests) != len(want) {
+		t.Fatalf("tests = %+v", res.Tests)
+	}
+	for _, td := range res.Tests {
+		if w := want[td.Test]; td.Old != w[0] || td.New != w[1] {
+			t.Errorf("%s: %d → %d, want %d → %d", td.Test, td.Old, td.New, w[0], w[1])
+		}
+	}
+}
+
+func TestWrite(t *testing.T) {
+	res := Compare(testRuns())
+
+	var text bytes.Buffer
+	if err := Write(&text, res, "text"); err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range []string{"75.00% → 50.00% (-25.00 pp)", "Newly surviving (1):", "calc/calc.go:1:1 arithmetic_flip [a]  killed → survived", "2 regression(s)"} {
+		if !strings.Contains(text.String(), s) {
+			t.Errorf("text output lacks %q:\n%s", s, text.String())
+		}
+	}
+	if strings.Contains(text.String(), "condition_negation") {
+		t.Errorf("text output lists an unchanged operator:\n%s", text.String())
+	}
+
+	var md bytes.Buffer
+	if err := Write(&md, res, "markdown"); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(md.String(), "| `util` | 100.00% | 0.00% | -100.00 | 1 → 1 |") {
+		t.Errorf("markdown output lacks the package row:\n%s", md.String())
+	}
+
+	var js bytes.Buffer
+	if err := Write(&js, res, "json"); err != nil {
+		t.Fatal(err)
+	}
+	var decoded Result
+	if err := json.Unmarshal(js.Bytes(), &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if len(decoded.NewlySurviving) != 1 || decoded.NewlySurviving[0].Fingerprint != "a" {
+		t.Errorf("json round trip lost the change: %+v", decoded.NewlySurviving)
+	}
+
+	if err := Write(&js, res, "xml"); err == nil {
+		t.Error("expected an error for an unknown format")
+	}
+}
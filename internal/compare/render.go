@@ -0,0 +1,153 @@
+package compare
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Formats lists the output formats Write accepts.
+var Formats = []string{"text", "markdown", "json"}
+
+// Write renders r in the named format.
+func Write(w io.Writer, r *Result, format string) error {
+	switch format {
+	case "text", "":
+		return writeText(w, r)
+	case "markdown", "md":
+		return writeMarkdown(w, r)
+	case "json":
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(r)
+	default:
+		return fmt.Errorf("unknown compare format %q (want one of %s)", format, strings.Join(Formats, ", "))
+	}
+}
+
+func scoreLine(oldScore, newScore float64) string {
+	return fmt.Sprintf("%.2f%% → %.2f%% (%+.2f pp)", oldScore, newScore, newScore-oldScore)
+}
+
+func changed(deltas []ScoreDelta) []ScoreDelta {
+	var out []ScoreDelta
+	for _, d := range deltas {
+		if d.Changed() {
+			out = append(out, d)
+		}
+	}
+	return out
+}
+
+func writeText(w io.Writer, r *Result) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Comparing %s → %s\n", r.Old, r.New)
+	fmt.Fprintf(&b, "Mutation score: %s\n", scoreLine(r.OldScore, r.NewScore))
+
+	changes := func(title string, cs []Change) {
+		if len(cs) == 0 {
+			return
+		}
+		fmt.Fprintf(&b, "\n%s (%d):\n", title, len(cs))
+		for _, c := range cs {
+			fmt.Fprintf(&b, "  %s  %s → %s\n", c.Mutant, c.From, c.Status)
+		}
+	}
+	mutants := func(title string, ms []Mutant) {
+		if len(ms) == 0 {
+			return
+		}
+		fmt.Fprintf(&b, "\n%s (%d):\n", title, len(ms))
+		for _, m := range ms {
+			fmt.Fprintf(&b, "  %s  %s\n", m, m.Status)
+		}
+	}
+	changes("Newly surviving", r.NewlySurviving)
+	changes("Newly killed", r.NewlyKilled)
+	changes("Other status changes", r.OtherChanges)
+	mutants("New mutants", r.Added)
+	mutants("Disappeared mutants", r.Disappeared)
+
+	deltas := func(title string, ds []ScoreDelta) {
+		ds = changed(ds)
+		if len(ds) == 0 {
+			return
+		}
+		fmt.Fprintf(&b, "\n%s:\n", title)
+		for _, d := range ds {
+			fmt.Fprintf(&b, "  %-32s %s  mutants %d → %d\n", d.Name, scoreLine(d.OldScore, d.NewScore), d.OldMutants, d.NewMutants)
+		}
+	}
+	deltas("Packages", r.Packages)
+	deltas("Operators", r.Operators)
+
+	if len(r.Tests) > 0 {
+		b.WriteString("\nTests with changed kill counts:\n")
+		for _, t := range r.Tests {
+			fmt.Fprintf(&b, "  %-32s %d → %d (%+d)\n", t.Test, t.Old, t.New, t.New-t.Old)
+		}
+	}
+
+	fmt.Fprintf(&b, "\n%d regression(s)\n", len(r.Regressions()))
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func writeMarkdown(w io.Writer, r *Result) error {
+	var b strings.Builder
+	b.WriteString("## Mutation testing comparison\n\n")
+	fmt.Fprintf(&b, "`%s` → `%s`\n\n", r.Old, r.New)
+	fmt.Fprintf(&b, "**Mutation score:** %s  \n", scoreLine(r.OldScore, r.NewScore))
+	fmt.Fprintf(&b, "**Regressions:** %d\n", len(r.Regressions()))
+
+	changes := func(title string, cs []Change) {
+		if len(cs) == 0 {
+			return
+		}
+		fmt.Fprintf(&b, "\n### %s (%d)\n\n", title, len(cs))
+		b.WriteString("| Location | Operator | Status | Fingerprint |\n|---|---|---|---|\n")
+		for _, c := range cs {
+			fmt.Fprintf(&b, "| `%s:%d:%d` | %s | %s → %s | `%s` |\n", c.File, c.Line, c.Column, c.Operator, c.From, c.Status, c.Fingerprint)
+		}
+	}
+	mutants := func(title string, ms []Mutant) {
+		if len(ms) == 0 {
+			return
+		}
+		fmt.Fprintf(&b, "\n<details><summary>%s (%d)</summary>\n\n", title, len(ms))
+		b.WriteString("| Location | Operator | Status | Fingerprint |\n|---|---|---|---|\n")
+		for _, m := range ms {
+			fmt.Fprintf(&b, "| `%s:%d:%d` | %s | %s | `%s` |\n", m.File, m.Line, m.Column, m.Operator, m.Status, m.Fingerprint)
+		}
+		b.WriteString("\n</details>\n")
+	}
+	changes("Newly surviving", r.NewlySurviving)
+	changes("Newly killed", r.NewlyKilled)
+	changes("Other status changes", r.OtherChanges)
+	mutants("New mutants", r.Added)
+	mutants("Disappeared mutants", r.Disappeared)
+
+	deltas := func(title, column string, ds []ScoreDelta) {
+		ds = changed(ds)
+		if len(ds) == 0 {
+			return
+		}
+		fmt.Fprintf(&b, "\n### %s\n\n", title)
+		fmt.Fprintf(&b, "| %s | Old | New | Δ | Mutants |\n|---|---:|---:|---:|---:|\n", column)
+		for _, d := range ds {
+			fmt.Fprintf(&b, "| `%s` | %.2f%% | %.2f%% | %+.2f | %d → %d |\n", d.Name, d.OldScore, d.NewScore, d.Delta, d.OldMutants, d.NewMutants)
+		}
+	}
+	deltas("Packages", "Package", r.Packages)
+	deltas("Operators", "Operator", r.Operators)
+
+	if len(r.Tests) > 0 {
+		b.WriteString("\n### Tests with changed kill counts\n\n| Test | Old | New | Δ |\n|---|---:|---:|---:|\n")
+		for _, t := range r.Tests {
+			fmt.Fprintf(&b, "| `%s` | %d | %d | %+d |\n", t.Test, t.Old, t.New, t.New-t.Old)
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
@@ -0,0 +1,291 @@
+package testing
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/aclfe/gorgon/internal/cache"
+	"github.com/aclfe/gorgon/internal/logger"
+)
+
+// binaryCacheVersion is mixed into every key; bump it whenever the key
+// derivation changes so stale binaries are never reused.
+const binaryCacheVersion = "gorgon-bin-v1"
+
+// toolchainEnvVars are the `go env` settings that change the bytes of a
+// compiled test binary.
+var toolchainEnvVars = []string{
+	"GOVERSION", "GOOS", "GOARCH", "GOARM", "GOAMD64", "GOEXPERIMENT", "GOFLAGS",
+	"CGO_ENABLED", "CC", "CXX", "CGO_CFLAGS", "CGO_CXXFLAGS", "CGO_LDFLAGS", "GOMODCACHE",
+}
+
+// listedPackage is the subset of `go list -json` needed to fingerprint a
+// package and walk its imports.
+type listedPackage struct {
+	ImportPath      string
+	Dir             string
+	Standard        bool
+	GoFiles         []string
+	CgoFiles        []string
+	CFiles          []string
+	CXXFiles        []string
+	HFiles          []string
+	SFiles          []string
+	SysoFiles       []string
+	EmbedFiles      []string
+	TestGoFiles     []string
+	XTestGoFiles    []string
+	TestEmbedFiles  []string
+	XTestEmbedFiles []string
+	Imports         []string
+	TestImports     []string
+	XTestImports    []string
+}
+
+// binaryCache derives content-addressed keys for `go test -c` outputs and
+// stores the binaries in a cache.BinaryStore.
+//
+// A key covers the toolchain, build tags, go.mod/go.sum and the exact bytes
+// of every source the test binary is built from: the package's own files
+// (including tests and the schemata helper) and those of every dependency
+// that does not come from the module cache. Module-cache dependencies are
+// pinned by go.sum; the standard library by the Go version.
+type binaryCache struct {
+	store    *cache.BinaryStore
+	base     []byte
+	buildDir string
+	modCache string
+	pkgs     map[string]*listedPackage
+	byDir    map[string]string
+	source   func(string) string
+
+	mu     sync.Mutex
+	hashes map[string]string
+}
+
+func newBinaryCache(ctx context.Context, env buildEnv, maxBytes int64, buildTags []string, log *logger.Logger) (*binaryCache, error) {
+	store, err := cache.OpenBinaryStore(maxBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	toolchain, err := goEnv(ctx, env.buildDir, toolchainEnvVars)
+	if err != nil {
+		return nil, err
+	}
+
+	args := []string{"list", "-e", "-deps", "-test", "-json"}
+	args = append(args, env.flags...)
+	if len(buildTags) > 0 {
+		args = append(args, "-tags", strings.Join(buildTags, ","))
+	}
+	args = append(args, "./...")
+	cmd := exec.CommandContext(ctx, "go", args...)
+	cmd.Dir = env.buildDir
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list packages: %w (%s)", err, strings.TrimSpace(stderr.String()))
+	}
+
+	c := &binaryCache{
+		store:    store,
+		buildDir: env.buildDir,
+		modCache: toolchain["GOMODCACHE"],
+		pkgs:     make(map[string]*listedPackage),
+		byDir:    make(map[string]string),
+		source:   env.sourcePath,
+		hashes:   make(map[string]string),
+	}
+	if c.source == nil {
+		c.source = func(p string) string { return p }
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(out))
+	for {
+		var p listedPackage
+		if err := dec.Decode(&p); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("failed to parse go list output: %w", err)
+		}
+		// Test variants ("p [p.test]") and generated test mains ("p.test")
+		// are rebuilt from the same files as the base package.
+		if strings.Contains(p.ImportPath, " [") || strings.HasSuffix(p.ImportPath, ".test") {
+			continue
+		}
+		if _, seen := c.pkgs[p.ImportPath]; seen {
+			continue
+		}
+		pkg := p
+		c.pkgs[p.ImportPath] = &pkg
+		if p.Dir != "" && !p.Standard {
+			c.byDir[canonicalDir(p.Dir)] = p.ImportPath
+		}
+	}
+
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\n", binaryCacheVersion)
+	for _, k := range toolchainEnvVars {
+		fmt.Fprintf(h, "%s=%s\n", k, toolchain[k])
+	}
+	fmt.Fprintf(h, "tags=%s\n", strings.Join(buildTags, ","))
+	for _, name := range []string{"go.mod", "go.sum", "go.work", "go.work.sum"} {
+		data, err := os.ReadFile(filepath.Join(env.buildDir, name))
+		if err != nil && !os.IsNotExist(err) {
+			return nil, fmt.Errorf("failed to read %s: %w", name, err)
+		}
+		fmt.Fprintf(h, "%s:%d\n", name, len(data))
+		h.Write(data)
+	}
+	c.base = h.Sum(nil)
+
+	log.Debug("[BINCACHE] %d package(s) indexed, store at %s", len(c.pkgs), store.Dir())
+	return c, nil
+}
+
+// key returns the cache key for the test binary of the package in dir. It
+// reports false when the package is unknown or one of its sources cannot be
+// read, in which case the binary is built and not cached.
+func (c *binaryCache) key(dir string) (string, bool) {
+	root, ok := c.byDir[canonicalDir(dir)]
+	if !ok {
+		return "", false
+	}
+
+	h := sha256.New()
+	h.Write(c.base)
+
+	target := c.pkgs[root]
+	rel, _ := filepath.Rel(c.buildDir, target.Dir)
+	fmt.Fprintf(h, "target=%s dir=%s\n", root, filepath.ToSlash(rel))
+	if !c.writeFiles(h, target, testSources(target)) {
+		return "", false
+	}
+
+	seen := map[string]bool{root: true}
+	queue := append(append(append([]string{}, target.Imports...), target.TestImports...), target.XTestImports...)
+	var deps []string
+	for len(queue) > 0 {
+		path := queue[0]
+		queue = queue[1:]
+		if seen[path] {
+			continue
+		}
+		seen[path] = true
+		p, ok := c.pkgs[path]
+		if !ok || p.Standard || c.fromModCache(p) {
+			continue
+		}
+		deps = append(deps, path)
+		queue = append(queue, p.Imports...)
+	}
+	sort.Strings(deps)
+	for _, path := range deps {
+		p := c.pkgs[path]
+		fmt.Fprintf(h, "dep=%s\n", path)
+		if !c.writeFiles(h, p, buildSources(p)) {
+			return "", false
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil)), true
+}
+
+func (c *binaryCache) fromModCache(p *listedPackage) bool {
+	if c.modCache == "" || p.Dir == "" {
+		return false
+	}
+	rel, err := filepath.Rel(c.modCache, p.Dir)
+	return err == nil && !strings.HasPrefix(rel, "..")
+}
+
+func (c *binaryCache) writeFiles(h io.Writer, p *listedPackage, names []string) bool {
+	sort.Strings(names)
+	for _, name := range names {
+		sum, err := c.fileHash(filepath.Join(p.Dir, name))
+		if err != nil {
+			return false
+		}
+		fmt.Fprintf(h, "%s %s\n", name, sum)
+	}
+	return true
+}
+
+func (c *binaryCache) fileHash(path string) (string, error) {
+	c.mu.Lock()
+	sum, ok := c.hashes[path]
+	c.mu.Unlock()
+	if ok {
+		return sum, nil
+	}
+	sum, err := hashFile(c.source(path))
+	if err != nil {
+		return "", err
+	}
+	c.mu.Lock()
+	c.hashes[path] = sum
+	c.mu.Unlock()
+	return sum, nil
+}
+
+// fetch places the cached binary for key at dest.
+func (c *binaryCache) fetch(key, dest string) bool {
+	return c.store.Fetch(key, dest)
+}
+
+// put records the binary at src under key.
+func (c *binaryCache) put(key, src string) error {
+	return c.store.Put(key, src)
+}
+
+func buildSources(p *listedPackage) []string {
+	var names []string
+	for _, list := range [][]string{p.GoFiles, p.CgoFiles, p.CFiles, p.CXXFiles, p.HFiles, p.SFiles, p.SysoFiles, p.EmbedFiles} {
+		names = append(names, list...)
+	}
+	return names
+}
+
+func testSources(p *listedPackage) []string {
+	names := buildSources(p)
+	for _, list := range [][]string{p.TestGoFiles, p.XTestGoFiles, p.TestEmbedFiles, p.XTestEmbedFiles} {
+		names = append(names, list...)
+	}
+	return names
+}
+
+func goEnv(ctx context.Context, dir string, vars []string) (map[string]string, error) {
+	cmd := exec.CommandContext(ctx, "go", append([]string{"env"}, vars...)...)
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to run go env: %w", err)
+	}
+	lines := strings.Split(strings.TrimRight(string(out), "\n"), "\n")
+	env := make(map[string]string, len(vars))
+	for i, k := range vars {
+		if i < len(lines) {
+			env[k] = lines[i]
+		}
+	}
+	return env, nil
+}
+
+func canonicalDir(dir string) string {
+	if resolved, err := filepath.EvalSymlinks(dir); err == nil {
+		return resolved
+	}
+	return filepath.Clean(dir)
+}
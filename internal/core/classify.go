@@ -140,8 +140,10 @@ func truncOutput(b []byte) string {
 // is correctly reported as having no tests.
 //
 // importPath is interpreted relative to dir (typically a "./..." pattern).
-func packageHasGoTestFiles(ctx context.Context, dir, importPath string, buildTags []string) (bool, error) {
+// extraFlags are passed through verbatim (e.g. -overlay in overlay mode).
+func packageHasGoTestFiles(ctx context.Context, dir, importPath string, buildTags []string, extraFlags ...string) (bool, error) {
 	args := []string{"list", "-f", "{{len .TestGoFiles}}+{{len .XTestGoFiles}}"}
+	args = append(args, extraFlags...)
 	if len(buildTags) > 0 {
 		args = append(args, "-tags", strings.Join(buildTags, ","))
 	}
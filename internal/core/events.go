@@ -0,0 +1,180 @@
+package testing
+
+import (
+	"path/filepath"
+	"time"
+
+	"github.com/aclfe/gorgon/internal/events"
+)
+
+// eventPackage names the package in dir for the event stream: the
+// directory relative to root.
+func eventPackage(dir, root string) string {
+	if root != "" {
+		if abs, err := filepath.Abs(root); err == nil {
+			if rel, err := filepath.Rel(abs, dir); err == nil {
+				dir = rel
+			}
+		}
+	}
+	return filepath.ToSlash(dir)
+}
+
+// mutantEvent describes m, as it stands, for the event stream.
+func mutantEvent(m *Mutant) *events.Mutant {
+	e := &events.Mutant{
+		ID:          m.ID,
+		Fingerprint: m.Fingerprint,
+		Line:        m.Site.Line,
+		Column:      m.Site.Column,
+		Status:      m.Status,
+		KilledBy:    m.KilledBy,
+		Cached:      m.Cached,
+		Preflight:   m.Preflight,
+	}
+	if m.Site.File != nil {
+		e.File = m.Site.File.Name()
+	}
+	if m.Operator != nil {
+		e.Operator = m.Operator.Name()
+	}
+	if m.KillDuration > 0 {
+		e.DurationMS = float64(m.KillDuration) / float64(time.Millisecond)
+	}
+	return e
+}
+
+// emitPackagesDiscovered announces the generated mutants by package.
+func emitPackagesDiscovered(mutants []Mutant, root string) {
+	if !events.Enabled() {
+		return
+	}
+	packages := make(map[string]int)
+	for i := range mutants {
+		if mutants[i].Site.File != nil {
+			packages[eventPackage(filepath.Dir(mutants[i].Site.File.Name()), root)]++
+		}
+	}
+	events.Emit(events.Event{
+		Type:     events.PackagesDiscovered,
+		Total:    len(mutants),
+		Packages: packages,
+	})
+}
+
+// emitPreflight reports the preflight counts of a run, then each mutant
+// preflight rejected as finished.
+func emitPreflight(valid int, invalid []Mutant, root string) {
+	if !events.Enabled() {
+		return
+	}
+	counts := &events.Preflight{Checked: valid + len(invalid), Valid: valid}
+	for i := range invalid {
+		if invalid[i].Status == StatusInvalid {
+			counts.Invalid++
+		} else {
+			counts.CompileErrors++
+		}
+	}
+	events.Emit(events.Event{Type: events.PreflightFinished, Preflight: counts})
+	for i := range invalid {
+		emitMutantFinished(&invalid[i], 0, root)
+	}
+}
+
+// emitCached reports the mutants that will not be run as finished: all of
+// valid but the indices in toRun, nil when none will be. Most were restored
+// from the cache; the rest already have a status, e.g. untested.
+func emitCached(valid []Mutant, toRun []int, root string) {
+	if !events.Enabled() {
+		return
+	}
+	running := make(map[int]bool, len(toRun))
+	for _, idx := range toRun {
+		running[idx] = true
+	}
+	for i := range valid {
+		if !running[i] {
+			emitMutant(events.MutantFinished, mutantEvent(&valid[i]), 0, root)
+		}
+	}
+}
+
+// emitMutantFinished reports m's status.
+func emitMutantFinished(m *Mutant, worker int, root string) {
+	if !events.Enabled() {
+		return
+	}
+	emitMutant(events.MutantFinished, mutantEvent(m), worker, root)
+}
+
+// emitResult reports the outcome of running m's tests, before it is
+// collected into m.
+func emitResult(r mutantResult, m *Mutant, root string) {
+	if !events.Enabled() {
+		return
+	}
+	e := mutantEvent(m)
+	e.Status = r.status
+	e.KilledBy = r.killedBy
+	e.DurationMS = 0
+	if r.killDuration > 0 {
+		e.DurationMS = float64(r.killDuration) / float64(time.Millisecond)
+	}
+	emitMutant(events.MutantFinished, e, r.worker, root)
+}
+
+// emitMutantStarted reports that worker started running m's tests.
+func emitMutantStarted(m *Mutant, worker int, root string) {
+	if !events.Enabled() {
+		return
+	}
+	e := mutantEvent(m)
+	e.Status = ""
+	emitMutant(events.MutantStarted, e, worker, root)
+}
+
+func emitMutant(typ events.Type, e *events.Mutant, worker int, root string) {
+	events.Emit(events.Event{
+		Type:    typ,
+		Package: eventPackage(filepath.Dir(e.File), root),
+		Worker:  worker,
+		Mutant:  e,
+	})
+}
+
+// emitCompile reports building the test binary of the package in dir;
+// once it finished, result says which of ids failed to compile.
+func emitCompile(typ events.Type, dir, root string, ids []int, result *compileResultWithAttribution) {
+	if !events.Enabled() {
+		return
+	}
+	e := events.Event{Type: typ, Package: eventPackage(dir, root)}
+	if result != nil {
+		for _, id := range ids {
+			if err := result.perMutant[id]; err != nil {
+				e.Failures = append(e.Failures, events.CompileFailure{
+					MutantID:   id,
+					Attributed: result.attributed[id],
+					Error:      err.Error(),
+				})
+			}
+		}
+	}
+	events.Emit(e)
+}
+
+// workerLanes numbers the goroutines running mutants, so the event stream
+// can say which worker ran what.
+type workerLanes chan int
+
+func newWorkerLanes(n int) workerLanes {
+	l := make(workerLanes, n)
+	for i := 1; i <= n; i++ {
+		l <- i
+	}
+	return l
+}
+
+func (l workerLanes) acquire() int  { return <-l }
+func (l workerLanes) release(w int) { l <- w }
@@ -18,6 +18,7 @@ import (
 
 	"golang.org/x/sync/errgroup"
 
+	"github.com/aclfe/gorgon/internal/events"
 	"github.com/aclfe/gorgon/internal/logger"
 	"github.com/aclfe/gorgon/pkg/config"
 )
@@ -171,7 +172,12 @@ func findSchemataSpans(tempFile string) map[int]schemataSpan {
 	return spans
 }
 
-func attributeCompileErrors(tempDir string, projectRoot string, mutantIDs []int, sites map[int]MutantSite, output string) compileResultWithAttribution {
+// attributeCompileErrors pins compiler errors to the schemata blocks that
+// produced them. Transformed files live at tempDir/<path relative to
+// projectRoot>; relative error paths are resolved against buildDir, the
+// directory `go test -c` ran in (tempDir itself in copy mode, the original
+// module root in overlay mode).
+func attributeCompileErrors(tempDir, buildDir string, projectRoot string, mutantIDs []int, sites map[int]MutantSite, output string) compileResultWithAttribution {
 	result := compileResultWithAttribution{
 		compilerOutput: output,
 		perMutant:      make(map[int]error, len(mutantIDs)),
@@ -216,7 +222,7 @@ func attributeCompileErrors(tempDir string, projectRoot string, mutantIDs []int,
 	for _, ce := range parsed {
 		errFile := filepath.Clean(ce.File)
 		if !filepath.IsAbs(errFile) {
-			errFile = filepath.Clean(filepath.Join(tempDir, errFile))
+			errFile = filepath.Clean(filepath.Join(buildDir, errFile))
 		}
 		spans, ok := fileSpans[errFile]
 		formatted := fmt.Sprintf("%s:%d:%d: %s", ce.File, ce.Line, ce.Col, ce.Message)
@@ -260,6 +266,20 @@ func attributeCompileErrors(tempDir string, projectRoot string, mutantIDs []int,
 	return result
 }
 
+// buildEnv describes where a workspace's go commands run. In copy mode the
+// whole module lives in tempDir and buildDir == tempDir; in overlay mode
+// buildDir is the original module root and flags carries -overlay.
+// sourcePath maps a path under buildDir to the file the build reads; bins,
+// when set, serves and records compiled test binaries.
+type buildEnv struct {
+	tempDir     string
+	buildDir    string
+	projectRoot string
+	flags       []string
+	sourcePath  func(string) string
+	bins        *binaryCache
+}
+
 type testExecutor struct {
 	tempDir     string
 	testBinary  string
@@ -271,6 +291,9 @@ type testExecutor struct {
 	log         *logger.Logger
 	projectRoot string
 	buildTags   []string
+	buildDir    string
+	buildFlags  []string
+	bins        *binaryCache
 }
 
 func newTestExecutor(tempDir, pkgDir, projectRoot string, tests []string, log *logger.Logger) *testExecutor {
@@ -287,6 +310,7 @@ func newTestExecutor(tempDir, pkgDir, projectRoot string, tests []string, log *l
 		timeout:     30 * time.Second,
 		log:         log,
 		projectRoot: projectRoot,
+		buildDir:    tempDir,
 	}
 }
 
@@ -295,18 +319,42 @@ func (e *testExecutor) compileWithAttribution(ctx context.Context, mutantIDs []i
 	relPkg := e.relPath()
 
 	args := []string{"test", "-c", "-vet=off"}
+	args = append(args, e.buildFlags...)
 	if len(e.buildTags) > 0 {
 		args = append(args, "-tags", strings.Join(e.buildTags, ","))
 	}
 	args = append(args, "-o", e.testBinary, relPkg)
+
+	var binKey string
+	if e.bins != nil {
+		if key, ok := e.bins.key(e.runDir()); ok {
+			binKey = key
+			if e.bins.fetch(key, e.testBinary) {
+				e.log.Debug("[BINCACHE] hit for %s (%s)", relPkg, key[:12])
+				return compiledOK(mutantIDs)
+			}
+		}
+	}
+
 	cmd := exec.CommandContext(ctx, "go", args...)
-	cmd.Dir = e.tempDir
+	cmd.Dir = e.buildDir
 	out, err := cmd.CombinedOutput()
 	if err != nil {
 		e.log.Debug("[COMPILE] FAILED for package %s: %v\nOutput:\n%s", relPkg, err, string(out))
-		return attributeCompileErrors(e.tempDir, e.projectRoot, mutantIDs, sites, string(out))
+		return attributeCompileErrors(e.tempDir, e.buildDir, e.projectRoot, mutantIDs, sites, string(out))
 	}
 
+	if binKey != "" {
+		if _, statErr := os.Stat(e.testBinary); statErr == nil {
+			if putErr := e.bins.put(binKey, e.testBinary); putErr != nil {
+				e.log.Debug("[BINCACHE] failed to store %s: %v", relPkg, putErr)
+			}
+		}
+	}
+	return compiledOK(mutantIDs)
+}
+
+func compiledOK(mutantIDs []int) compileResultWithAttribution {
 	result := compileResultWithAttribution{
 		perMutant: make(map[int]error, len(mutantIDs)),
 	}
@@ -383,7 +431,7 @@ func (e *testExecutor) runMutant(ctx context.Context, mutantID int) mutantResult
 	raw, err := runTestBinary(
 		hardCtx,
 		e.testBinary,
-		e.pkgDir,
+		e.runDir(),
 		cmdEnv,
 		testFilter,
 		fmt.Sprintf("%.0fs", e.timeout.Seconds()),
@@ -410,7 +458,22 @@ func (e *testExecutor) relPath() string {
 	return "./" + filepath.ToSlash(rel)
 }
 
-func compileAndRunPackages(ctx context.Context, tempDir string, pkgToMutantIDs map[string][]int, pkgToMutants map[string][]*Mutant, mutantSites map[int]MutantSite, concurrent int, testsByPkg map[string][]string, buildTags []string, prog *ProgressTracker, log *logger.Logger) ([]mutantResult, error) {
+// runDir is the working directory for the test binary. In copy mode this is
+// the package's temp copy; in overlay mode it is the original package, so
+// tests that read testdata/ relative to the cwd keep working.
+func (e *testExecutor) runDir() string {
+	return filepath.Join(e.buildDir, e.relPath())
+}
+
+func compileAndRunPackages(ctx context.Context, env buildEnv, pkgToMutantIDs map[string][]int, pkgToMutants map[string][]*Mutant, mutantSites map[int]MutantSite, concurrent int, testsByPkg map[string][]string, buildTags []string, prog *ProgressTracker, log *logger.Logger) ([]mutantResult, error) {
+	idToMutant := make(map[int]*Mutant)
+	for _, ms := range pkgToMutants {
+		for _, m := range ms {
+			idToMutant[m.ID] = m
+		}
+	}
+	lanes := newWorkerLanes(concurrent)
+
 	resultsChan := make(chan mutantResult)
 	var allResults []mutantResult
 	var resultsMu sync.Mutex
@@ -419,6 +482,10 @@ func compileAndRunPackages(ctx context.Context, tempDir string, pkgToMutantIDs m
 	go func() {
 		defer collectorDone.Done()
 		for result := range resultsChan {
+			// Workers report their own results, before giving up their lane.
+			if m := idToMutant[result.id]; m != nil && result.worker == 0 {
+				emitResult(result, m, env.projectRoot)
+			}
 			resultsMu.Lock()
 			allResults = append(allResults, result)
 			resultsMu.Unlock()
@@ -472,14 +539,17 @@ func compileAndRunPackages(ctx context.Context, tempDir string, pkgToMutantIDs m
 				}
 			}
 			
-			executor := newTestExecutor(tempDir, pkgDir, tempDir, pkgTests, log)
+			executor := newTestExecutor(env.tempDir, pkgDir, env.projectRoot, pkgTests, log)
 			executor.buildTags = buildTags
+			executor.buildDir = env.buildDir
+			executor.buildFlags = env.flags
+			executor.bins = env.bins
 			pkgMuts := pkgToMutants[pkgDir]
 
 			// Authoritative test-file check via `go list`. If the package has
 			// no in-package or external test files, every mutant is UNTESTED
 			// and we skip compile/run entirely.
-			hasTests, listErr := packageHasGoTestFiles(compileCtx, tempDir, executor.relPath(), buildTags)
+			hasTests, listErr := packageHasGoTestFiles(compileCtx, env.buildDir, executor.relPath(), buildTags, env.flags...)
 			if listErr != nil {
 				executor.log.Debug("go list failed for %s: %v — falling through to compile", executor.relPath(), listErr)
 				hasTests = true // best effort: let compile decide
@@ -501,7 +571,13 @@ func compileAndRunPackages(ctx context.Context, tempDir string, pkgToMutantIDs m
 				}
 			}
 
+			var origDir string
+			if len(pkgMuts) > 0 && pkgMuts[0].Site.File != nil {
+				origDir = filepath.Dir(pkgMuts[0].Site.File.Name())
+			}
+			emitCompile(events.CompileStarted, origDir, env.projectRoot, nil, nil)
 			result := executor.compileWithAttribution(compileCtx, mutantIDsForPkg, currentSites)
+			emitCompile(events.CompileFinished, origDir, env.projectRoot, mutantIDsForPkg, &result)
 
 			for _, mutantID := range mutantIDsForPkg {
 				err := result.perMutant[mutantID]
@@ -593,7 +669,16 @@ func compileAndRunPackages(ctx context.Context, tempDir string, pkgToMutantIDs m
 
 					mutantID := mutantID
 					testGroup.Go(func() error {
+						worker := lanes.acquire()
+						defer lanes.release(worker)
+						if mutant != nil {
+							emitMutantStarted(mutant, worker, env.projectRoot)
+						}
 						result := executor.runMutant(testCtx, mutantID)
+						result.worker = worker
+						if mutant != nil {
+							emitResult(result, mutant, env.projectRoot)
+						}
 						resultsChan <- result
 						if prog != nil {
 							prog.Record()
@@ -675,7 +760,7 @@ func copyAllPackages(srcRoot, dstRoot string, skipFiles map[string]bool) error {
 	})
 }
 
-func runStandalonePackage(ctx context.Context, pkgDir string, pkgMutants []*Mutant, concurrent int, tests []string, workerTempDir string, progbar bool, buildTags []string, prog *ProgressTracker, log *logger.Logger) error {
+func runStandalonePackage(ctx context.Context, pkgDir, eventRoot string, pkgMutants []*Mutant, concurrent int, tests []string, workerTempDir string, progbar bool, buildTags []string, prog *ProgressTracker, log *logger.Logger) error {
 
 	entries, _ := os.ReadDir(workerTempDir)
 	for _, e := range entries {
@@ -870,7 +955,9 @@ func runStandalonePackage(ctx context.Context, pkgDir string, pkgMutants []*Muta
 
 	sites := rebuildMutantSites(pkgMutants)
 
+	emitCompile(events.CompileStarted, pkgDir, eventRoot, nil, nil)
 	result := executor.compileWithAttribution(ctx, mutantIDs, sites)
+	emitCompile(events.CompileFinished, pkgDir, eventRoot, mutantIDs, &result)
 	for _, m := range pkgMutants {
 		err := result.perMutant[m.ID]
 		if err != nil {
@@ -1012,6 +1099,7 @@ func sumMutantIDs(m map[string][]int) int {
 
 type mutantResult struct {
 	id           int
+	worker       int // lane that ran the tests, 0 when none did
 	status       string
 	err          error
 	killedBy     string
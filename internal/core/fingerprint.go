@@ -0,0 +1,198 @@
+package testing
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"go/ast"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/aclfe/gorgon/internal/engine"
+	"github.com/aclfe/gorgon/internal/subconfig"
+	"github.com/aclfe/gorgon/pkg/config"
+	"github.com/aclfe/gorgon/pkg/mutator"
+)
+
+// fingerprintLen is the number of hex digits kept from the fingerprint hash.
+const fingerprintLen = 16
+
+// describeMutants fills in what each mutant is, as opposed to how it fared:
+// its fingerprint and its original and mutated source. It must run before
+// schemata rewrites the AST.
+func describeMutants(mutants []Mutant) {
+	pkgs := make(map[string]string)
+	seen := make(map[string]int)
+	for i := range mutants {
+		m := &mutants[i]
+		dir := filepath.Dir(m.Site.File.Name())
+		pkg, ok := pkgs[dir]
+		if !ok {
+			pkg = packageIdentity(dir, m.Site.FileAST)
+			pkgs[dir] = pkg
+		}
+		mutated := mutator.ApplyOperator(m.Operator, m.Site.Node, m.Site.ReturnType, m.Site.FileAST, m.Site.EnclosingFunc)
+		fp := mutantFingerprint(m, pkg, mutated)
+		// Identical code at the same path (e.g. two init funcs with the
+		// same body) would collide; number repeats in generation order.
+		seen[fp]++
+		if n := seen[fp]; n > 1 {
+			fp += "-" + strconv.Itoa(n)
+		}
+		m.Fingerprint = fp
+		setSnippets(m, mutated)
+	}
+}
+
+// mutantFingerprint identifies m across runs. Unlike ID, which numbers
+// mutants in generation order, it is derived only from what the mutant is:
+// its package, the declaration it sits in, the operator, its normalised AST
+// path within the declaration and the original and mutated code. Edits
+// elsewhere, added or filtered sites and a different checkout location leave
+// it unchanged.
+func mutantFingerprint(m *Mutant, pkg string, mutated ast.Node) string {
+	scope, root := declScope(m.Site)
+	var path, normMutated string
+	if root != nil {
+		path = engine.NodePath(root, m.Site.Node)
+	}
+	original := engine.NormalizedSource(m.Site.Node)
+	if mutated != nil {
+		normMutated = engine.NormalizedSource(mutated)
+	}
+
+	h := sha256.New()
+	for _, part := range []string{pkg, scope, m.Operator.Name(), path, original, normMutated} {
+		h.Write([]byte(part))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))[:fingerprintLen]
+}
+
+// declScope returns the top-level declaration containing the site and a
+// name for it: the function identity for functions, the declared names for
+// package-level declarations.
+func declScope(site engine.Site) (string, ast.Node) {
+	if fn := site.EnclosingFunc; fn != nil {
+		return engine.FuncIdentity(fn), fn
+	}
+	if site.FileAST == nil || site.Node == nil {
+		return "", nil
+	}
+	pos := site.Node.Pos()
+	for _, d := range site.FileAST.Decls {
+		if pos < d.Pos() || pos >= d.End() {
+			continue
+		}
+		switch decl := d.(type) {
+		case *ast.FuncDecl:
+			return engine.FuncIdentity(decl), decl
+		case *ast.GenDecl:
+			var names []string
+			for _, spec := range decl.Specs {
+				switch s := spec.(type) {
+				case *ast.ValueSpec:
+					for _, n := range s.Names {
+						names = append(names, n.Name)
+					}
+				case *ast.TypeSpec:
+					names = append(names, s.Name.Name)
+				}
+			}
+			return decl.Tok.String() + " " + strings.Join(names, ","), decl
+		}
+	}
+	return "", nil
+}
+
+// ModulePath returns the path of the module dir belongs to, "" outside a
+// module.
+func ModulePath(dir string) string {
+	modDir := FindGoModDir(dir)
+	if modDir == "" {
+		return ""
+	}
+	return modulePath(modDir)
+}
+
+// modulePath reads the module path from the go.mod in modDir.
+func modulePath(modDir string) string {
+	data, err := os.ReadFile(filepath.Join(modDir, "go.mod"))
+	if err != nil {
+		return ""
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "module ") {
+			return strings.Trim(strings.TrimSpace(strings.TrimPrefix(line, "module ")), `"`)
+		}
+	}
+	return ""
+}
+
+// packageIdentity names the package in dir by its import path when it sits
+// in a module, falling back to the package clause.
+func packageIdentity(dir string, file *ast.File) string {
+	name := ""
+	if file != nil && file.Name != nil {
+		name = file.Name.Name
+	}
+	modDir := FindGoModDir(dir)
+	if modDir == "" {
+		return name
+	}
+	module := modulePath(modDir)
+	rel, err := filepath.Rel(modDir, dir)
+	if err != nil || module == "" {
+		return name
+	}
+	if rel == "." {
+		return module + " " + name
+	}
+	return module + "/" + filepath.ToSlash(rel) + " " + name
+}
+
+// FilterSuppressedFingerprints drops mutants whose fingerprint is listed in
+// a `suppress` entry, either in root or in a sub-config governing the
+// mutant's file.
+func FilterSuppressedFingerprints(mutants []Mutant, root []config.SuppressEntry, resolver *subconfig.Resolver) []Mutant {
+	rootSet := fingerprintSet(root)
+	hasOverrides := resolver != nil && resolver.HasAnyOverrides()
+	if len(rootSet) == 0 && !hasOverrides {
+		return mutants
+	}
+
+	byDir := make(map[string]map[string]bool)
+	kept := mutants[:0]
+	for _, m := range mutants {
+		set := rootSet
+		if hasOverrides {
+			dir := filepath.Dir(m.Site.File.Name())
+			s, ok := byDir[dir]
+			if !ok {
+				s = fingerprintSet(resolver.EffectiveSuppress(m.Site.File.Name(), root))
+				byDir[dir] = s
+			}
+			set = s
+		}
+		if set[m.Fingerprint] {
+			continue
+		}
+		kept = append(kept, m)
+	}
+	return kept
+}
+
+func fingerprintSet(entries []config.SuppressEntry) map[string]bool {
+	var set map[string]bool
+	for _, e := range entries {
+		if fp := strings.TrimSpace(e.Fingerprint); fp != "" {
+			if set == nil {
+				set = make(map[string]bool)
+			}
+			set[fp] = true
+		}
+	}
+	return set
+}
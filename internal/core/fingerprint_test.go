@@ -0,0 +1,93 @@
+package testing
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"testing"
+
+	"github.com/aclfe/gorgon/internal/engine"
+	"github.com/aclfe/gorgon/pkg/config"
+	"github.com/aclfe/gorgon/pkg/mutator"
+	"github.com/aclfe/gorgon/pkg/mutator/operators/arithmetic_flip"
+)
+
+// binaryExprMutants returns one mutant per binary expression in path, in
+// source order, with IDs starting at firstID.
+func binaryExprMutants(t *testing.T, path string, firstID int, op mutator.Operator) []Mutant {
+	t.Helper()
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, path, nil, parser.ParseComments)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var mutants []Mutant
+	for _, d := range f.Decls {
+		fn, _ := d.(*ast.FuncDecl)
+		ast.Inspect(d, func(n ast.Node) bool {
+			if _, ok := n.(*ast.BinaryExpr); ok {
+				pos := fset.Position(n.Pos())
+				mutants = append(mutants, Mutant{
+					ID:       firstID + len(mutants),
+					Operator: op,
+					Site: engine.Site{
+						File: fset.File(n.Pos()), FileAST: f, Fset: fset,
+						Line: pos.Line, Column: pos.Column, Node: n, EnclosingFunc: fn,
+					},
+				})
+			}
+			return true
+		})
+	}
+	describeMutants(mutants)
+	return mutants
+}
+
+func TestDescribeMutants_StableAcrossUnrelatedChanges(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, filepath.Join(dir, "go.mod"), "module example.com/fp\n\ngo 1.21\n")
+	src := filepath.Join(dir, "calc", "calc.go")
+	op := arithmetic_flip.ArithmeticFlip{}
+
+	writeTestFile(t, src, "package calc\n\nfunc Add(a, b int) int { return a + b }\n\nfunc Sub(a, b int) int { return a - b }\n")
+	before := binaryExprMutants(t, src, 1, op)
+
+	// A new function ahead of the others shifts every line and ID.
+	writeTestFile(t, src, "package calc\n\n// Mul multiplies.\nfunc Mul(a, b int) int { return a * b }\n\n// Add adds.\nfunc Add(a, b int) int {\n\treturn a + b\n}\n\nfunc Sub(a, b int) int { return a - b }\n")
+	after := binaryExprMutants(t, src, 1, op)
+
+	if len(before) != 2 || len(after) != 3 {
+		t.Fatalf("unexpected mutant counts %d, %d", len(before), len(after))
+	}
+	if before[0].Fingerprint == before[1].Fingerprint {
+		t.Fatal("expected distinct mutants to have distinct fingerprints")
+	}
+	if before[0].Fingerprint != after[1].Fingerprint || before[1].Fingerprint != after[2].Fingerprint {
+		t.Fatalf("expected fingerprints to survive unrelated edits: %s %s vs %s %s",
+			before[0].Fingerprint, before[1].Fingerprint, after[1].Fingerprint, after[2].Fingerprint)
+	}
+
+	writeTestFile(t, src, "package calc\n\nfunc Add(a, b int) int { return a + b + 0 }\n\nfunc Sub(a, b int) int { return a - b }\n")
+	edited := binaryExprMutants(t, src, 1, op)
+	if edited[0].Fingerprint == before[0].Fingerprint {
+		t.Fatal("expected an edit to the mutated code to change its fingerprint")
+	}
+}
+
+func TestFilterSuppressedFingerprints(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, filepath.Join(dir, "go.mod"), "module example.com/fp\n\ngo 1.21\n")
+	src := filepath.Join(dir, "calc.go")
+	writeTestFile(t, src, "package calc\n\nfunc Add(a, b int) int { return a + b }\n\nfunc Sub(a, b int) int { return a - b }\n")
+	mutants := binaryExprMutants(t, src, 1, arithmetic_flip.ArithmeticFlip{})
+	keep := mutants[1].Fingerprint
+
+	kept := FilterSuppressedFingerprints(mutants, []config.SuppressEntry{
+		{Location: "calc.go:3"},
+		{Fingerprint: mutants[0].Fingerprint},
+	}, nil)
+	if len(kept) != 1 || kept[0].Fingerprint != keep {
+		t.Fatalf("expected only the unsuppressed mutant, got %d", len(kept))
+	}
+}
@@ -3,6 +3,9 @@ package testing
 import (
 	"crypto/sha256"
 	"encoding/hex"
+	"errors"
+	"fmt"
+	"go/ast"
 	"io"
 	"os"
 	"path/filepath"
@@ -32,7 +35,11 @@ const (
 
 type Mutant struct {
 	ID           int
+	Fingerprint  string // stable across runs; see mutantFingerprint
 	Site         engine.Site
+	Original     string // gofmt'd source of Site.Node
+	Mutated      string // gofmt'd source of the operator's replacement
+	Diff         string // unified diff from Original to Mutated
 	Operator     mutator.Operator
 	TempDir      string
 	TempLine     int
@@ -43,6 +50,8 @@ type Mutant struct {
 	KillDuration time.Duration
 	KillOutput   string
 	ErrorReason  string
+	Preflight    bool // rejected by preflight before any build; Status is invalid or a compile error
+	Cached       bool // result restored from the cache rather than run
 }
 
 func effectiveOperators(filePath, projectRoot string, base []mutator.Operator, rules []config.DirOperatorRule, log *logger.Logger) []mutator.Operator {
@@ -139,9 +148,9 @@ func GenerateMutants(sites []engine.Site, operators []mutator.Operator, allOps [
 
 	for _, site := range sites {
 		// 1. Apply sub-config operator override (replace semantics)
-		ops := operators
+		ops := sortedOps
 		if resolver != nil && resolver.HasAnyOverrides() {
-			ops = resolver.EffectiveOperators(site.File.Name(), operators, allOps)
+			ops = resolver.EffectiveOperators(site.File.Name(), sortedOps, allOps)
 		}
 
 		// 2. Apply dir_rules on top (existing logic, but with merged rules)
@@ -176,10 +185,146 @@ func GenerateMutants(sites []engine.Site, operators []mutator.Operator, allOps [
 			}
 		}
 	}
+	describeMutants(mutants)
 	return mutants
 }
 
-func ResolveCache(mutants []Mutant, baseDir string, c *cache.Cache) (toRun []int, fileHashes map[string]string, err error) {
+// CacheOptions controls how cached results are validated.
+type CacheOptions struct {
+	// ExternalSuites whose sources are folded into every test fingerprint.
+	ExternalSuites config.ExternalSuitesConfig
+	// Selective keeps killed (and compile-error) results when only tests
+	// changed, rerunning just the mutants that tests could newly kill.
+	Selective bool
+}
+
+// CacheFingerprints holds the hashes cached results are validated against.
+// Code-side hashes (the mutated file, go.mod/go.sum) form the cache key;
+// test-side hashes are stored in the entry so a test-only change can be
+// told apart from a code change.
+type CacheFingerprints struct {
+	files map[string]string   // mutated file -> content hash
+	sites map[int]cache.Entry // mutant ID -> key inputs, taken before schemata rewrites the AST
+	tests map[string]string   // package dir -> _test.go files + external suites
+	deps  string              // go.mod + go.sum of the enclosing module
+	root  string              // module root; keys use paths relative to it
+}
+
+func newCacheFingerprints(mutants []Mutant, baseDir string, opts CacheOptions) *CacheFingerprints {
+	fp := &CacheFingerprints{
+		files: make(map[string]string),
+		sites: make(map[int]cache.Entry),
+		tests: make(map[string]string),
+	}
+
+	absBase, _ := filepath.Abs(baseDir)
+	modDir := FindGoModDir(absBase)
+	fp.root = modDir
+	if modDir != "" {
+		fp.deps = cache.DepsHash(modDir)
+	}
+
+	var external string
+	if opts.ExternalSuites.Enabled && modDir != "" {
+		external = hashFiles(externalSuiteSources(modDir, opts.ExternalSuites))
+	}
+
+	for i := range mutants {
+		f := mutants[i].Site.File.Name()
+		if _, ok := fp.files[f]; ok {
+			continue
+		}
+		h, err := hashFile(f)
+		if err != nil {
+			continue
+		}
+		fp.files[f] = h
+
+		dir := filepath.Dir(f)
+		if _, ok := fp.tests[dir]; !ok {
+			tests, _ := filepath.Glob(filepath.Join(dir, "*_test.go"))
+			fp.tests[dir] = hashFiles(tests) + external
+		}
+	}
+
+	funcs := make(map[*ast.FuncDecl]string)
+	for i := range mutants {
+		if site, ok := fp.computeSite(&mutants[i], funcs); ok {
+			fp.sites[mutants[i].ID] = site
+		}
+	}
+	return fp
+}
+
+// key derives the cache key of m. The file path is made relative to the
+// module root so checkouts in different locations share results.
+func (fp *CacheFingerprints) key(c *cache.Cache, m *Mutant) (string, bool) {
+	site, ok := fp.site(m)
+	if !ok {
+		return "", false
+	}
+	return site.SiteKey(), true
+}
+
+// site returns the key inputs of m as entry fields, recorded alongside the
+// result so cache maintenance can re-derive the key later.
+func (fp *CacheFingerprints) site(m *Mutant) (cache.Entry, bool) {
+	e, ok := fp.sites[m.ID]
+	return e, ok
+}
+
+// computeSite derives the key inputs of m. Mutants inside a function are
+// keyed by the function's normalised hash and their AST path within it, so
+// unrelated edits to the file keep their results; anything else
+// (package-level declarations) falls back to the whole-file hash. It must
+// run on the pristine AST: schemata later rewrites function bodies in place.
+func (fp *CacheFingerprints) computeSite(m *Mutant, funcs map[*ast.FuncDecl]string) (cache.Entry, bool) {
+	path := m.Site.File.Name()
+	fh := fp.files[path]
+	if fh == "" {
+		return cache.Entry{}, false
+	}
+	if fp.root != "" {
+		if rel, err := filepath.Rel(fp.root, path); err == nil {
+			path = filepath.ToSlash(rel)
+		}
+	}
+	e := cache.Entry{
+		File:     path,
+		Line:     m.Site.Line,
+		Column:   m.Site.Column,
+		Node:     schemata_nodes.NodeTypeToUint8(m.Site.Node),
+		Operator: m.Operator.Name(),
+		FileHash: fh,
+		DepsHash: fp.deps,
+	}
+	if fn := m.Site.EnclosingFunc; fn != nil {
+		if nodePath := engine.NodePath(fn, m.Site.Node); nodePath != "" {
+			h, ok := funcs[fn]
+			if !ok {
+				h = engine.FuncHash(fn)
+				funcs[fn] = h
+			}
+			if h != "" {
+				e.Func, e.FuncHash, e.Path = engine.FuncIdentity(fn), h, nodePath
+			}
+		}
+	}
+	return e, true
+}
+
+func (fp *CacheFingerprints) testHash(m *Mutant) string {
+	return fp.tests[filepath.Dir(m.Site.File.Name())]
+}
+
+// cachedResultStillValid reports whether an entry written against other
+// tests can be reused. Only outcomes decided by the code alone survive a
+// test change: a kill stays a kill and a compile error stays one.
+func cachedResultStillValid(e cache.Entry) bool {
+	return e.Status == StatusKilled || e.Status == StatusError || e.Status == StatusInvalid
+}
+
+func ResolveCache(mutants []Mutant, baseDir string, c *cache.Cache, opts CacheOptions) (toRun []int, fp *CacheFingerprints, err error) {
 	if c == nil {
 		indices := make([]int, 0, len(mutants))
 		for i := range mutants {
@@ -190,37 +335,39 @@ func ResolveCache(mutants []Mutant, baseDir string, c *cache.Cache) (toRun []int
 		return indices, nil, nil
 	}
 
-	fileHashes = make(map[string]string)
+	fp = newCacheFingerprints(mutants, baseDir, opts)
+
+	keys := make([]string, 0, len(mutants))
 	for i := range mutants {
-		f := mutants[i].Site.File.Name()
-		if _, ok := fileHashes[f]; !ok {
-			h, err := hashFile(f)
-			if err != nil {
-				continue
-			}
-			fileHashes[f] = h
+		if key, ok := fp.key(c, &mutants[i]); ok {
+			keys = append(keys, key)
 		}
 	}
+	if err := c.Prefetch(keys); err != nil {
+		// A partially reachable shared cache only costs reruns.
+		fmt.Fprintf(os.Stderr, "Warning: cache fetch from %s incomplete: %v\n", c.Location(), err)
+	}
 
 	var cachedCount int
 	for i := range mutants {
 		m := &mutants[i]
-		fh := fileHashes[m.Site.File.Name()]
-		if fh == "" {
+		key, ok := fp.key(c, m)
+		if !ok {
 			continue
 		}
-
-		key := c.Key(m.Site.File.Name(), m.Site.Line, m.Site.Column,
-			schemata_nodes.NodeTypeToUint8(m.Site.Node), m.Operator.Name(), fh)
-		if entry, ok := c.Get(key); ok {
-			m.Status = entry.Status
-			cachedCount++
+		entry, ok := c.Get(key)
+		if !ok {
+			continue
 		}
+		if entry.TestHash != fp.testHash(m) && !(opts.Selective && cachedResultStillValid(entry)) {
+			continue
+		}
+		m.restoreFromCache(entry)
+		cachedCount++
 	}
 
 	if cachedCount == len(mutants) {
-		_ = c.Save(baseDir)
-		return nil, fileHashes, nil
+		return nil, fp, nil
 	}
 
 	toRun = make([]int, 0, len(mutants)-cachedCount)
@@ -229,45 +376,97 @@ func ResolveCache(mutants []Mutant, baseDir string, c *cache.Cache) (toRun []int
 			toRun = append(toRun, i)
 		}
 	}
-	return toRun, fileHashes, nil
+	return toRun, fp, nil
 }
 
-func SaveCache(mutants []Mutant, baseDir string, c *cache.Cache, fileHashes map[string]string) {
+func SaveCache(mutants []Mutant, baseDir string, c *cache.Cache, fp *CacheFingerprints) {
 	if c == nil {
 		return
 	}
 
-	if fileHashes == nil {
-		fileHashes = make(map[string]string)
-		for i := range mutants {
-			f := mutants[i].Site.File.Name()
-			if mutants[i].Status == "" {
-				continue
-			}
-			if _, ok := fileHashes[f]; !ok {
-				h, err := hashFile(f)
-				if err != nil {
-					continue
-				}
-				fileHashes[f] = h
-			}
-		}
+	if fp == nil {
+		fp = newCacheFingerprints(mutants, baseDir, CacheOptions{})
 	}
 
 	for i := range mutants {
 		m := &mutants[i]
-		if m.Status == "" {
+		// Results restored from the cache are in the log already.
+		if m.Status == "" || m.Cached {
 			continue
 		}
-		fh := fileHashes[m.Site.File.Name()]
-		if fh == "" {
+		site, ok := fp.site(m)
+		if !ok {
 			continue
 		}
-		key := c.Key(m.Site.File.Name(), m.Site.Line, m.Site.Column,
-			schemata_nodes.NodeTypeToUint8(m.Site.Node), m.Operator.Name(), fh)
-		c.Set(key, m.Status)
+		key := site.SiteKey()
+		entry := m.cacheEntry(site)
+		entry.TestHash = fp.testHash(m)
+		c.Set(key, entry)
+	}
+	if err := c.Save(); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to save cache to %s: %v\n", c.Location(), err)
+	}
+}
+
+// cacheEntry captures the mutant's result for the cache on top of the
+// site fields it was keyed from.
+func (m *Mutant) cacheEntry(site cache.Entry) cache.Entry {
+	e := site
+	e.Fingerprint = m.Fingerprint
+	e.Status = m.Status
+	e.KilledBy = m.KilledBy
+	e.KillDuration = m.KillDuration
+	e.KillOutput = m.KillOutput
+	e.ErrorReason = m.ErrorReason
+	if m.Error != nil {
+		e.Error = m.Error.Error()
+	}
+	return e
+}
+
+// restoreFromCache applies a cached result to the mutant.
+func (m *Mutant) restoreFromCache(e cache.Entry) {
+	m.Cached = true
+	m.Status = e.Status
+	m.KilledBy = e.KilledBy
+	m.KillDuration = e.KillDuration
+	m.KillOutput = e.KillOutput
+	m.ErrorReason = e.ErrorReason
+	if e.Error != "" {
+		m.Error = errors.New(e.Error)
+	}
+}
+
+// externalSuiteSources lists the .go files of every configured external
+// suite, resolved the same way the workspace copies them.
+func externalSuiteSources(absModule string, cfg config.ExternalSuitesConfig) []string {
+	var files []string
+	for _, suite := range cfg.Suites {
+		for _, p := range suite.Paths {
+			dirs, err := expandGlobPath(absModule, p)
+			if err != nil {
+				continue
+			}
+			for _, dir := range dirs {
+				matches, _ := filepath.Glob(filepath.Join(dir, "*.go"))
+				files = append(files, matches...)
+			}
+		}
+	}
+	return files
+}
+
+// hashFiles returns a single hash over the names and contents of paths.
+// Missing files contribute their name only, so adding one changes the hash.
+func hashFiles(paths []string) string {
+	sorted := append([]string(nil), paths...)
+	sort.Strings(sorted)
+	h := sha256.New()
+	for _, p := range sorted {
+		sum, _ := hashFile(p)
+		io.WriteString(h, filepath.Base(p)+" "+sum+"\n")
 	}
-	_ = c.Save(baseDir)
+	return hex.EncodeToString(h.Sum(nil))
 }
 
 func canApply(op mutator.Operator, site engine.Site) bool {
@@ -0,0 +1,169 @@
+package testing
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/aclfe/gorgon/internal/cache"
+	"github.com/aclfe/gorgon/internal/engine"
+)
+
+type stubOperator struct{ name string }
+
+func (o stubOperator) Name() string                  { return o.name }
+func (o stubOperator) CanApply(ast.Node) bool        { return true }
+func (o stubOperator) Mutate(node ast.Node) ast.Node { return node }
+
+func cacheFixture(t *testing.T) (dir string, mutants func() []Mutant) {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+	dir = t.TempDir()
+	writeTestFile(t, filepath.Join(dir, "go.mod"), "module example.com/c\n\ngo 1.21\n")
+	src := filepath.Join(dir, "c.go")
+	writeTestFile(t, src, "package c\n\nfunc F(a, b int) int { return a + b }\n")
+	writeTestFile(t, filepath.Join(dir, "c_test.go"), "package c\n")
+
+	return dir, func() []Mutant {
+		file := token.NewFileSet().AddFile(src, -1, 100)
+		node := &ast.BinaryExpr{Op: token.ADD}
+		return []Mutant{
+			{ID: 1, Site: engine.Site{File: file, Line: 3, Column: 31, Node: node}, Operator: stubOperator{"a"}},
+			{ID: 2, Site: engine.Site{File: file, Line: 3, Column: 31, Node: node}, Operator: stubOperator{"b"}},
+		}
+	}
+}
+
+func TestResolveCache_TestChangeInvalidatesResults(t *testing.T) {
+	dir, fresh := cacheFixture(t)
+	c := cache.New()
+
+	first := fresh()
+	_, fp, err := ResolveCache(first, dir, c, CacheOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	first[0].Status = StatusKilled
+	first[1].Status = StatusSurvived
+	SaveCache(first, dir, c, fp)
+
+	toRun, _, err := ResolveCache(fresh(), dir, c, CacheOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if toRun != nil {
+		t.Fatalf("expected every mutant cached, got %v to run", toRun)
+	}
+
+	writeTestFile(t, filepath.Join(dir, "c_test.go"), "package c\n\nfunc helper() {}\n")
+
+	toRun, _, err = ResolveCache(fresh(), dir, c, CacheOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(toRun) != 2 {
+		t.Fatalf("strict mode: expected both mutants to rerun, got %v", toRun)
+	}
+
+	again := fresh()
+	toRun, _, err = ResolveCache(again, dir, c, CacheOptions{Selective: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(toRun) != 1 || toRun[0] != 1 || again[0].Status != StatusKilled {
+		t.Fatalf("selective mode: expected only the survivor to rerun, got %v (statuses %q, %q)", toRun, again[0].Status, again[1].Status)
+	}
+}
+
+func TestResolveCache_GoSumChangeInvalidatesResults(t *testing.T) {
+	dir, fresh := cacheFixture(t)
+	c := cache.New()
+
+	first := fresh()
+	_, fp, err := ResolveCache(first, dir, c, CacheOptions{Selective: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	first[0].Status = StatusKilled
+	first[1].Status = StatusKilled
+	SaveCache(first, dir, c, fp)
+
+	if err := os.WriteFile(filepath.Join(dir, "go.sum"), []byte("example.com/dep v1.0.0 h1:x=\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	toRun, _, err := ResolveCache(fresh(), dir, c, CacheOptions{Selective: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(toRun) != 2 {
+		t.Fatalf("expected a go.sum change to invalidate every result, got %v to run", toRun)
+	}
+}
+
+// parsedMutants parses path and returns one mutant on the first binary
+// expression of function fn, positioned as the engine would place it.
+func parsedMutants(t *testing.T, path, fn string) []Mutant {
+	t.Helper()
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, path, nil, parser.ParseComments)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, d := range f.Decls {
+		decl, ok := d.(*ast.FuncDecl)
+		if !ok || decl.Name.Name != fn {
+			continue
+		}
+		var node ast.Node
+		ast.Inspect(decl, func(n ast.Node) bool {
+			if _, ok := n.(*ast.BinaryExpr); ok && node == nil {
+				node = n
+			}
+			return node == nil
+		})
+		pos := fset.Position(node.Pos())
+		site := engine.Site{File: fset.File(node.Pos()), Line: pos.Line, Column: pos.Column, Node: node, EnclosingFunc: decl}
+		return []Mutant{{ID: 1, Site: site, Operator: stubOperator{"a"}}}
+	}
+	t.Fatalf("no function %s in %s", fn, path)
+	return nil
+}
+
+func TestResolveCache_UnrelatedEditsKeepFunctionResults(t *testing.T) {
+	dir, _ := cacheFixture(t)
+	src := filepath.Join(dir, "c.go")
+	writeTestFile(t, src, "package c\n\nfunc F(a, b int) int { return a + b }\n\nfunc G() int { return 1 }\n")
+	c := cache.New()
+
+	first := parsedMutants(t, src, "F")
+	_, fp, err := ResolveCache(first, dir, c, CacheOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	first[0].Status = StatusKilled
+	SaveCache(first, dir, c, fp)
+
+	// New import, comments, a moved F and an edited G: F's mutant stays cached.
+	writeTestFile(t, src, "// Package c.\npackage c\n\nimport \"fmt\"\n\nfunc G() string { return fmt.Sprint(2) }\n\n// F adds.\nfunc F(a, b int) int {\n\t// sum\n\treturn a + b\n}\n")
+	moved := parsedMutants(t, src, "F")
+	toRun, _, err := ResolveCache(moved, dir, c, CacheOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if toRun != nil || moved[0].Status != StatusKilled {
+		t.Fatalf("expected F's result to survive unrelated edits, got %v to run", toRun)
+	}
+
+	writeTestFile(t, src, "package c\n\nfunc F(a, b int) int { return a + b + 1 }\n")
+	toRun, _, err = ResolveCache(parsedMutants(t, src, "F"), dir, c, CacheOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(toRun) != 1 {
+		t.Fatalf("expected an edit to F to invalidate its result, got %v to run", toRun)
+	}
+}
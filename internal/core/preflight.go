@@ -16,6 +16,7 @@ import (
 	"golang.org/x/tools/go/packages"
 
 	"github.com/aclfe/gorgon/internal/logger"
+	"github.com/aclfe/gorgon/internal/trace"
 	"github.com/aclfe/gorgon/pkg/mutator"
 )
 
@@ -41,21 +42,27 @@ const (
 func RunPreflight(mutants []Mutant, log *logger.Logger) ([]Mutant, []PreflightResult) {
 	var invalid []PreflightResult
 
+	end := trace.Begin("preflight L1 static checks", map[string]any{"mutants": len(mutants)})
 	level1Valid, level1Invalid := quickStaticFilter(mutants)
+	end()
 	invalid = append(invalid, level1Invalid...)
 	if len(level1Valid) == 0 {
 		LogPreflightResults(log, len(mutants), invalid, 0)
 		return nil, invalid
 	}
 
+	end = trace.Begin("preflight L2 schemata AST", map[string]any{"mutants": len(level1Valid)})
 	level2Valid, level2Invalid := level2PackagePreflight(level1Valid)
+	end()
 	invalid = append(invalid, level2Invalid...)
 	if len(level2Valid) == 0 {
 		LogPreflightResults(log, len(mutants), invalid, 0)
 		return nil, invalid
 	}
 
+	end = trace.Begin("preflight L3 type-check", map[string]any{"mutants": len(level2Valid)})
 	level3Valid, level3Invalid := level3TypeCheckPreflight(level2Valid, log)
+	end()
 	invalid = append(invalid, level3Invalid...)
 
 	LogPreflightResults(log, len(mutants), invalid, len(level3Valid))
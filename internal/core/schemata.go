@@ -20,6 +20,7 @@ import (
 	"github.com/aclfe/gorgon/internal/engine"
 	"github.com/aclfe/gorgon/internal/logger"
 	"github.com/aclfe/gorgon/internal/subconfig"
+	"github.com/aclfe/gorgon/internal/trace"
 	"github.com/aclfe/gorgon/pkg/config"
 	"github.com/aclfe/gorgon/pkg/mutator"
 )
@@ -42,9 +43,13 @@ func GenerateAndRunSchemata(ctx context.Context, sites []engine.Site, operators
 	log.Debug("GenerateAndRunSchemata called with externalCfg.Enabled=%v, suites=%d", externalCfg.Enabled, len(externalCfg.Suites))
 
 	mutants := GenerateMutants(sites, operators, allOps, projectRoot, dirRules, resolver, log)
+	if cfg != nil {
+		mutants = FilterSuppressedFingerprints(mutants, cfg.Suppress, resolver)
+	}
 	if len(mutants) == 0 {
 		return nil, nil
 	}
+	emitPackagesDiscovered(mutants, projectRoot)
 
 	if progbar {
 		log.Print("Generated %d mutants from sites", len(mutants))
@@ -69,6 +74,8 @@ func GenerateAndRunSchemata(ctx context.Context, sites []engine.Site, operators
 				mutants[i].Status = r.Status
 				mutants[i].Error = r.Error
 				mutants[i].KillOutput = r.ErrorReason
+				mutants[i].ErrorReason = r.ErrorReason
+				mutants[i].Preflight = true
 				if r.Status == StatusCompileError {
 					mutants[i].KilledBy = "(compiler)"
 				}
@@ -78,6 +85,8 @@ func GenerateAndRunSchemata(ctx context.Context, sites []engine.Site, operators
 		}
 	}
 
+	emitPreflight(len(validMutants), invalidMutants, projectRoot)
+
 	// Total includes all mutants (valid + invalid) so reporter math always balances.
 	lastTotalMutants = len(mutants)
 	mutants = validMutants
@@ -86,12 +95,17 @@ func GenerateAndRunSchemata(ctx context.Context, sites []engine.Site, operators
 		return invalidMutants, nil
 	}
 
-	uncachedIndices, fileHashes, err := ResolveCache(mutants, baseDir, cache)
+	cacheOpts := CacheOptions{ExternalSuites: externalCfg}
+	if cfg != nil {
+		cacheOpts.Selective = cfg.CacheInvalidation == config.CacheInvalidationSelective
+	}
+	uncachedIndices, fingerprints, err := ResolveCache(mutants, baseDir, cache, cacheOpts)
 	if err != nil {
 		setMutantErrors(mutants, fmt.Errorf("cache resolution failed: %w", err))
 		return append(mutants, invalidMutants...), err
 	}
 	log.Debug("After cache check: uncachedIndices nil=%v", uncachedIndices == nil)
+	emitCached(mutants, uncachedIndices, projectRoot)
 
 	// If all cached and no external suites, return early
 	if uncachedIndices == nil && !externalCfg.Enabled {
@@ -119,14 +133,20 @@ func GenerateAndRunSchemata(ctx context.Context, sites []engine.Site, operators
 		}
 		defer ws.Cleanup()
 
+		end := trace.Begin("workspace setup", nil)
 		if err := ws.Setup(baseDir, mutants); err != nil {
+			end()
 			finalizeMutants(mutants)
 			return append(mutants, invalidMutants...), fmt.Errorf("workspace setup failed: %w", err)
 		}
 
 		_ = MakeSelfContained(ws.TempDir)
+		end()
 
-		if _, _, err := ws.applySchemata(mutants, log); err != nil {
+		end = trace.Begin("apply schemata", map[string]any{"mutants": len(mutants)})
+		_, _, err = ws.applySchemata(mutants, log)
+		end()
+		if err != nil {
 			finalizeMutants(mutants)
 			return append(mutants, invalidMutants...), fmt.Errorf("schemata application failed: %w", err)
 		}
@@ -165,11 +185,22 @@ func GenerateAndRunSchemata(ctx context.Context, sites []engine.Site, operators
 		if cfg != nil {
 			bt = cfg.BuildTags
 		}
-		return runStandalone(ctx, mutants, uncachedIndices, concurrent, cache, baseDir, testsByPkg, progbar, bt, fileHashes, log)
+		result, err := runStandalone(ctx, mutants, uncachedIndices, concurrent, cache, baseDir, projectRoot, testsByPkg, progbar, bt, fingerprints, log)
+		for _, idx := range uncachedIndices {
+			if idx < len(result) {
+				emitMutantFinished(&result[idx], 0, projectRoot)
+			}
+		}
+		return result, err
 	}
 	log.Debug("Module layout detected, using workspace mode")
 
-	ws, err := NewModuleWorkspace()
+	newWorkspace := NewModuleWorkspace
+	if cfg != nil && cfg.Workspace == config.WorkspaceOverlay {
+		log.Debug("Using overlay workspace: building %s in place with -overlay", projectRootAbs)
+		newWorkspace = NewOverlayWorkspace
+	}
+	ws, err := newWorkspace()
 	if err != nil {
 		setMutantErrors(mutants, fmt.Errorf("workspace creation failed: %w", err))
 		finalizeMutants(mutants)
@@ -177,15 +208,22 @@ func GenerateAndRunSchemata(ctx context.Context, sites []engine.Site, operators
 	}
 	defer ws.Cleanup()
 
+	end := trace.Begin("workspace setup", nil)
 	if err := ws.Setup(projectRoot, mutants); err != nil {
+		end()
 		setMutantErrors(mutants, fmt.Errorf("workspace setup failed: %w", err))
 		finalizeMutants(mutants)
 		return append(mutants, invalidMutants...), err
 	}
 
-	_ = MakeSelfContained(ws.TempDir)
+	if !ws.IsOverlay() {
+		_ = MakeSelfContained(ws.TempDir)
+	}
+	end()
 
+	end = trace.Begin("apply schemata", map[string]any{"mutants": len(mutants)})
 	_, hasNonStdlib, err := ws.applySchemata(mutants, log)
+	end()
 	if err != nil {
 		log.Warn("CRITICAL: Schemata application failed: %v", err)
 		setMutantErrors(mutants, fmt.Errorf("schemata application failed: %w", err))
@@ -194,12 +232,25 @@ func GenerateAndRunSchemata(ctx context.Context, sites []engine.Site, operators
 	}
 	log.Debug("Schemata application completed successfully")
 
+	if ws.IsOverlay() {
+		if err := ws.writeOverlay(); err != nil {
+			setMutantErrors(mutants, err)
+			finalizeMutants(mutants)
+			return append(mutants, invalidMutants...), err
+		}
+	}
+
 	//Verify the transformed code compiles with L4 retry logic
 	log.Debug("Verifying schemata-transformed code compiles...")
 	var removedByVerify []Mutant
+	end = trace.Begin("verify schemata", map[string]any{"mutants": len(mutants)})
 	mutants, removedByVerify, err = verifyAndCleanSchemata(ctx, ws, mutants, log)
+	end()
 	// Track removed mutants so they appear in final counts (compile-error status already set).
 	invalidMutants = append(invalidMutants, removedByVerify...)
+	for i := range removedByVerify {
+		emitMutantFinished(&removedByVerify[i], 0, projectRoot)
+	}
 	if err != nil {
 		if len(mutants) == 0 {
 			log.Warn("Schemata compilation failed with no recoverable mutants")
@@ -237,6 +288,11 @@ func GenerateAndRunSchemata(ctx context.Context, sites []engine.Site, operators
 		finalizeMutants(mutants)
 		return append(mutants, invalidMutants...), err
 	}
+	if cache != nil {
+		// Cached mutants stay in the schemata (keeping the transformed
+		// sources stable) but are not run again.
+		pkgToMutantIDs = filterCachedFromPkgMap(pkgToMutantIDs, mutants, mutantIDToIndex)
+	}
 
 	// DEBUG: expose key mismatch between the two package maps
 	log.Debug("[DEBUG-PKGMAP] buildPkgMap produced %d package keys:", len(pkgToMutantIDs))
@@ -324,8 +380,21 @@ func GenerateAndRunSchemata(ctx context.Context, sites []engine.Site, operators
 		if cfg != nil {
 			bt = cfg.BuildTags
 		}
-		log.Info("[UNIT] Running unit tests against %d mutant(s) across %d package(s)", len(mutants), len(pkgToMutantIDs))
-		results, err = compileAndRunPackages(ctx, ws.TempDir, pkgToMutantIDs, pkgToMutants, mutantSites, concurrent, testsByPkg, bt, prog, log)
+		env := ws.buildEnv()
+		if cfg != nil && cfg.BinaryCache {
+			bins, binErr := newBinaryCache(ctx, env, cfg.BinaryCacheMaxMB<<20, bt, log)
+			if binErr != nil {
+				log.Warn("binary cache disabled: %v", binErr)
+			} else {
+				env.bins = bins
+			}
+		}
+		pending := 0
+		for _, ids := range pkgToMutantIDs {
+			pending += len(ids)
+		}
+		log.Info("[UNIT] Running unit tests against %d mutant(s) across %d package(s)", pending, len(pkgToMutantIDs))
+		results, err = compileAndRunPackages(ctx, env, pkgToMutantIDs, pkgToMutants, mutantSites, concurrent, testsByPkg, bt, prog, log)
 
 		if len(results) > 0 {
 			collectResults(mutants, results, mutantIDToIndex, ws.TempDir)
@@ -342,7 +411,7 @@ func GenerateAndRunSchemata(ctx context.Context, sites []engine.Site, operators
 		// }
 
 		if err != nil {
-			SaveCache(mutants, baseDir, cache, fileHashes)
+			SaveCache(mutants, baseDir, cache, fingerprints)
 			finalizeMutants(mutants)
 			return append(mutants, invalidMutants...), err
 		}
@@ -365,12 +434,12 @@ func GenerateAndRunSchemata(ctx context.Context, sites []engine.Site, operators
 	// is marked untested so Total always equals the sum of all categories.
 	finalizeMutants(mutants)
 
-	SaveCache(mutants, baseDir, cache, fileHashes)
+	SaveCache(mutants, baseDir, cache, fingerprints)
 
 	return append(mutants, invalidMutants...), nil
 }
 
-func runStandalone(ctx context.Context, mutants []Mutant, uncachedIndices []int, concurrent int, cache *cache.Cache, baseDir string, testsByPkg map[string][]string, progbar bool, buildTags []string, fileHashes map[string]string, log *logger.Logger) ([]Mutant, error) {
+func runStandalone(ctx context.Context, mutants []Mutant, uncachedIndices []int, concurrent int, cache *cache.Cache, baseDir, eventRoot string, testsByPkg map[string][]string, progbar bool, buildTags []string, fingerprints *CacheFingerprints, log *logger.Logger) ([]Mutant, error) {
 
 	pkgToMutants := make(map[string][]*Mutant, len(uncachedIndices))
 	for _, idx := range uncachedIndices {
@@ -422,7 +491,7 @@ func runStandalone(ctx context.Context, mutants []Mutant, uncachedIndices []int,
 					pkgTests = tests
 				}
 			}
-			return runStandalonePackage(ctx, pkgDir, pkgMutants, concurrent, pkgTests, workerTempDir, progbar, buildTags, prog, log)
+			return runStandalonePackage(ctx, pkgDir, eventRoot, pkgMutants, concurrent, pkgTests, workerTempDir, progbar, buildTags, prog, log)
 		})
 	}
 
@@ -439,7 +508,7 @@ func runStandalone(ctx context.Context, mutants []Mutant, uncachedIndices []int,
 	}
 
 	finalizeMutants(mutants)
-	SaveCache(mutants, baseDir, cache, fileHashes)
+	SaveCache(mutants, baseDir, cache, fingerprints)
 
 	return mutants, nil
 }
@@ -834,14 +903,17 @@ func runExternalPhase(ctx context.Context, ws *ModuleWorkspace, mutants []Mutant
 
 // verifyBuildSequential builds all packages at once.
 // Returns combined error output and error if any package fails.
-func verifyBuildSequential(ctx context.Context, tempDir string, log *logger.Logger) (string, error) {
+// extraFlags are passed through verbatim (e.g. -overlay in overlay mode).
+func verifyBuildSequential(ctx context.Context, tempDir string, log *logger.Logger, extraFlags ...string) (string, error) {
 	// -gcflags=all=-e disables the default 10-error-per-package truncation so
 	// all bad mutant IDs can be extracted in a single round.
 	// Use "go test -run=^$ ./..." instead of "go build ./..." so that test
 	// files are compiled together with source files — this catches errors that
 	// only appear when the test binary is linked (e.g. undefined symbols that
 	// are only visible when _test.go files are included in the build).
-	cmd := exec.CommandContext(ctx, "go", "test", "-run=^$", "-gcflags=all=-e", "./...")
+	args := append([]string{"test", "-run=^$", "-gcflags=all=-e"}, extraFlags...)
+	args = append(args, "./...")
+	cmd := exec.CommandContext(ctx, "go", args...)
 	cmd.Dir = tempDir
 	out, err := cmd.CombinedOutput()
 	if err != nil {
@@ -907,11 +979,7 @@ func fileHasCompileErrors(buildOut string, ws *ModuleWorkspace, srcFile string)
 	}
 	want := filepath.Clean(filepath.Join(ws.TempDir, rel))
 	for _, ce := range ParseCompilerErrors(buildOut) {
-		ef := ce.File
-		if !filepath.IsAbs(ef) {
-			ef = filepath.Join(ws.TempDir, ef)
-		}
-		if filepath.Clean(ef) == want {
+		if ws.tempPathFor(ce.File) == want {
 			return true
 		}
 	}
@@ -936,7 +1004,7 @@ func bisectFileMutants(ctx context.Context, ws *ModuleWorkspace, srcFile string,
 		return nil, bad
 	}
 
-	buildOut, _ := verifyBuildSequential(ctx, ws.TempDir, log)
+	buildOut, _ := verifyBuildSequential(ctx, ws.buildDir(), log, ws.buildFlags()...)
 	if !fileHasCompileErrors(buildOut, ws, srcFile) {
 		// File compiles cleanly with this subset — every candidate here is good.
 		return candidates, nil
@@ -984,7 +1052,7 @@ func bisectBadMutants(ctx context.Context, ws *ModuleWorkspace, candidates []Mut
 		return nil, candidates
 	}
 	
-	if _, err := verifyBuildSequential(ctx, ws.TempDir, log); err == nil {
+	if _, err := verifyBuildSequential(ctx, ws.buildDir(), log, ws.buildFlags()...); err == nil {
 		return candidates, nil // all good
 	}
 	
@@ -1011,8 +1079,14 @@ func verifyAndCleanSchemata(ctx context.Context, ws *ModuleWorkspace, mutants []
 	verifyCtx, cancel := context.WithTimeout(context.Background(), 120*time.Second)
 	defer cancel()
 
+	// Each round is a span, ended when the next one starts or on return.
+	endRound := func() {}
+	defer func() { endRound() }()
+
 	for round := 0; round < maxRounds; round++ {
-		buildOut, buildErr := verifyBuildSequential(verifyCtx, ws.TempDir, log)
+		endRound()
+		endRound = trace.Begin(fmt.Sprintf("verify round %d", round+1), map[string]any{"mutants": len(mutants)})
+		buildOut, buildErr := verifyBuildSequential(verifyCtx, ws.buildDir(), log, ws.buildFlags()...)
 		if buildErr == nil {
 			if round > 0 {
 				log.Debug("[VERIFY] Build clean after %d removal round(s)", round)
@@ -1022,7 +1096,7 @@ func verifyAndCleanSchemata(ctx context.Context, ws *ModuleWorkspace, mutants []
 
 		log.Debug("[VERIFY] Round %d: build failed, scanning for bad mutant IDs", round+1)
 
-		badIDs := extractMutantIDsFromBuildErrors(ws.TempDir, buildOut)
+		badIDs := extractMutantIDsFromBuildErrors(ws.buildDir(), buildOut)
 		if len(badIDs) == 0 {
 			log.Debug("[VERIFY] Round %d: tight scan missed — falling back to per-file bisection", round+1)
 
@@ -1045,11 +1119,7 @@ func verifyAndCleanSchemata(ctx context.Context, ws *ModuleWorkspace, mutants []
 			// Map compiler errors → source files we know about.
 			failingFiles := make(map[string]bool)
 			for _, ce := range ParseCompilerErrors(buildOut) {
-				ef := ce.File
-				if !filepath.IsAbs(ef) {
-					ef = filepath.Join(ws.TempDir, ef)
-				}
-				if src, ok := tempToSrc[filepath.Clean(ef)]; ok {
+				if src, ok := tempToSrc[ws.tempPathFor(ce.File)]; ok {
 					failingFiles[src] = true
 				}
 			}
@@ -1073,7 +1143,9 @@ func verifyAndCleanSchemata(ctx context.Context, ws *ModuleWorkspace, mutants []
 				}
 				log.Debug("[VERIFY] Round %d: bisecting %d mutant(s) in %s",
 					round+1, len(fileMutants), filepath.Base(srcFile))
+				end := trace.Begin("bisect "+filepath.Base(srcFile), map[string]any{"mutants": len(fileMutants)})
 				_, bad := bisectFileMutants(ctx, ws, srcFile, fileMutants, log)
+				end()
 				for _, m := range bad {
 					badSet[m.ID] = true
 				}
@@ -1174,7 +1246,7 @@ func verifyAndCleanSchemata(ctx context.Context, ws *ModuleWorkspace, mutants []
 
 	// After max rounds, quarantine mutants in any still-failing packages so
 	// the rest of the workspace can compile and its tests can run.
-	if finalOut, finalErr := verifyBuildSequential(verifyCtx, ws.TempDir, log); finalErr != nil {
+	if finalOut, finalErr := verifyBuildSequential(verifyCtx, ws.buildDir(), log, ws.buildFlags()...); finalErr != nil {
 		failingPkgs := identifyFailingPackages(ws, finalOut)
 		if len(failingPkgs) == 0 {
 			log.Warn("[VERIFY] Still failing after %d rounds with no attributable packages — deferring %d mutant(s) to per-package compile", maxRounds, len(mutants))
@@ -1195,11 +1267,7 @@ func verifyAndCleanSchemata(ctx context.Context, ws *ModuleWorkspace, mutants []
 func identifyFailingPackages(ws *ModuleWorkspace, buildOutput string) map[string]bool {
 	failingPkgs := make(map[string]bool)
 	for _, ce := range ParseCompilerErrors(buildOutput) {
-		fp := ce.File
-		if !filepath.IsAbs(fp) {
-			fp = filepath.Join(ws.TempDir, fp)
-		}
-		failingPkgs[filepath.Dir(fp)] = true
+		failingPkgs[filepath.Dir(ws.tempPathFor(ce.File))] = true
 	}
 	return failingPkgs
 }
@@ -1438,6 +1506,25 @@ func filterKilledFromPkgMap(pkgToMutantIDs map[string][]int, mutants []Mutant, i
 	return filtered
 }
 
+// filterCachedFromPkgMap drops mutants whose status was resolved from the
+// result cache.
+func filterCachedFromPkgMap(pkgToMutantIDs map[string][]int, mutants []Mutant, idToIdx map[int]int) map[string][]int {
+	filtered := make(map[string][]int, len(pkgToMutantIDs))
+	for pkg, ids := range pkgToMutantIDs {
+		var kept []int
+		for _, id := range ids {
+			if idx, ok := idToIdx[id]; ok && mutants[idx].Status != "" {
+				continue
+			}
+			kept = append(kept, id)
+		}
+		if len(kept) > 0 {
+			filtered[pkg] = kept
+		}
+	}
+	return filtered
+}
+
 // Test helper for integration tests - calls GenerateAndRunSchemata with proper types
 func TestGenerateAndRunSchemata(ctx context.Context, sites []engine.Site, operators []mutator.Operator, allOps []mutator.Operator, baseDir string, projectRoot string, dirRules []config.DirOperatorRule, resolver *subconfig.Resolver, concurrent int, cache *cache.Cache, tests []string, testPaths []string, log *logger.Logger, progbar bool, unitTestsEnabled bool, externalCfg config.ExternalSuitesConfig, cfg *config.Config) ([]Mutant, error) {
 	testsByPkg := make(map[string][]string)
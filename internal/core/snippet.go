@@ -0,0 +1,49 @@
+package testing
+
+import (
+	"bytes"
+	"go/ast"
+	"go/format"
+	"go/token"
+	"path/filepath"
+
+	"github.com/aclfe/gorgon/internal/diff"
+	"github.com/aclfe/gorgon/internal/engine"
+)
+
+// setSnippets records the source of m's node before and after the operator
+// and the diff between them, so reports can show what a mutant looks like
+// without the reader applying the operator in their head.
+func setSnippets(m *Mutant, mutated ast.Node) {
+	m.Original = formatNode(m.Site, m.Site.Node)
+	if mutated != nil {
+		m.Mutated = formatNode(m.Site, mutated)
+	}
+	if m.Original == "" || m.Mutated == "" {
+		return
+	}
+	line := m.Site.Line
+	if m.Site.Fset != nil && m.Site.Node.Pos().IsValid() {
+		line = m.Site.Fset.Position(m.Site.Node.Pos()).Line
+	}
+	name := filepath.Base(m.Site.File.Name())
+	m.Diff = diff.Unified(name, name, m.Original, m.Mutated, line)
+}
+
+// formatNode renders n with go/format. The site's file set keeps the line
+// breaks of the original code; replacement nodes without positions are laid
+// out canonically.
+func formatNode(site engine.Site, n ast.Node) string {
+	if n == nil {
+		return ""
+	}
+	fset := site.Fset
+	if fset == nil {
+		fset = token.NewFileSet()
+	}
+	var buf bytes.Buffer
+	if err := format.Node(&buf, fset, n); err != nil {
+		return ""
+	}
+	return buf.String()
+}
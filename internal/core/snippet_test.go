@@ -0,0 +1,41 @@
+package testing
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/aclfe/gorgon/internal/diff"
+	"github.com/aclfe/gorgon/pkg/mutator/operators/arithmetic_flip"
+)
+
+func TestDescribeMutants_Snippets(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "calc.go")
+	writeTestFile(t, src, "package calc\n\nfunc Add(a, b int) int {\n\treturn a + b\n}\n")
+	mutants := binaryExprMutants(t, src, 1, arithmetic_flip.ArithmeticFlip{})
+	if len(mutants) != 1 {
+		t.Fatalf("expected one mutant, got %d", len(mutants))
+	}
+	m := mutants[0]
+	if m.Original != "a + b" || m.Mutated != "a - b" {
+		t.Fatalf("unexpected snippets %q -> %q", m.Original, m.Mutated)
+	}
+	want := "--- calc.go\n+++ calc.go\n@@ -4 +4 @@\n-a + b\n+a - b\n"
+	if m.Diff != want {
+		t.Fatalf("unexpected diff:\n%s\nwant:\n%s", m.Diff, want)
+	}
+}
+
+func TestUnifiedDiff_KeepsContextAndLineNumbers(t *testing.T) {
+	a := "switch x {\ncase 1:\n\tone()\ncase 2:\n\ttwo()\ncase 3:\n\tthree()\ncase 4:\n\tfour()\n}"
+	b := "switch x {\ncase 1:\n\tone()\ncase 2:\ncase 3:\n\tthree()\ncase 4:\n\tfour()\n}"
+	want := "--- f.go\n+++ f.go\n" +
+		"@@ -11,7 +11,6 @@\n" +
+		" case 1:\n \tone()\n case 2:\n-\ttwo()\n case 3:\n \tthree()\n case 4:\n"
+	if got := diff.Unified("f.go", "f.go", a, b, 10); got != want {
+		t.Fatalf("unexpected diff:\n%s\nwant:\n%s", got, want)
+	}
+	if got := diff.Unified("f.go", "f.go", a, a, 10); got != "" {
+		t.Fatalf("expected no diff for equal input, got %q", got)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"bufio"
 	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"go/ast"
 	"os"
@@ -21,12 +22,20 @@ import (
 
 func maxConcurrency() int { return runtime.NumCPU() }
 
+// overlayFileName is the `go build -overlay` description written into TempDir
+// when the workspace runs in overlay mode.
+const overlayFileName = "overlay.json"
+
 type ModuleWorkspace struct {
 	TempDir      string
 	absModule    string
 	goWork       *gowork.Workspace
 	fileRelPaths map[string]string
 	mu           sync.Mutex
+	// overlay is true when TempDir holds only the schemata-transformed files
+	// and helpers, and every go command runs against the original module with
+	// -overlay instead of against a full copy.
+	overlay bool
 }
 
 func NewModuleWorkspace() (*ModuleWorkspace, error) {
@@ -40,6 +49,113 @@ func NewModuleWorkspace() (*ModuleWorkspace, error) {
 	}, nil
 }
 
+// NewOverlayWorkspace returns a workspace that never copies the module. Setup
+// only resolves the module root; applySchemata writes transformed files into a
+// scratch TempDir that mirrors the module layout, and writeOverlay maps each of
+// them onto its original path for `go test -overlay`.
+func NewOverlayWorkspace() (*ModuleWorkspace, error) {
+	ws, err := NewModuleWorkspace()
+	if err != nil {
+		return nil, err
+	}
+	ws.overlay = true
+	return ws, nil
+}
+
+// IsOverlay reports whether the workspace builds the original module in place.
+func (w *ModuleWorkspace) IsOverlay() bool { return w.overlay }
+
+// buildDir is the directory go commands run in: the temp copy in copy mode,
+// the original module (or go.work) root in overlay mode.
+func (w *ModuleWorkspace) buildDir() string {
+	if w.overlay {
+		return w.absModule
+	}
+	return w.TempDir
+}
+
+// buildFlags returns the extra flags every go build/test/list invocation needs.
+func (w *ModuleWorkspace) buildFlags() []string {
+	if w.overlay {
+		return []string{"-overlay=" + filepath.Join(w.TempDir, overlayFileName)}
+	}
+	return nil
+}
+
+// buildEnv bundles buildDir/buildFlags for the per-package executors.
+func (w *ModuleWorkspace) buildEnv() buildEnv {
+	return buildEnv{
+		tempDir:     w.TempDir,
+		buildDir:    w.buildDir(),
+		projectRoot: w.absModule,
+		flags:       w.buildFlags(),
+		sourcePath:  w.sourcePath,
+	}
+}
+
+// sourcePath returns the file the build actually reads for p: its scratch
+// copy under TempDir when the overlay replaces it, p itself otherwise.
+func (w *ModuleWorkspace) sourcePath(p string) string {
+	if !w.overlay {
+		return p
+	}
+	if scratch := w.tempPathFor(p); scratch != filepath.Clean(p) {
+		if _, err := os.Stat(scratch); err == nil {
+			return scratch
+		}
+	}
+	return p
+}
+
+// tempPathFor maps a file path reported by the compiler onto its location
+// under TempDir. Relative paths are resolved against buildDir. In overlay mode
+// errors in files we did not transform name the original module, so they are
+// rebased onto TempDir to keep package-level attribution consistent.
+func (w *ModuleWorkspace) tempPathFor(p string) string {
+	if !filepath.IsAbs(p) {
+		p = filepath.Join(w.buildDir(), p)
+	}
+	p = filepath.Clean(p)
+	if w.overlay {
+		if rel, err := filepath.Rel(w.absModule, p); err == nil && !strings.HasPrefix(rel, "..") {
+			return filepath.Join(w.TempDir, rel)
+		}
+	}
+	return p
+}
+
+// writeOverlay records every .go file under TempDir in overlay.json, keyed by
+// the path it replaces in the original module. Files that do not exist in the
+// module (the gorgon_schemata.go helpers) are added to the build by the same
+// mechanism.
+func (w *ModuleWorkspace) writeOverlay() error {
+	replace := make(map[string]string)
+	err := filepath.Walk(w.TempDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() || !strings.HasSuffix(path, ".go") {
+			return nil
+		}
+		rel, err := filepath.Rel(w.TempDir, path)
+		if err != nil {
+			return nil
+		}
+		replace[filepath.Join(w.absModule, rel)] = path
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to scan overlay dir: %w", err)
+	}
+	data, err := json.MarshalIndent(struct {
+		Replace map[string]string
+	}{replace}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal overlay: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(w.TempDir, overlayFileName), data, filePermissions); err != nil {
+		return fmt.Errorf("failed to write overlay: %w", err)
+	}
+	return nil
+}
+
 func (w *ModuleWorkspace) relPath(filePath string) (string, error) {
 	w.mu.Lock()
 	defer w.mu.Unlock()
@@ -98,6 +214,11 @@ func (w *ModuleWorkspace) Setup(baseDir string, mutants []Mutant) error {
 		moduleRoots = []string{abs}
 	}
 
+	// Overlay mode builds the original tree in place; nothing to copy.
+	if w.overlay {
+		return nil
+	}
+
 	mutatedPaths := make(map[string]bool, len(mutants))
 	for i := range mutants {
 		if mutants[i].Site.File != nil {
@@ -301,7 +422,8 @@ func (w *ModuleWorkspace) buildPkgMap(mutants []Mutant) (map[string][]int, map[i
 func (w *ModuleWorkspace) simplifyGoMod(hasNonStdlib bool) {
 	// Never strip go.mod content when a workspace is active —
 	// member modules may reference each other through go.work.
-	if hasNonStdlib || w.goWork != nil {
+	// Overlay mode builds against the user's own go.mod, which stays untouched.
+	if hasNonStdlib || w.goWork != nil || w.overlay {
 		return
 	}
 
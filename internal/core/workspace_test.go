@@ -0,0 +1,76 @@
+package testing
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func TestOverlayWorkspace_AttributesCompileErrorsToScratchFile(t *testing.T) {
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go toolchain not available")
+	}
+
+	module := t.TempDir()
+	writeTestFile(t, filepath.Join(module, "go.mod"), "module example.com/ov\n\ngo 1.21\n")
+	orig := filepath.Join(module, "calc", "calc.go")
+	writeTestFile(t, orig, "package calc\n\nfunc Add(a, b int) int { return a + b }\n")
+
+	ws, err := NewOverlayWorkspace()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(ws.TempDir)
+	if err := ws.Setup(module, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	scratch := filepath.Join(ws.TempDir, "calc", "calc.go")
+	writeTestFile(t, scratch, `package calc
+
+var activeMutantID = 0
+
+func Add(a, b int) int {
+	if activeMutantID == 1 {
+		return undefinedName
+	}
+	return a + b
+}
+`)
+	if err := ws.writeOverlay(); err != nil {
+		t.Fatal(err)
+	}
+
+	args := append([]string{"test", "-c", "-o", os.DevNull}, ws.buildFlags()...)
+	cmd := exec.Command("go", append(args, "./calc")...)
+	cmd.Dir = ws.buildDir()
+	out, err := cmd.CombinedOutput()
+	if err == nil {
+		t.Fatalf("expected compile failure, got success: %s", out)
+	}
+
+	sites := map[int]MutantSite{1: {File: orig, Line: 3, Col: 33}}
+	res := attributeCompileErrors(ws.TempDir, ws.buildDir(), module, []int{1}, sites, string(out))
+	if !res.attributed[1] {
+		t.Fatalf("expected mutant 1 to be attributed, output:\n%s", out)
+	}
+
+	got, err := os.ReadFile(orig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "package calc\n\nfunc Add(a, b int) int { return a + b }\n" {
+		t.Fatalf("overlay build modified the original module: %q", got)
+	}
+}
+
+func writeTestFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
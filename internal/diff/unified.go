@@ -0,0 +1,153 @@
+package diff
+
+import (
+	"fmt"
+	"strings"
+)
+
+// contextLines is the number of unchanged lines kept around each change.
+const contextLines = 3
+
+// maxLCSCells bounds the line-matching table; larger inputs are rendered as a
+// single replacement hunk.
+const maxLCSCells = 1 << 20
+
+type edit struct {
+	kind byte // ' ', '-' or '+'
+	text string
+}
+
+// Unified renders a unified diff from a to b. Hunk line numbers start at
+// firstLine, so a snippet taken from the middle of a file keeps the file's
+// line numbers. It returns "" when a and b are equal.
+func Unified(fromName, toName, a, b string, firstLine int) string {
+	if a == b {
+		return ""
+	}
+	edits := editScript(splitLines(a), splitLines(b))
+
+	// Keep every change plus its surrounding context.
+	keep := make([]bool, len(edits))
+	for i, e := range edits {
+		if e.kind == ' ' {
+			continue
+		}
+		for j := max(i-contextLines, 0); j <= min(i+contextLines, len(edits)-1); j++ {
+			keep[j] = true
+		}
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "--- %s\n+++ %s\n", fromName, toName)
+	aLine, bLine := firstLine, firstLine
+	for i := 0; i < len(edits); {
+		if !keep[i] {
+			aLine, bLine = advance(edits[i], aLine, bLine)
+			i++
+			continue
+		}
+		end := i
+		for end < len(edits) && keep[end] {
+			end++
+		}
+		aStart, bStart := aLine, bLine
+		var body strings.Builder
+		for _, e := range edits[i:end] {
+			body.WriteByte(e.kind)
+			body.WriteString(e.text)
+			body.WriteByte('\n')
+			aLine, bLine = advance(e, aLine, bLine)
+		}
+		fmt.Fprintf(&sb, "@@ -%s +%s @@\n", hunkRange(aStart, aLine-aStart), hunkRange(bStart, bLine-bStart))
+		sb.WriteString(body.String())
+		i = end
+	}
+	return sb.String()
+}
+
+func advance(e edit, aLine, bLine int) (int, int) {
+	switch e.kind {
+	case '-':
+		aLine++
+	case '+':
+		bLine++
+	default:
+		aLine++
+		bLine++
+	}
+	return aLine, bLine
+}
+
+// hunkRange formats one side of a hunk header the way diff(1) does: the
+// count is omitted when it is 1, and an empty side names the line before it.
+func hunkRange(start, count int) string {
+	switch count {
+	case 0:
+		return fmt.Sprintf("%d,0", start-1)
+	case 1:
+		return fmt.Sprintf("%d", start)
+	}
+	return fmt.Sprintf("%d,%d", start, count)
+}
+
+func splitLines(s string) []string {
+	s = strings.TrimSuffix(s, "\n")
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, "\n")
+}
+
+// editScript returns a shortest edit turning a into b, based on the longest
+// common subsequence of lines.
+func editScript(a, b []string) []edit {
+	if len(a)*len(b) > maxLCSCells {
+		edits := make([]edit, 0, len(a)+len(b))
+		for _, l := range a {
+			edits = append(edits, edit{'-', l})
+		}
+		for _, l := range b {
+			edits = append(edits, edit{'+', l})
+		}
+		return edits
+	}
+
+	// lcs[i][j] is the LCS length of a[i:] and b[j:].
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	edits := make([]edit, 0, len(a)+len(b))
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			edits = append(edits, edit{' ', a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			edits = append(edits, edit{'-', a[i]})
+			i++
+		default:
+			edits = append(edits, edit{'+', b[j]})
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		edits = append(edits, edit{'-', a[i]})
+	}
+	for ; j < len(b); j++ {
+		edits = append(edits, edit{'+', b[j]})
+	}
+	return edits
+}
@@ -0,0 +1,113 @@
+package engine
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"go/ast"
+	"go/printer"
+	"go/token"
+	"strconv"
+	"strings"
+)
+
+// FuncIdentity names fn the way cache keys refer to it: "Name" for
+// functions and "Recv.Name" for methods, so a method and a function of the
+// same name in one file stay apart.
+func FuncIdentity(fn *ast.FuncDecl) string {
+	if fn.Recv == nil || len(fn.Recv.List) == 0 {
+		return fn.Name.Name
+	}
+	t := fn.Recv.List[0].Type
+	for {
+		switch x := t.(type) {
+		case *ast.StarExpr:
+			t = x.X
+			continue
+		case *ast.IndexExpr:
+			t = x.X
+			continue
+		case *ast.IndexListExpr:
+			t = x.X
+			continue
+		case *ast.ParenExpr:
+			t = x.X
+			continue
+		}
+		break
+	}
+	if id, ok := t.(*ast.Ident); ok {
+		return id.Name + "." + fn.Name.Name
+	}
+	return fn.Name.Name
+}
+
+// FuncHash hashes the normalised source of fn (see NormalizedSource) without
+// its doc comment, so only edits that change the function's code change the
+// hash; moving it within the file or editing around it does not.
+func FuncHash(fn *ast.FuncDecl) string {
+	decl := *fn
+	decl.Doc = nil
+	src := NormalizedSource(&decl)
+	if src == "" {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(src))
+	return hex.EncodeToString(sum[:])
+}
+
+// NormalizedSource prints n without comments and without position
+// information, which yields gofmt's canonical layout independent of how the
+// original was formatted. It returns "" when n cannot be printed.
+func NormalizedSource(n ast.Node) string {
+	buf := bufPool.Get().(*bytes.Buffer)
+	buf.Reset()
+	defer bufPool.Put(buf)
+	cfg := printer.Config{Mode: printer.RawFormat}
+	if err := cfg.Fprint(buf, token.NewFileSet(), n); err != nil {
+		return ""
+	}
+	return buf.String()
+}
+
+// NodePath locates node within root independently of file positions and
+// comments: each step is the node type and its index among the children of
+// its parent, in ast.Inspect order. It returns "" when node is not under
+// root.
+func NodePath(root, node ast.Node) string {
+	var (
+		steps []string
+		found bool
+	)
+	// counts[i] is the number of children seen so far at depth i.
+	counts := []int{0}
+	ast.Inspect(root, func(n ast.Node) bool {
+		if found {
+			return false
+		}
+		if n == nil {
+			counts = counts[:len(counts)-1]
+			steps = steps[:len(steps)-1]
+			return true
+		}
+		switch n.(type) {
+		case *ast.CommentGroup, *ast.Comment:
+			// Comments are not code; adding one must not shift its siblings.
+			return false
+		}
+		idx := counts[len(counts)-1]
+		counts[len(counts)-1]++
+		steps = append(steps, fmt.Sprintf("%T", n)[len("*ast."):]+"."+strconv.Itoa(idx))
+		if n == node {
+			found = true
+			return false
+		}
+		counts = append(counts, 0)
+		return true
+	})
+	if !found {
+		return ""
+	}
+	return strings.Join(steps, "/")
+}
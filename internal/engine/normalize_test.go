@@ -0,0 +1,66 @@
+//go:build unit
+// +build unit
+
+package engine
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"testing"
+)
+
+func parseFunc(t *testing.T, src, name string) *ast.FuncDecl {
+	t.Helper()
+	f, err := parser.ParseFile(token.NewFileSet(), "x.go", src, parser.ParseComments)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, d := range f.Decls {
+		if fn, ok := d.(*ast.FuncDecl); ok && FuncIdentity(fn) == name {
+			return fn
+		}
+	}
+	t.Fatalf("no function %s", name)
+	return nil
+}
+
+func firstReturn(fn *ast.FuncDecl) ast.Node {
+	var ret ast.Node
+	ast.Inspect(fn, func(n ast.Node) bool {
+		if _, ok := n.(*ast.ReturnStmt); ok && ret == nil {
+			ret = n
+		}
+		return ret == nil
+	})
+	return ret
+}
+
+// TestFuncHash_IgnoresLayoutAndComments verifies only code edits change the
+// normalised function hash and node path
+func TestFuncHash_IgnoresLayoutAndComments(t *testing.T) {
+	a := parseFunc(t, "package p\n\nfunc F(x int) int { return x * 2 }\n", "F")
+	b := parseFunc(t, "package p\n\nimport \"os\"\n\n// F doubles.\nfunc F(x int) int {\n\t// twice\n\n\treturn x * 2\n}\n\nvar _ = os.Args\n", "F")
+	c := parseFunc(t, "package p\n\nfunc F(x int) int { return x * 3 }\n", "F")
+
+	if FuncHash(a) == "" || FuncHash(a) != FuncHash(b) {
+		t.Fatalf("expected equal hashes for layout-only edits: %q vs %q", FuncHash(a), FuncHash(b))
+	}
+	if FuncHash(a) == FuncHash(c) {
+		t.Fatal("expected a code edit to change the hash")
+	}
+	if pa, pb := NodePath(a, firstReturn(a)), NodePath(b, firstReturn(b)); pa == "" || pa != pb {
+		t.Fatalf("expected equal node paths: %q vs %q", pa, pb)
+	}
+	if NodePath(a, firstReturn(c)) != "" {
+		t.Fatal("expected no path for a node outside root")
+	}
+}
+
+// TestFuncIdentity_QualifiesMethods verifies methods are named after their
+// receiver type, through pointers and type parameters
+func TestFuncIdentity_QualifiesMethods(t *testing.T) {
+	src := "package p\n\ntype T[K any] struct{}\n\nfunc (t *T[K]) Get() {}\n\nfunc Get() {}\n"
+	parseFunc(t, src, "T.Get")
+	parseFunc(t, src, "Get")
+}
@@ -0,0 +1,163 @@
+// Package events is the structured stream of what a run is doing: which
+// mutants it will run, which packages it compiles and how each mutant fares.
+// The engine emits events whether or not anyone listens; the live dashboard
+// and the `events:` output subscribe to them.
+package events
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+)
+
+// Type names an event.
+type Type string
+
+const (
+	// RunStarted is sent when a run starts, with the project Root.
+	RunStarted Type = "run_started"
+	// PackagesDiscovered is sent once mutants are generated: Total is every
+	// generated mutant and Packages counts them by package.
+	PackagesDiscovered Type = "packages_discovered"
+	// PreflightFinished carries the Preflight counts; a MutantFinished
+	// follows for every mutant it rejected.
+	PreflightFinished Type = "preflight_finished"
+	// CompileStarted and CompileFinished bracket building the test binary
+	// of Package; Failures lists the mutants that failed to compile.
+	CompileStarted  Type = "compile_started"
+	CompileFinished Type = "compile_finished"
+	// MutantStarted is sent when Worker starts running Mutant's tests.
+	MutantStarted Type = "mutant_started"
+	// MutantFinished is sent when Mutant has a status, including mutants
+	// rejected by preflight or restored from the cache.
+	MutantFinished Type = "mutant_finished"
+	// RunFinished carries the Stats of the run.
+	RunFinished Type = "run_finished"
+)
+
+// Event is one thing that happened during a run.
+type Event struct {
+	Type      Type             `json:"type"`
+	Time      time.Time        `json:"time"`
+	Root      string           `json:"root,omitempty"`
+	Package   string           `json:"package,omitempty"` // directory relative to the project root
+	Worker    int              `json:"worker,omitempty"`  // test worker lane, from 1
+	Mutant    *Mutant          `json:"mutant,omitempty"`
+	Total     int              `json:"total,omitempty"`
+	Packages  map[string]int   `json:"packages,omitempty"`
+	Preflight *Preflight       `json:"preflight,omitempty"`
+	Failures  []CompileFailure `json:"failures,omitempty"`
+	Stats     *Stats           `json:"stats,omitempty"`
+}
+
+// Mutant describes the mutant an event is about.
+type Mutant struct {
+	ID          int     `json:"id"`
+	Fingerprint string  `json:"fingerprint,omitempty"`
+	File        string  `json:"file"`
+	Line        int     `json:"line"`
+	Column      int     `json:"column"`
+	Operator    string  `json:"operator"`
+	Status      string  `json:"status,omitempty"`
+	KilledBy    string  `json:"killed_by,omitempty"`
+	DurationMS  float64 `json:"duration_ms,omitempty"`
+	Cached      bool    `json:"cached,omitempty"`    // result restored from the cache
+	Preflight   bool    `json:"preflight,omitempty"` // rejected before any build
+}
+
+// Preflight counts the mutants checked before any build.
+type Preflight struct {
+	Checked       int `json:"checked"`
+	Valid         int `json:"valid"`
+	Invalid       int `json:"invalid"`        // rejected by the static checks
+	CompileErrors int `json:"compile_errors"` // rejected by the AST and type checks
+}
+
+// CompileFailure is a mutant that failed to compile with its package.
+type CompileFailure struct {
+	MutantID int `json:"mutant_id"`
+	// Attributed is whether the compiler error was pinned to the mutant's
+	// own code rather than failing the package as a whole.
+	Attributed bool   `json:"attributed"`
+	Error      string `json:"error"`
+}
+
+// Stats is the outcome of a run.
+type Stats struct {
+	Score    float64 `json:"score"`
+	Total    int     `json:"total"`
+	Killed   int     `json:"killed"`
+	Survived int     `json:"survived"`
+	Timeout  int     `json:"timeout"`
+	Untested int     `json:"untested"`
+	Errors   int     `json:"errors"`
+	Invalid  int     `json:"invalid"`
+}
+
+var (
+	mu          sync.RWMutex
+	subscribers = make(map[int]func(Event))
+	nextID      int
+)
+
+// Subscribe calls fn with every event emitted until cancel is called. fn
+// runs on the emitting goroutine, so it must not block.
+func Subscribe(fn func(Event)) (cancel func()) {
+	mu.Lock()
+	defer mu.Unlock()
+	id := nextID
+	nextID++
+	subscribers[id] = fn
+	return func() {
+		mu.Lock()
+		defer mu.Unlock()
+		delete(subscribers, id)
+	}
+}
+
+// Enabled reports whether anyone is subscribed, so emitters can skip
+// building events nobody reads.
+func Enabled() bool {
+	mu.RLock()
+	defer mu.RUnlock()
+	return len(subscribers) > 0
+}
+
+// Emit sends e to every subscriber, stamping its time if unset.
+func Emit(e Event) {
+	mu.RLock()
+	defer mu.RUnlock()
+	if len(subscribers) == 0 {
+		return
+	}
+	if e.Time.IsZero() {
+		e.Time = time.Now()
+	}
+	for _, fn := range subscribers {
+		fn(e)
+	}
+}
+
+// Record writes every event emitted until stop is called to w, one JSON
+// object per line. stop returns the first write error.
+func Record(w io.Writer) (stop func() error) {
+	var (
+		wmu  sync.Mutex
+		werr error
+		enc  = json.NewEncoder(w)
+	)
+	cancel := Subscribe(func(e Event) {
+		wmu.Lock()
+		defer wmu.Unlock()
+		if werr == nil {
+			werr = enc.Encode(e)
+		}
+	})
+	return func() error {
+		cancel()
+		wmu.Lock()
+		defer wmu.Unlock()
+		return werr
+	}
+}
@@ -0,0 +1,78 @@
+//go:build unit
+// +build unit
+
+package events
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestSubscribe_ReceivesUntilCancelled(t *testing.T) {
+	if Enabled() {
+		t.Fatal("Enabled with no subscribers")
+	}
+	var got []Event
+	cancel := Subscribe(func(e Event) { got = append(got, e) })
+	if !Enabled() {
+		t.Fatal("not Enabled with a subscriber")
+	}
+
+	Emit(Event{Type: RunStarted, Total: 3})
+	cancel()
+	Emit(Event{Type: RunFinished})
+
+	if len(got) != 1 || got[0].Type != RunStarted || got[0].Total != 3 {
+		t.Fatalf("events = %+v, want the run_started only", got)
+	}
+	if got[0].Time.IsZero() {
+		t.Error("Emit did not stamp the event's time")
+	}
+	if Enabled() {
+		t.Error("Enabled after cancel")
+	}
+}
+
+func TestRecord_WritesOneEventPerLine(t *testing.T) {
+	var buf bytes.Buffer
+	stop := Record(&buf)
+	Emit(Event{Type: PackagesDiscovered, Total: 2, Packages: map[string]int{"calc": 2}})
+	Emit(Event{Type: CompileFinished, Package: "calc", Failures: []CompileFailure{{MutantID: 1, Attributed: true, Error: "undefined: x"}}})
+	if err := stop(); err != nil {
+		t.Fatal(err)
+	}
+	Emit(Event{Type: RunFinished})
+
+	var got []Event
+	sc := bufio.NewScanner(&buf)
+	for sc.Scan() {
+		var e Event
+		if err := json.Unmarshal(sc.Bytes(), &e); err != nil {
+			t.Fatalf("line %q: %v", sc.Text(), err)
+		}
+		got = append(got, e)
+	}
+	if len(got) != 2 || got[0].Packages["calc"] != 2 || got[1].Failures[0].MutantID != 1 || !got[1].Failures[0].Attributed {
+		t.Fatalf("recorded %+v", got)
+	}
+}
+
+type failingWriter struct{ n int }
+
+func (w *failingWriter) Write(p []byte) (int, error) {
+	w.n++
+	return 0, errors.New("disk full")
+}
+
+func TestRecord_StopsWritingAfterError(t *testing.T) {
+	w := &failingWriter{}
+	stop := Record(w)
+	Emit(Event{Type: RunStarted})
+	Emit(Event{Type: RunFinished})
+	if err := stop(); err == nil || w.n != 1 {
+		t.Errorf("stop = %v after %d writes, want the error after 1", err, w.n)
+	}
+}
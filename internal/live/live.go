@@ -0,0 +1,200 @@
+// Package live serves the dashboard of a run in progress: a page that
+// follows the run's events over Server-Sent Events and, once the run is
+// over, switches to its HTML report.
+package live
+
+import (
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/aclfe/gorgon/internal/events"
+)
+
+// dashboardPage is served at /.
+//
+//go:embed dashboard.html
+var dashboardPage []byte
+
+// clientBuffer is how many messages a slow browser may fall behind before
+// it is disconnected; it reconnects and is replayed the run so far.
+const clientBuffer = 4096
+
+// reportReady is sent once the HTML report can be fetched from /report.
+var reportReady = []byte(`{"type":"report_ready"}`)
+
+// Server is the live dashboard of one run.
+type Server struct {
+	ln     net.Listener
+	srv    *http.Server
+	cancel func() // ends the events subscription
+
+	mu      sync.Mutex
+	backlog [][]byte // messages replayed to browsers that connect late
+	clients map[chan []byte]struct{}
+	report  []byte // nil until the run is over
+}
+
+// Start listens on addr and follows the events of the run from now on.
+func Start(addr string) (*Server, error) {
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start live dashboard on %s: %w", addr, err)
+	}
+	s := &Server{ln: ln, clients: make(map[chan []byte]struct{})}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", s.handleDashboard)
+	mux.HandleFunc("/events", s.handleEvents)
+	mux.HandleFunc("/report", s.handleReport)
+	s.srv = &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+
+	s.cancel = events.Subscribe(s.publishEvent)
+	go func() { _ = s.srv.Serve(ln) }()
+	return s, nil
+}
+
+// URL is where the dashboard can be opened.
+func (s *Server) URL() string {
+	host, port, err := net.SplitHostPort(s.ln.Addr().String())
+	if err != nil {
+		return "http://" + s.ln.Addr().String()
+	}
+	if ip := net.ParseIP(host); ip == nil || ip.IsUnspecified() {
+		host = "localhost"
+	}
+	return "http://" + net.JoinHostPort(host, port) + "/"
+}
+
+// SetReport publishes the HTML report of the finished run; the dashboard
+// switches to it.
+func (s *Server) SetReport(page []byte) {
+	s.mu.Lock()
+	s.report = page
+	s.mu.Unlock()
+	s.publish(reportReady, true)
+}
+
+// Close stops following events and shuts the server down.
+func (s *Server) Close() error {
+	s.cancel()
+	s.mu.Lock()
+	for c := range s.clients {
+		close(c)
+		delete(s.clients, c)
+	}
+	s.mu.Unlock()
+	return s.srv.Close()
+}
+
+func (s *Server) publishEvent(e events.Event) {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return
+	}
+	// What workers are doing right now is of no use to a browser that
+	// connects later; keeping it out of the backlog keeps that to a few
+	// hundred bytes per mutant.
+	keep := e.Type != events.MutantStarted && e.Type != events.CompileStarted
+	s.publish(data, keep)
+}
+
+func (s *Server) publish(msg []byte, keep bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if keep {
+		s.backlog = append(s.backlog, msg)
+	}
+	for c := range s.clients {
+		select {
+		case c <- msg:
+		default:
+			close(c)
+			delete(s.clients, c)
+		}
+	}
+}
+
+func (s *Server) handleDashboard(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/" {
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	_, _ = w.Write(dashboardPage)
+}
+
+func (s *Server) handleReport(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	page := s.report
+	s.mu.Unlock()
+	if page == nil {
+		http.Error(w, "the run is still in progress", http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	_, _ = w.Write(page)
+}
+
+// handleEvents streams the run to a browser: a reset message, the backlog,
+// then every message as it is published.
+func (s *Server) handleEvents(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+
+	c := make(chan []byte, clientBuffer)
+	s.mu.Lock()
+	backlog := s.backlog
+	s.clients[c] = struct{}{}
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		if _, ok := s.clients[c]; ok {
+			close(c)
+			delete(s.clients, c)
+		}
+		s.mu.Unlock()
+	}()
+
+	if err := writeMessage(w, []byte(`{"type":"reset"}`)); err != nil {
+		return
+	}
+	for _, msg := range backlog {
+		if err := writeMessage(w, msg); err != nil {
+			return
+		}
+	}
+	flusher.Flush()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case msg, ok := <-c:
+			if !ok {
+				return
+			}
+			if err := writeMessage(w, msg); err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+func writeMessage(w http.ResponseWriter, msg []byte) error {
+	if _, err := fmt.Fprintf(w, "data: %s\n\n", msg); err != nil {
+		return errors.New("client went away")
+	}
+	return nil
+}
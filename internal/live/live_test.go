@@ -0,0 +1,104 @@
+//go:build unit
+// +build unit
+
+package live
+
+import (
+	"bufio"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aclfe/gorgon/internal/events"
+)
+
+func get(t *testing.T, url string) (int, string) {
+	t.Helper()
+	resp, err := http.Get(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp.StatusCode, string(body)
+}
+
+func TestServer_ReplaysRunAndServesReport(t *testing.T) {
+	s, err := Start("127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	if code, body := get(t, s.URL()); code != http.StatusOK || !strings.Contains(body, "EventSource") {
+		t.Fatalf("dashboard = %d %q", code, body)
+	}
+	if code, _ := get(t, s.URL()+"report"); code != http.StatusNotFound {
+		t.Fatalf("report before the run finished = %d, want 404", code)
+	}
+
+	events.Emit(events.Event{Type: events.PackagesDiscovered, Total: 2})
+	events.Emit(events.Event{Type: events.MutantStarted, Worker: 1, Mutant: &events.Mutant{ID: 1}})
+	events.Emit(events.Event{Type: events.MutantFinished, Worker: 1, Mutant: &events.Mutant{ID: 1, Status: "survived"}})
+
+	// A browser connecting now is replayed the run so far, without what
+	// workers were doing, then follows it live.
+	resp, err := http.Get(s.URL() + "events")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	lines := make(chan string)
+	go func() {
+		sc := bufio.NewScanner(resp.Body)
+		for sc.Scan() {
+			if data, ok := strings.CutPrefix(sc.Text(), "data: "); ok {
+				lines <- data
+			}
+		}
+		close(lines)
+	}()
+	next := func() string {
+		select {
+		case l := <-lines:
+			return l
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for an event")
+			return ""
+		}
+	}
+
+	if got := next(); got != `{"type":"reset"}` {
+		t.Fatalf("first message = %s", got)
+	}
+	if got := next(); !strings.Contains(got, `"type":"packages_discovered"`) {
+		t.Fatalf("second message = %s", got)
+	}
+	if got := next(); !strings.Contains(got, `"type":"mutant_finished"`) || !strings.Contains(got, `"status":"survived"`) {
+		t.Fatalf("third message = %s", got)
+	}
+
+	s.SetReport([]byte("<html>report</html>"))
+	if got := next(); got != `{"type":"report_ready"}` {
+		t.Fatalf("message after SetReport = %s", got)
+	}
+	if code, body := get(t, s.URL()+"report"); code != http.StatusOK || body != "<html>report</html>" {
+		t.Fatalf("report = %d %q", code, body)
+	}
+}
+
+func TestServer_URLNamesLocalhostForUnspecifiedHost(t *testing.T) {
+	s, err := Start(":0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	if !strings.HasPrefix(s.URL(), "http://localhost:") {
+		t.Errorf("URL = %q", s.URL())
+	}
+}
@@ -0,0 +1,233 @@
+package reporter
+
+import (
+	"go/ast"
+	"path/filepath"
+	"sort"
+
+	testing "github.com/aclfe/gorgon/internal/core"
+)
+
+// Summary is the stats of one node of the breakdown.
+type Summary struct {
+	Name  string      `json:"name"`
+	Stats ReportStats `json:"stats"`
+}
+
+// Scored reports whether any mutant of the node counts towards its score.
+func (s Summary) Scored() bool {
+	return s.Stats.Killed+s.Stats.Survived+s.Stats.Untested+s.Stats.Timeout > 0
+}
+
+// PackageSummary is a package directory, named relative to the project root.
+type PackageSummary struct {
+	Summary
+	Files []FileSummary `json:"files"`
+}
+
+// FileSummary is a file, named relative to the project root.
+type FileSummary struct {
+	Summary
+	Path      string            `json:"-"` // as the mutants name it
+	Functions []FunctionSummary `json:"functions"`
+}
+
+// FunctionSummary is a function or method; mutants outside any function
+// are grouped under packageLevel.
+type FunctionSummary struct {
+	Summary
+	Line int `json:"line"` // where the function is declared
+}
+
+const packageLevel = "(package level)"
+
+// Breakdown is the module → package → file → function aggregation of a
+// run, computed once per report and shared by every format.
+type Breakdown struct {
+	Packages  []PackageSummary
+	Operators []OperatorStats
+}
+
+// Aggregate builds the breakdown of mutants, naming packages and files
+// relative to root.
+func Aggregate(mutants []testing.Mutant, root string) Breakdown {
+	rel := rootRelativizer(root)
+
+	type fileGroup struct {
+		path  string
+		all   []testing.Mutant
+		funcs map[string][]testing.Mutant
+	}
+	files := make(map[string]*fileGroup)
+	for _, m := range mutants {
+		if m.Site.File == nil {
+			continue
+		}
+		path := m.Site.File.Name()
+		g := files[path]
+		if g == nil {
+			g = &fileGroup{path: path, funcs: make(map[string][]testing.Mutant)}
+			files[path] = g
+		}
+		g.all = append(g.all, m)
+		fn := funcName(m)
+		g.funcs[fn] = append(g.funcs[fn], m)
+	}
+
+	pkgFiles := make(map[string][]FileSummary)
+	pkgMutants := make(map[string][]testing.Mutant)
+	for path, g := range files {
+		fs := FileSummary{
+			Summary: summarize(rel(path), g.all),
+			Path:    path,
+		}
+		for name, ms := range g.funcs {
+			fs.Functions = append(fs.Functions, FunctionSummary{
+				Summary: summarize(name, ms),
+				Line:    funcLine(ms),
+			})
+		}
+		sort.Slice(fs.Functions, func(i, j int) bool {
+			a, b := fs.Functions[i], fs.Functions[j]
+			if a.Line != b.Line {
+				return a.Line < b.Line
+			}
+			return a.Name < b.Name
+		})
+		pkg := filepath.ToSlash(filepath.Dir(fs.Name))
+		pkgFiles[pkg] = append(pkgFiles[pkg], fs)
+		pkgMutants[pkg] = append(pkgMutants[pkg], g.all...)
+	}
+
+	bd := Breakdown{Operators: operatorStats(mutants)}
+	for pkg, fss := range pkgFiles {
+		sort.Slice(fss, func(i, j int) bool { return fss[i].Name < fss[j].Name })
+		bd.Packages = append(bd.Packages, PackageSummary{
+			Summary: summarize(pkg, pkgMutants[pkg]),
+			Files:   fss,
+		})
+	}
+	sort.Slice(bd.Packages, func(i, j int) bool { return bd.Packages[i].Name < bd.Packages[j].Name })
+	return bd
+}
+
+func summarize(name string, mutants []testing.Mutant) Summary {
+	s := StatsForFile(mutants)
+	s.Total = len(mutants)
+	return Summary{Name: name, Stats: s}
+}
+
+// funcName names the function a mutant is in the way Go code refers to it:
+// Func, T.Method or (*T).Method.
+func funcName(m testing.Mutant) string {
+	fn := m.Site.EnclosingFunc
+	if fn == nil {
+		if m.Site.FunctionName != "" {
+			return m.Site.FunctionName
+		}
+		return packageLevel
+	}
+	if fn.Recv == nil || len(fn.Recv.List) == 0 {
+		return fn.Name.Name
+	}
+	name, ptr := recvName(fn.Recv.List[0].Type)
+	switch {
+	case name == "":
+		return fn.Name.Name
+	case ptr:
+		return "(*" + name + ")." + fn.Name.Name
+	default:
+		return name + "." + fn.Name.Name
+	}
+}
+
+// recvName is the type name of a receiver, without type parameters.
+func recvName(e ast.Expr) (name string, ptr bool) {
+	switch t := e.(type) {
+	case *ast.StarExpr:
+		n, _ := recvName(t.X)
+		return n, true
+	case *ast.ParenExpr:
+		return recvName(t.X)
+	case *ast.IndexExpr:
+		return recvName(t.X)
+	case *ast.IndexListExpr:
+		return recvName(t.X)
+	case *ast.Ident:
+		return t.Name, false
+	}
+	return "", false
+}
+
+// funcLine is the declaration line of the function the mutants are in, or
+// the first mutant's line when that is unknown.
+func funcLine(mutants []testing.Mutant) int {
+	line := 0
+	for _, m := range mutants {
+		if fn := m.Site.EnclosingFunc; fn != nil && m.Site.Fset != nil {
+			if l := m.Site.Fset.Position(fn.Pos()).Line; l > 0 {
+				return l
+			}
+		}
+		if line == 0 || m.Site.Line < line {
+			line = m.Site.Line
+		}
+	}
+	return line
+}
+
+// weakest returns up to n scored summaries with the lowest scores, ties
+// broken by the number of survivors. Summaries at 100% are left out.
+func weakest(summaries []Summary, n int) []Summary {
+	var out []Summary
+	for _, s := range summaries {
+		if s.Scored() && s.Stats.Score < percentageMultiplier {
+			out = append(out, s)
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		if out[i].Stats.Score != out[j].Stats.Score {
+			return out[i].Stats.Score < out[j].Stats.Score
+		}
+		return out[i].Stats.Survived > out[j].Stats.Survived
+	})
+	if len(out) > n {
+		out = out[:n]
+	}
+	return out
+}
+
+// packageSummaries and functionSummaries flatten the breakdown for ranking;
+// functions are named file:Func.
+func (bd Breakdown) packageSummaries() []Summary {
+	out := make([]Summary, 0, len(bd.Packages))
+	for _, p := range bd.Packages {
+		out = append(out, p.Summary)
+	}
+	return out
+}
+
+func (bd Breakdown) functionSummaries() []Summary {
+	var out []Summary
+	for _, p := range bd.Packages {
+		for _, f := range p.Files {
+			for _, fn := range f.Functions {
+				s := fn.Summary
+				s.Name = f.Name + ":" + fn.Name
+				out = append(out, s)
+			}
+		}
+	}
+	return out
+}
+
+// fileSummaries indexes the files of the breakdown by Path.
+func (bd Breakdown) fileSummaries() map[string]FileSummary {
+	out := make(map[string]FileSummary)
+	for _, p := range bd.Packages {
+		for _, f := range p.Files {
+			out[f.Path] = f
+		}
+	}
+	return out
+}
@@ -0,0 +1,142 @@
+//go:build unit
+// +build unit
+
+package reporter
+
+import (
+	"bytes"
+	"encoding/xml"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	core "github.com/aclfe/gorgon/internal/core"
+	"github.com/aclfe/gorgon/internal/engine"
+	"github.com/aclfe/gorgon/pkg/mutator/operators/arithmetic_flip"
+)
+
+const aggregateSrc = `package calc
+
+var offset = 1 + 2
+
+func Add(a, b int) int { return a + b }
+
+type Acc struct{ n int }
+
+func (a *Acc) Add(n int) { a.n = a.n + n }
+`
+
+// aggregateMutants returns one mutant per function of aggregateSrc plus
+// one in another package: Add killed, (*Acc).Add survived, the package-level
+// one untested and util's killed.
+func aggregateMutants(t *testing.T, root string) []core.Mutant {
+	t.Helper()
+	fset := token.NewFileSet()
+	name := filepath.Join(root, "calc", "calc.go")
+	f, err := parser.ParseFile(fset, name, aggregateSrc, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	file := fset.File(f.Pos())
+	mutant := func(id int, status string, fn *ast.FuncDecl, line int) core.Mutant {
+		return core.Mutant{
+			ID:       id,
+			Status:   status,
+			Operator: arithmetic_flip.ArithmeticFlip{},
+			Site:     engine.Site{File: file, Fset: fset, Line: line, Column: 1, EnclosingFunc: fn},
+		}
+	}
+	add, method := f.Decls[1].(*ast.FuncDecl), f.Decls[3].(*ast.FuncDecl)
+	util := token.NewFileSet().AddFile(filepath.Join(root, "util", "util.go"), -1, 100)
+	return []core.Mutant{
+		mutant(1, core.StatusKilled, add, 5),
+		mutant(2, core.StatusSurvived, method, 9),
+		mutant(3, core.StatusUntested, nil, 3),
+		{ID: 4, Status: core.StatusKilled, Operator: arithmetic_flip.ArithmeticFlip{},
+			Site: engine.Site{File: util, Line: 2, Column: 1, FunctionName: "Helper"}},
+	}
+}
+
+func TestAggregate(t *testing.T) {
+	root := t.TempDir()
+	bd := Aggregate(aggregateMutants(t, root), root)
+
+	if len(bd.Packages) != 2 || bd.Packages[0].Name != "calc" || bd.Packages[1].Name != "util" {
+		t.Fatalf("unexpected packages: %+v", bd.Packages)
+	}
+	calc := bd.Packages[0]
+	if calc.Stats.Total != 3 || calc.Stats.Killed != 1 || calc.Stats.Survived != 1 || calc.Stats.Untested != 1 {
+		t.Errorf("calc stats = %+v", calc.Stats)
+	}
+	if len(calc.Files) != 1 || calc.Files[0].Name != "calc/calc.go" {
+		t.Fatalf("unexpected files: %+v", calc.Files)
+	}
+
+	var got []string
+	for _, fn := range calc.Files[0].Functions {
+		got = append(got, fn.Name)
+	}
+	if want := []string{packageLevel, "Add", "(*Acc).Add"}; strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("functions = %v, want %v", got, want)
+	}
+	if fn := calc.Files[0].Functions[2]; fn.Line != 9 || fn.Stats.Score != 0 {
+		t.Errorf("method summary = %+v", fn)
+	}
+	if fn := bd.Packages[1].Files[0].Functions[0]; fn.Name != "Helper" || fn.Line != 2 {
+		t.Errorf("function name not taken from the site: %+v", fn)
+	}
+
+	weak := weakest(bd.functionSummaries(), 5)
+	if len(weak) != 2 || weak[0].Name != "calc/calc.go:(*Acc).Add" || weak[1].Name != "calc/calc.go:"+packageLevel {
+		t.Errorf("weakest functions = %+v", weak)
+	}
+}
+
+func TestTextReportListsWeakest(t *testing.T) {
+	root := t.TempDir()
+	mutants := aggregateMutants(t, root)
+	out := filepath.Join(t.TempDir(), "report.txt")
+	if err := writeTextReport(mutants, computeStats(mutants, 4), Aggregate(mutants, root), false, false, false, out); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	text := string(data)
+	for _, s := range []string{"Weakest Packages:", "calc  33.33%  1 of 3 survived", "Weakest Functions:", "calc/calc.go:(*Acc).Add"} {
+		if !strings.Contains(text, s) {
+			t.Errorf("text report lacks %q:\n%s", s, text)
+		}
+	}
+	if strings.Contains(text, "util") {
+		t.Errorf("fully killed package listed as weak:\n%s", text)
+	}
+}
+
+func TestJUnitSuitePerPackage(t *testing.T) {
+	root := t.TempDir()
+	mutants := aggregateMutants(t, root)
+	out := filepath.Join(t.TempDir(), "junit.xml")
+	if err := writeJUnitReport(mutants, computeStats(mutants, 4), Aggregate(mutants, root), out); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var suites junitTestSuites
+	if err := xml.NewDecoder(bytes.NewReader(data)).Decode(&suites); err != nil {
+		t.Fatal(err)
+	}
+	if suites.Total != 4 || suites.Tests != 4 || suites.Failures != 1 || suites.Skipped != 1 || len(suites.Suites) != 2 {
+		t.Fatalf("unexpected suites: %+v", suites)
+	}
+	if s := suites.Suites[0]; s.Name != "calc" || s.Tests != 3 || s.Survived != 1 || len(s.TestCases) != 3 {
+		t.Errorf("unexpected calc suite: %+v", s)
+	}
+}
@@ -0,0 +1,54 @@
+//go:build unit
+// +build unit
+
+package reporter
+
+import (
+	"go/token"
+	"path/filepath"
+	"testing"
+
+	"github.com/aclfe/gorgon/internal/baseline"
+	core "github.com/aclfe/gorgon/internal/core"
+	"github.com/aclfe/gorgon/internal/engine"
+	"github.com/aclfe/gorgon/pkg/mutator/operators/arithmetic_flip"
+)
+
+// TestReport_BaselineMutantsMode verifies that in mutants mode a moved but
+// unchanged survivor passes while an unknown survivor fails, even when the
+// score did not drop.
+func TestReport_BaselineMutantsMode(t *testing.T) {
+	dir := t.TempDir()
+	file := token.NewFileSet().AddFile(filepath.Join(dir, "calc.go"), -1, 100)
+	survivor := func(fp string, line int) core.Mutant {
+		return core.Mutant{
+			Fingerprint: fp,
+			Status:      core.StatusSurvived,
+			Operator:    arithmetic_flip.ArithmeticFlip{},
+			Site:        engine.Site{File: file, Line: line, Column: 1},
+		}
+	}
+	opts := BaselineOptions{NoRegression: true, Dir: dir, Mode: baseline.ModeMutants}
+	report := func(mutants ...core.Mutant) error {
+		_, err := Report(mutants, len(mutants), 0, nil, false, false, false, "", "", "", opts)
+		return err
+	}
+
+	if err := report(survivor("aaaa", 3)); err != nil {
+		t.Fatalf("first run should save the baseline: %v", err)
+	}
+	saved, err := baseline.Load(dir, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if saved.Mode != baseline.ModeMutants || len(saved.Survivors) != 1 || saved.Survivors[0].File != "calc.go" {
+		t.Fatalf("unexpected saved baseline: %+v", saved)
+	}
+
+	if err := report(survivor("aaaa", 10)); err != nil {
+		t.Errorf("a known survivor on another line should pass: %v", err)
+	}
+	if err := report(survivor("bbbb", 3)); err == nil {
+		t.Error("expected a new survivor to fail the baseline check")
+	}
+}
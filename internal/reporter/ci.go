@@ -0,0 +1,148 @@
+package reporter
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	testing "github.com/aclfe/gorgon/internal/core"
+)
+
+// writeGitHubAnnotations writes one GitHub Actions workflow command per
+// survivor, which the runner turns into an annotation on the pull-request
+// diff. Commands only take effect on a step's output, so outputFile "-"
+// writes them to stdout.
+func writeGitHubAnnotations(mutants []testing.Mutant, root, outputFile string) error {
+	var out io.Writer = os.Stdout
+	if outputFile != "-" {
+		f, err := os.Create(outputFile)
+		if err != nil {
+			return fmt.Errorf("failed to create output file: %w", err)
+		}
+		defer f.Close()
+		out = f
+	}
+	for _, f := range collectFindings(mutants, root) {
+		msg := f.Message
+		if f.Mutant.Diff != "" {
+			msg += "\n\n" + f.Mutant.Diff
+		}
+		fmt.Fprintf(out, "::warning file=%s,line=%d,col=%d,endLine=%d,endColumn=%d,title=%s::%s\n",
+			escapeGitHubProperty(f.Path), f.Span.StartLine, f.Span.StartColumn, f.Span.EndLine, f.Span.EndColumn,
+			escapeGitHubProperty(fmt.Sprintf("Survived mutant (%s)", f.Mutant.Operator.Name())),
+			escapeGitHubData(msg))
+	}
+	return nil
+}
+
+// escapeGitHubData escapes a workflow command message.
+func escapeGitHubData(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A").Replace(s)
+}
+
+// escapeGitHubProperty escapes a workflow command property value.
+func escapeGitHubProperty(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C").Replace(s)
+}
+
+// gitlabIssue is an entry of a GitLab Code Quality report, a subset of the
+// Code Climate issue format.
+type gitlabIssue struct {
+	Description string         `json:"description"`
+	CheckName   string         `json:"check_name"`
+	Fingerprint string         `json:"fingerprint"`
+	Severity    string         `json:"severity"`
+	Location    gitlabLocation `json:"location"`
+}
+
+type gitlabLocation struct {
+	Path  string      `json:"path"`
+	Lines gitlabLines `json:"lines"`
+}
+
+type gitlabLines struct {
+	Begin int `json:"begin"`
+	End   int `json:"end,omitempty"`
+}
+
+// writeGitLabCodeQuality writes survivors as a GitLab Code Quality report.
+// GitLab matches issues across pipelines by fingerprint, so merge requests
+// show only survivors that are new or fixed.
+func writeGitLabCodeQuality(mutants []testing.Mutant, root, outputFile string) error {
+	issues := make([]gitlabIssue, 0)
+	for _, f := range collectFindings(mutants, root) {
+		issues = append(issues, gitlabIssue{
+			Description: f.Message,
+			CheckName:   "gorgon/" + f.Mutant.Operator.Name(),
+			Fingerprint: f.Mutant.Fingerprint,
+			Severity:    f.Severity,
+			Location: gitlabLocation{
+				Path:  f.Path,
+				Lines: gitlabLines{Begin: f.Span.StartLine, End: f.Span.EndLine},
+			},
+		})
+	}
+	data, err := json.MarshalIndent(issues, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(outputFile, data, 0644)
+}
+
+// sonarReport is SonarQube's generic external issue format.
+type sonarReport struct {
+	Issues []sonarIssue `json:"issues"`
+}
+
+type sonarIssue struct {
+	EngineID        string        `json:"engineId"`
+	RuleID          string        `json:"ruleId"`
+	Severity        string        `json:"severity"`
+	Type            string        `json:"type"`
+	PrimaryLocation sonarLocation `json:"primaryLocation"`
+}
+
+type sonarLocation struct {
+	Message   string         `json:"message"`
+	FilePath  string         `json:"filePath"`
+	TextRange sonarTextRange `json:"textRange"`
+}
+
+// sonarTextRange lines are 1-based and columns 0-based.
+type sonarTextRange struct {
+	StartLine   int `json:"startLine"`
+	EndLine     int `json:"endLine"`
+	StartColumn int `json:"startColumn"`
+	EndColumn   int `json:"endColumn"`
+}
+
+// writeSonarQubeIssues writes survivors as SonarQube generic external
+// issues, for import with sonar.externalIssuesReportPaths.
+func writeSonarQubeIssues(mutants []testing.Mutant, root, outputFile string) error {
+	report := sonarReport{Issues: make([]sonarIssue, 0)}
+	for _, f := range collectFindings(mutants, root) {
+		report.Issues = append(report.Issues, sonarIssue{
+			EngineID: "gorgon",
+			RuleID:   f.Mutant.Operator.Name(),
+			Severity: strings.ToUpper(f.Severity),
+			Type:     "CODE_SMELL",
+			PrimaryLocation: sonarLocation{
+				Message:  f.Message,
+				FilePath: f.Path,
+				TextRange: sonarTextRange{
+					StartLine:   f.Span.StartLine,
+					EndLine:     f.Span.EndLine,
+					StartColumn: f.Span.StartColumn - 1,
+					EndColumn:   f.Span.EndColumn - 1,
+				},
+			},
+		})
+	}
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(outputFile, data, 0644)
+}
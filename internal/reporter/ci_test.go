@@ -0,0 +1,109 @@
+//go:build unit
+// +build unit
+
+package reporter
+
+import (
+	"encoding/json"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	core "github.com/aclfe/gorgon/internal/core"
+	"github.com/aclfe/gorgon/internal/engine"
+	"github.com/aclfe/gorgon/pkg/mutator/operators/arithmetic_flip"
+)
+
+func ciMutants(root string) []core.Mutant {
+	file := token.NewFileSet().AddFile(filepath.Join(root, "calc", "calc.go"), -1, 100)
+	survivor := core.Mutant{
+		ID:          1,
+		Fingerprint: "5124b415cdde32e6",
+		Status:      core.StatusSurvived,
+		Operator:    arithmetic_flip.ArithmeticFlip{},
+		Original:    "a + b",
+		Mutated:     "a - b",
+		Diff:        "--- calc.go\n+++ calc.go\n@@ -3 +3 @@\n-a + b\n+a - b\n",
+		Site:        engine.Site{File: file, Line: 3, Column: 33},
+	}
+	killed := survivor
+	killed.ID, killed.Status = 2, core.StatusKilled
+	return []core.Mutant{survivor, killed}
+}
+
+func TestGitHubAnnotations(t *testing.T) {
+	root := t.TempDir()
+	out := filepath.Join(t.TempDir(), "annotations.txt")
+	if err := writeGitHubAnnotations(ciMutants(root), root, out); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 1 {
+		t.Fatalf("expected one annotation for the survivor, got %d", len(lines))
+	}
+	want := "::warning file=calc/calc.go,line=3,col=33,endLine=3,endColumn=34,title=Survived mutant (arithmetic_flip)::" +
+		"Mutant survived: arithmetic_flip changed `a + b` to `a - b`. No test failed when the code was changed.%0A%0A--- calc.go%0A"
+	if !strings.HasPrefix(lines[0], want) {
+		t.Fatalf("unexpected annotation:\n%s\nwant prefix:\n%s", lines[0], want)
+	}
+}
+
+func TestGitLabAndSonarQubeReports(t *testing.T) {
+	dir := t.TempDir()
+	mutants := ciMutants(dir)
+
+	gl := filepath.Join(dir, "gl.json")
+	if err := writeGitLabCodeQuality(mutants, dir, gl); err != nil {
+		t.Fatal(err)
+	}
+	var issues []gitlabIssue
+	readJSON(t, gl, &issues)
+	if len(issues) != 1 || issues[0].Fingerprint != "5124b415cdde32e6" || issues[0].Severity != severityMinor ||
+		issues[0].Location.Path != "calc/calc.go" || issues[0].Location.Lines.Begin != 3 {
+		t.Fatalf("unexpected GitLab issues: %+v", issues)
+	}
+
+	sq := filepath.Join(dir, "sonar.json")
+	if err := writeSonarQubeIssues(mutants, dir, sq); err != nil {
+		t.Fatal(err)
+	}
+	var report sonarReport
+	readJSON(t, sq, &report)
+	if len(report.Issues) != 1 {
+		t.Fatalf("expected one SonarQube issue, got %d", len(report.Issues))
+	}
+	issue := report.Issues[0]
+	if issue.Severity != "MINOR" || issue.RuleID != "arithmetic_flip" || issue.PrimaryLocation.TextRange.StartColumn != 32 {
+		t.Fatalf("unexpected SonarQube issue: %+v", issue)
+	}
+}
+
+func TestOperatorSeverity(t *testing.T) {
+	for op, want := range map[string]string{
+		"error_check_removal": severityCritical,
+		"negate_condition":    severityMajor,
+		"arithmetic_flip":     severityMinor,
+		"not_an_operator":     severityMajor,
+	} {
+		if got := operatorSeverity(op); got != want {
+			t.Errorf("operatorSeverity(%s) = %s, want %s", op, got, want)
+		}
+	}
+}
+
+func readJSON(t *testing.T, path string, v any) {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		t.Fatal(err)
+	}
+}
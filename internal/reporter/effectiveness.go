@@ -0,0 +1,105 @@
+package reporter
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	testing "github.com/aclfe/gorgon/internal/core"
+)
+
+// OperatorStats is how useful an operator was in a run: how many of its
+// mutants were noise (rejected by preflight or failing to compile) and how
+// the rest fared against the tests.
+type OperatorStats struct {
+	Operator         string  `json:"operator"`
+	Generated        int     `json:"generated"`
+	PreflightInvalid int     `json:"preflight_invalid"`
+	CompileErrors    int     `json:"compile_errors"` // after preflight, when building the test binary
+	RuntimeErrors    int     `json:"runtime_errors"`
+	Killed           int     `json:"killed"`
+	Survived         int     `json:"survived"`
+	Timeouts         int     `json:"timeouts"`
+	Untested         int     `json:"untested"`
+	MeanKillMS       float64 `json:"mean_kill_ms"`  // mean time to kill over kills with a recorded duration
+	SurvivalRate     float64 `json:"survival_rate"` // survived / (killed + survived + timeouts), in percent
+	NoiseRate        float64 `json:"noise_rate"`    // (preflight_invalid + compile_errors) / generated, in percent
+}
+
+// operatorStats computes OperatorStats for every operator that generated a
+// mutant, by name.
+func operatorStats(mutants []testing.Mutant) []OperatorStats {
+	byOp := make(map[string]*OperatorStats)
+	killTime := make(map[string]time.Duration)
+	timedKills := make(map[string]int)
+	for _, m := range mutants {
+		name := m.Operator.Name()
+		s := byOp[name]
+		if s == nil {
+			s = &OperatorStats{Operator: name}
+			byOp[name] = s
+		}
+		s.Generated++
+		switch {
+		case m.Preflight:
+			s.PreflightInvalid++
+		case m.Status == testing.StatusKilled:
+			s.Killed++
+			if m.KillDuration > 0 {
+				killTime[name] += m.KillDuration
+				timedKills[name]++
+			}
+		case m.Status == testing.StatusSurvived:
+			s.Survived++
+		case m.Status == testing.StatusTimeout:
+			s.Timeouts++
+		case m.Status == testing.StatusUntested:
+			s.Untested++
+		case m.Status == testing.StatusError && m.KilledBy == "(compiler)":
+			s.CompileErrors++
+		case m.Status == testing.StatusError:
+			s.RuntimeErrors++
+		case m.Status == testing.StatusInvalid:
+			s.PreflightInvalid++
+		}
+	}
+
+	out := make([]OperatorStats, 0, len(byOp))
+	for name, s := range byOp {
+		if n := timedKills[name]; n > 0 {
+			s.MeanKillMS = float64(killTime[name]) / float64(n) / float64(time.Millisecond)
+		}
+		if tested := s.Killed + s.Survived + s.Timeouts; tested > 0 {
+			s.SurvivalRate = float64(s.Survived) / float64(tested) * percentageMultiplier
+		}
+		s.NoiseRate = float64(s.PreflightInvalid+s.CompileErrors) / float64(s.Generated) * percentageMultiplier
+		out = append(out, *s)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Operator < out[j].Operator })
+	return out
+}
+
+// writeOperatorEffectiveness prints the operator table of the text report.
+func writeOperatorEffectiveness(out io.Writer, ops []OperatorStats) {
+	if len(ops) == 0 {
+		return
+	}
+	fmt.Fprintln(out, "\nOperator Effectiveness:")
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "  Operator\tGenerated\tInvalid\tCompile Errors\tKilled\tSurvived\tTimeout\tMean Kill\tSurvival\tNoise")
+	for _, s := range ops {
+		meanKill := "-"
+		if s.MeanKillMS > 0 {
+			meanKill = (time.Duration(s.MeanKillMS * float64(time.Millisecond))).Round(time.Millisecond).String()
+		}
+		survival := "-"
+		if s.Killed+s.Survived+s.Timeouts > 0 {
+			survival = fmt.Sprintf("%.1f%%", s.SurvivalRate)
+		}
+		fmt.Fprintf(w, "  %s\t%d\t%d\t%d\t%d\t%d\t%d\t%s\t%s\t%.1f%%\n",
+			s.Operator, s.Generated, s.PreflightInvalid, s.CompileErrors, s.Killed, s.Survived, s.Timeouts, meanKill, survival, s.NoiseRate)
+	}
+	w.Flush()
+}
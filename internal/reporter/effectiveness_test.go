@@ -0,0 +1,59 @@
+//go:build unit
+// +build unit
+
+package reporter
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	core "github.com/aclfe/gorgon/internal/core"
+)
+
+func TestOperatorStats(t *testing.T) {
+	base := ciMutants(t.TempDir())[0]
+	with := func(status, killedBy string, d time.Duration, preflight bool) core.Mutant {
+		m := base
+		m.Status, m.KilledBy, m.KillDuration, m.Preflight = status, killedBy, d, preflight
+		return m
+	}
+	mutants := []core.Mutant{
+		with(core.StatusKilled, "TestAdd", 10*time.Millisecond, false),
+		with(core.StatusKilled, "TestAdd", 30*time.Millisecond, false),
+		with(core.StatusKilled, "TestAdd", 0, false), // cached before durations were kept
+		with(core.StatusSurvived, "", 0, false),
+		with(core.StatusInvalid, "", 0, true),
+		with(core.StatusError, "(compiler)", 0, true),
+		with(core.StatusError, "(compiler)", 0, false),
+		with(core.StatusTimeout, "", 0, false),
+	}
+
+	ops := operatorStats(mutants)
+	if len(ops) != 1 {
+		t.Fatalf("expected one operator, got %+v", ops)
+	}
+	s := ops[0]
+	want := OperatorStats{
+		Operator:         "arithmetic_flip",
+		Generated:        8,
+		PreflightInvalid: 2,
+		CompileErrors:    1,
+		Killed:           3,
+		Survived:         1,
+		Timeouts:         1,
+		MeanKillMS:       20,
+		SurvivalRate:     20,
+		NoiseRate:        37.5,
+	}
+	if s != want {
+		t.Errorf("stats = %+v\nwant    %+v", s, want)
+	}
+
+	var out bytes.Buffer
+	writeOperatorEffectiveness(&out, ops)
+	if !strings.Contains(out.String(), "arithmetic_flip  8          2        1               3       1         1        20ms       20.0%     37.5%") {
+		t.Errorf("unexpected table:\n%s", out.String())
+	}
+}
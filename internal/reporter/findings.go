@@ -0,0 +1,146 @@
+package reporter
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	testing "github.com/aclfe/gorgon/internal/core"
+	"github.com/aclfe/gorgon/pkg/mutator"
+)
+
+// finding is a reportable mutant, usually a survivor, in the shape that code-scanning and CI
+// annotation formats share, so every such format reports the same location,
+// message and severity for it.
+type finding struct {
+	Mutant   testing.Mutant
+	Path     string // relative to the working directory, slash-separated
+	Span     span
+	Message  string
+	Severity string // see operatorSeverity
+}
+
+// span is a 1-based source range; the end column is exclusive.
+type span struct {
+	StartLine, StartColumn int
+	EndLine, EndColumn     int
+}
+
+// mutantSpan covers the mutated node, falling back to the site position
+// when the node's extent is unknown.
+func mutantSpan(m testing.Mutant) span {
+	sp := span{m.Site.Line, m.Site.Column, m.Site.Line, m.Site.Column + 1}
+	if m.Site.Fset == nil || m.Site.Node == nil || !m.Site.Node.Pos().IsValid() {
+		return sp
+	}
+	start, end := m.Site.Fset.Position(m.Site.Node.Pos()), m.Site.Fset.Position(m.Site.Node.End())
+	sp.StartLine, sp.StartColumn = start.Line, start.Column
+	if end.IsValid() {
+		sp.EndLine, sp.EndColumn = end.Line, end.Column
+	} else {
+		sp.EndLine, sp.EndColumn = start.Line, start.Column+1
+	}
+	return sp
+}
+
+// Severities, in the lowercase form GitLab uses.
+const (
+	severityCritical = "critical"
+	severityMajor    = "major"
+	severityMinor    = "minor"
+)
+
+// operatorSeverity ranks a survivor by what its operator category says about
+// the untested behaviour: unchecked error, panic and concurrency paths are
+// critical, control flow and returned values major, and value tweaks minor.
+func operatorSeverity(op string) string {
+	switch mutator.CategoryOf(op) {
+	case "error_handling", "panic_recovery", "concurrency":
+		return severityCritical
+	case "arithmetic", "binary", "assignment", "literal":
+		return severityMinor
+	}
+	return severityMajor
+}
+
+// maxInlineSnippet is the longest snippet quoted inline in a message.
+const maxInlineSnippet = 60
+
+// findingMessage describes a mutant in one line, quoting the change when
+// it is short.
+func findingMessage(m testing.Mutant) string {
+	verb, why := "survived", "No test failed when the code was changed."
+	switch m.Status {
+	case testing.StatusTimeout:
+		verb, why = "timed out", "The tests did not finish in time when the code was changed."
+	case testing.StatusUntested:
+		verb, why = "not tested", "No test covers this code."
+	}
+	msg := fmt.Sprintf("Mutant %s: %s", verb, m.Operator.Name())
+	if isInlineSnippet(m.Original) && isInlineSnippet(m.Mutated) {
+		msg += fmt.Sprintf(" changed `%s` to `%s`", m.Original, m.Mutated)
+	}
+	return msg + ". " + why
+}
+
+func isInlineSnippet(s string) bool {
+	return s != "" && len(s) <= maxInlineSnippet && !strings.Contains(s, "\n")
+}
+
+// collectFindings returns the survivors among mutants as findings, in
+// mutant order, with paths relative to root.
+func collectFindings(mutants []testing.Mutant, root string) []finding {
+	return findingsWithStatus(mutants, rootRelativizer(root), testing.StatusSurvived)
+}
+
+// findingsWithStatus returns the mutants with one of statuses as findings,
+// in mutant order, with paths made relative by rel.
+func findingsWithStatus(mutants []testing.Mutant, rel func(string) string, statuses ...string) []finding {
+	want := make(map[string]bool, len(statuses))
+	for _, s := range statuses {
+		want[s] = true
+	}
+	var findings []finding
+	for _, m := range mutants {
+		if !want[m.Status] || m.Site.File == nil {
+			continue
+		}
+		findings = append(findings, finding{
+			Mutant:   m,
+			Path:     rel(m.Site.File.Name()),
+			Span:     mutantSpan(m),
+			Message:  findingMessage(m),
+			Severity: operatorSeverity(m.Operator.Name()),
+		})
+	}
+	return findings
+}
+
+// relativizer returns a function that makes paths relative to the working
+// directory and slash-separated, leaving paths outside it untouched.
+func relativizer() func(string) string {
+	cwd, _ := os.Getwd()
+	return relativeTo(cwd)
+}
+
+// rootRelativizer makes paths relative to the project root, or to the
+// working directory when root is "".
+func rootRelativizer(root string) func(string) string {
+	if root == "" {
+		return relativizer()
+	}
+	return relativeTo(root)
+}
+
+// relativeTo is relativizer for an arbitrary root directory.
+func relativeTo(root string) func(string) string {
+	return func(path string) string {
+		if root != "" {
+			if r, err := filepath.Rel(root, path); err == nil && !strings.HasPrefix(r, "..") {
+				path = r
+			}
+		}
+		return filepath.ToSlash(path)
+	}
+}
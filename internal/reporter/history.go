@@ -0,0 +1,134 @@
+package reporter
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// DefaultHistoryFile is where runs are recorded when history.file is unset,
+// relative to the baseline directory.
+const DefaultHistoryFile = ".gorgon-history.jsonl"
+
+// HistoryOptions controls the run history. The HTML report draws its trends
+// from the file whether or not this run is recorded.
+type HistoryOptions struct {
+	Record   bool          // append this run to the history
+	File     string        // "" for DefaultHistoryFile
+	WallTime time.Duration // duration of the whole run
+}
+
+// HistoryRecord is one run in the history file, one JSON object per line.
+type HistoryRecord struct {
+	Time       time.Time          `json:"time"`
+	Commit     string             `json:"commit,omitempty"`
+	Score      float64            `json:"score"`
+	Stats      ReportStats        `json:"stats"`
+	Packages   map[string]float64 `json:"packages,omitempty"`  // score by directory relative to the project root
+	Operators  map[string]float64 `json:"operators,omitempty"` // score by operator
+	WallTimeMS int64              `json:"wall_time_ms,omitempty"`
+	// OperatorStats is the effectiveness of each operator, so it can be
+	// followed from run to run.
+	OperatorStats []OperatorStats `json:"operator_stats,omitempty"`
+}
+
+// historyPath resolves file against dir like the baseline file.
+func historyPath(dir, file string) string {
+	if file == "" {
+		file = DefaultHistoryFile
+	}
+	if filepath.IsAbs(file) {
+		return file
+	}
+	return filepath.Join(dir, file)
+}
+
+// newHistoryRecord summarises a run. Packages and operators without a
+// scored mutant are left out rather than recorded as 0%.
+func newHistoryRecord(stats ReportStats, bd Breakdown, root string, wall time.Duration) HistoryRecord {
+	rec := HistoryRecord{
+		Time:          time.Now().UTC(),
+		Commit:        gitCommit(root),
+		Score:         stats.Score,
+		Stats:         stats,
+		Packages:      make(map[string]float64, len(bd.Packages)),
+		Operators:     make(map[string]float64, len(bd.Operators)),
+		WallTimeMS:    wall.Milliseconds(),
+		OperatorStats: bd.Operators,
+	}
+	for _, p := range bd.Packages {
+		if p.Scored() {
+			rec.Packages[p.Name] = p.Stats.Score
+		}
+	}
+	for _, o := range bd.Operators {
+		if o.Killed+o.Survived+o.Untested+o.Timeouts > 0 {
+			rec.Operators[o.Operator] = CalculateScore(o.Killed, o.Survived, o.Untested, o.Timeouts)
+		}
+	}
+	return rec
+}
+
+// gitCommit returns the commit checked out in dir, or "" outside a
+// repository.
+func gitCommit(dir string) string {
+	cmd := exec.Command("git", "rev-parse", "HEAD")
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// appendHistory adds rec to the history file at path.
+func appendHistory(path string, rec HistoryRecord) error {
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// LoadHistory reads the runs recorded at path, oldest first. A missing file
+// is an empty history; lines that do not parse, such as one cut short by an
+// interrupted run, are skipped.
+func LoadHistory(path string) ([]HistoryRecord, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to open history: %w", err)
+	}
+	defer f.Close()
+
+	var records []HistoryRecord
+	sc := bufio.NewScanner(f)
+	sc.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for sc.Scan() {
+		var rec HistoryRecord
+		if err := json.Unmarshal(sc.Bytes(), &rec); err != nil {
+			continue
+		}
+		records = append(records, rec)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read history %s: %w", path, err)
+	}
+	return records, nil
+}
@@ -0,0 +1,82 @@
+//go:build unit
+// +build unit
+
+package reporter
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHistoryRoundTrip(t *testing.T) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	mutants := ciMutants(wd)
+	stats := computeStats(mutants, 2)
+	rec := newHistoryRecord(stats, Aggregate(mutants, wd), wd, 1500*time.Millisecond)
+	if rec.Packages["calc"] != 50 || rec.Operators["arithmetic_flip"] != 50 || rec.WallTimeMS != 1500 ||
+		len(rec.OperatorStats) != 1 || rec.OperatorStats[0].Survived != 1 {
+		t.Fatalf("unexpected record: %+v", rec)
+	}
+
+	path := historyPath(t.TempDir(), "")
+	if filepath.Base(path) != DefaultHistoryFile {
+		t.Errorf("history path = %s", path)
+	}
+	if err := appendHistory(path, rec); err != nil {
+		t.Fatal(err)
+	}
+	// A line cut short by an interrupted run must not hide the others.
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.WriteString(`{"time": "2026-`)
+	f.WriteString("\n")
+	f.Close()
+	rec.Score = 75
+	if err := appendHistory(path, rec); err != nil {
+		t.Fatal(err)
+	}
+
+	records, err := LoadHistory(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 2 || records[0].Score != 50 || records[1].Score != 75 || records[0].Stats != stats {
+		t.Fatalf("unexpected history: %+v", records)
+	}
+}
+
+func TestLoadHistoryMissingFile(t *testing.T) {
+	records, err := LoadHistory(filepath.Join(t.TempDir(), "none.jsonl"))
+	if err != nil || records != nil {
+		t.Fatalf("expected an empty history, got %v, %v", records, err)
+	}
+}
+
+func TestHTMLReportEmbedsHistory(t *testing.T) {
+	history := []HistoryRecord{{Time: time.Unix(0, 0).UTC(), Commit: "abc123", Score: 42, Packages: map[string]float64{"calc": 42}}}
+	out := filepath.Join(t.TempDir(), "html")
+	if err := writeHTMLReport(ciMutants(t.TempDir()), ReportStats{}, Breakdown{}, 80, nil, history, out); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(filepath.Join(out, "index.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	page := string(data)
+	for _, s := range []string{`"commit":"abc123"`, `"packages":{"calc":42}`, `id="tab-trends"`} {
+		if !strings.Contains(page, s) {
+			t.Errorf("report lacks %s", s)
+		}
+	}
+	if strings.Contains(page, "<script src=") {
+		t.Error("report must not load external scripts")
+	}
+}
@@ -1,8 +1,11 @@
 package reporter
 
 import (
+	"bytes"
+	_ "embed"
 	"fmt"
 	"html/template"
+	"io"
 	"os"
 	"path/filepath"
 	"sort"
@@ -12,226 +15,10 @@ import (
 	"github.com/aclfe/gorgon/internal/subconfig"
 )
 
-const reportTemplate = `<!DOCTYPE html>
-<html>
-<head>
-<meta charset="UTF-8">
-<title>Gorgon Mutation Report</title>
-<style>
-* { margin: 0; padding: 0; box-sizing: border-box; }
-body { font-family: monospace; font-size: 12px; }
-.container { display: flex; height: 100vh; }
-.sidebar { width: 300px; border-right: 1px solid #ccc; overflow-y: auto; background: #f5f5f5; }
-.main { flex: 1; display: flex; flex-direction: column; }
-.header { padding: 10px; border-bottom: 1px solid #ccc; background: #fff; }
-.stats { display: flex; gap: 20px; }
-.stat { display: flex; align-items: baseline; gap: 5px; }
-.stat-label { color: #666; }
-.stat-value { font-weight: bold; }
-.score { font-size: 18px; }
-.score.good { color: #2e7d32; }
-.score.amber { color: #f57c00; }
-.score.bad { color: #c62828; }
-.content { flex: 1; overflow-y: auto; background: #fff; }
-.tree { padding: 10px; }
-.tree-node { cursor: pointer; padding: 2px 0; user-select: none; }
-.tree-node:hover { background: #e0e0e0; }
-.tree-node.selected { background: #d0d0d0; font-weight: bold; }
-.tree-dir { padding-left: 15px; }
-.tree-file { padding-left: 15px; }
-.tree-toggle { display: inline-block; width: 12px; }
-.tree-icon { margin-right: 3px; }
-.file-score { float: right; margin-right: 5px; font-size: 10px; }
-.file-score.good { color: #2e7d32; }
-.file-score.amber { color: #f57c00; }
-.file-score.bad { color: #c62828; }
-.code-view { padding: 10px; }
-.code-line { display: flex; border-bottom: 1px solid #f0f0f0; position: relative; }
-.mutation-count { width: 30px; text-align: center; color: #666; font-size: 10px; padding: 2px; cursor: pointer; user-select: none; }
-.mutation-count:hover { background: #e0e0e0; }
-.line-num { width: 50px; text-align: right; padding-right: 10px; color: #999; user-select: none; border-right: 1px solid #ddd; }
-.line-content { flex: 1; padding-left: 10px; white-space: pre; }
-.line-killed { background: #c8e6c9; }
-.line-survived { background: #ffcdd2; }
-.line-timeout { background: #fff9c4; }
-.line-error { background: #fff9c4; }
-.line-untested { background: #fff9c4; }
-.line-none { background: #fff; }
-.mutant-popup { display: none; position: absolute; left: 30px; top: 100%; background: #fff; border: 1px solid #999; box-shadow: 2px 2px 8px rgba(0,0,0,0.2); padding: 8px; font-size: 11px; z-index: 1000; min-width: 300px; }
-.mutant-popup.show { display: block; }
-.mutant-item { padding: 3px 0; border-bottom: 1px solid #eee; }
-.mutant-item:last-child { border-bottom: none; }
-.mutant-status { display: inline-block; padding: 1px 4px; border-radius: 2px; font-size: 10px; font-weight: bold; margin-right: 5px; }
-.mutant-status.killed { background: #c8e6c9; color: #2e7d32; }
-.mutant-status.survived { background: #ffcdd2; color: #c62828; }
-.mutant-status.timeout { background: #fff9c4; color: #f57c00; }
-.mutant-status.error { background: #fff9c4; color: #f57c00; }
-.mutant-status.untested { background: #e0e0e0; color: #666; }
-</style>
-</head>
-<body>
-<div class="container">
-<div class="sidebar">
-<div class="tree">
-{{template "tree" .Tree}}
-</div>
-</div>
-<div class="main">
-<div class="header">
-<div class="stats">
-<div class="stat">
-<span class="stat-label">Score:</span>
-<span class="stat-value score {{.ScoreClass}}">{{printf "%.2f" .Stats.Score}}%</span>
-</div>
-<div class="stat">
-<span class="stat-label">Killed:</span>
-<span class="stat-value">{{.Stats.Killed}}</span>
-</div>
-<div class="stat">
-<span class="stat-label">Survived:</span>
-<span class="stat-value">{{.Stats.Survived}}</span>
-</div>
-<div class="stat">
-<span class="stat-label">Compile Errors:</span>
-<span class="stat-value">{{.Stats.CompileErrors}}</span>
-</div>
-<div class="stat">
-<span class="stat-label">Runtime Errors:</span>
-<span class="stat-value">{{.Stats.RuntimeErrors}}</span>
-</div>
-<div class="stat">
-<span class="stat-label">Timeout:</span>
-<span class="stat-value">{{.Stats.Timeout}}</span>
-</div>
-<div class="stat">
-<span class="stat-label">Untested:</span>
-<span class="stat-value">{{.Stats.Untested}}</span>
-</div>
-<div class="stat">
-<span class="stat-label">Invalid:</span>
-<span class="stat-value">{{.Stats.Invalid}}</span>
-</div>
-<div class="stat">
-<span class="stat-label">Total:</span>
-<span class="stat-value">{{.Stats.Total}}</span>
-</div>
-</div>
-</div>
-<div class="content">
-<div id="file-view"></div>
-</div>
-</div>
-</div>
-
-{{define "tree"}}
-{{range .Children}}
-{{if .IsDir}}
-<div class="tree-node" onclick="toggleDir(event, this)">
-<span class="tree-toggle">▶</span>
-<span class="tree-icon">📁</span>
-<span>{{.Name}}</span>
-</div>
-<div class="tree-dir" style="display:none;">
-{{template "tree" .}}
-</div>
-{{else}}
-<div class="tree-node tree-file" onclick="showFile('{{.Path}}')">
-<span class="tree-toggle"></span>
-<span class="tree-icon">📄</span>
-<span>{{.Name}}</span>
-<span class="file-score {{.ScoreClass}}">{{printf "%.0f" .Score}}%</span>
-</div>
-{{end}}
-{{end}}
-{{end}}
-
-<script>
-const filesData = {{.Files}};
-
-function toggleDir(e, el) {
-e.stopPropagation();
-const dir = el.nextElementSibling;
-const toggle = el.querySelector('.tree-toggle');
-if (dir.style.display === 'none') {
-dir.style.display = 'block';
-toggle.textContent = '▼';
-} else {
-dir.style.display = 'none';
-toggle.textContent = '▶';
-}
-}
-
-function showFile(path) {
-document.querySelectorAll('.tree-file').forEach(el => el.classList.remove('selected'));
-event.target.closest('.tree-file').classList.add('selected');
-
-const fileData = filesData[path];
-if (!fileData) return;
-
-let html = '<div class="code-view">';
-fileData.Lines.forEach(line => {
-const escaped = escapeHtml(line.Content);
-const mutantCount = line.Mutants ? line.Mutants.length : 0;
-const lineStatus = line.Status;
-
-html += ` + "`" + `<div class="code-line">` + "`" + `;
-html += ` + "`" + `<div class="mutation-count" onclick="toggleMutants(event, this)">` + "`" + `;
-if (mutantCount > 0) {
-html += mutantCount;
-}
-html += ` + "`" + `</div>` + "`" + `;
-html += ` + "`" + `<div class="line-num">${line.Number}</div>` + "`" + `;
-html += ` + "`" + `<div class="line-content line-${lineStatus}">${escaped}</div>` + "`" + `;
-
-if (mutantCount > 0) {
-html += ` + "`" + `<div class="mutant-popup">` + "`" + `;
-line.Mutants.forEach(m => {
-html += ` + "`" + `<div class="mutant-item">` + "`" + `;
-html += ` + "`" + `<span class="mutant-status ${m.Status}">${m.Status}</span>` + "`" + `;
-html += ` + "`" + `#${m.ID} ${m.Operator}` + "`" + `;
-if (m.KilledBy) html += ` + "`" + ` → ${m.KilledBy}` + "`" + `;
-html += ` + "`" + `</div>` + "`" + `;
-});
-html += ` + "`" + `</div>` + "`" + `;
-}
-
-html += ` + "`" + `</div>` + "`" + `;
-});
-html += '</div>';
-document.getElementById('file-view').innerHTML = html;
-}
-
-function toggleMutants(e, el) {
-e.stopPropagation();
-const popup = el.parentElement.querySelector('.mutant-popup');
-if (!popup) return;
-
-document.querySelectorAll('.mutant-popup').forEach(p => {
-if (p !== popup) p.classList.remove('show');
-});
-
-popup.classList.toggle('show');
-}
-
-document.addEventListener('click', (e) => {
-if (!e.target.closest('.mutation-count') && !e.target.closest('.mutant-popup')) {
-document.querySelectorAll('.mutant-popup').forEach(p => p.classList.remove('show'));
-}
-});
-
-function escapeHtml(text) {
-const div = document.createElement('div');
-div.textContent = text;
-return div.innerHTML;
-}
-
-window.onload = () => {
-const firstFile = document.querySelector('.tree-file');
-if (firstFile) firstFile.click();
-};
-</script>
-</body>
-</html>` + "`"
+// reportTemplate is the page written to index.html.
+//
+//go:embed report.html
+var reportTemplate string
 
 type LineCoverage struct {
 	Number  int
@@ -241,18 +28,39 @@ type LineCoverage struct {
 }
 
 type MutantInfo struct {
-	ID       int
-	Operator string
-	Status   string
-	KilledBy string
+	ID          int
+	Column      int
+	Fingerprint string
+	Operator    string
+	Status      string
+	KilledBy    string
+	Original    string
+	Mutated     string
+	Diff        string
+	KillOutput  string // trimmed to maxHTMLKillOutput
+	DurationMS  int64
+	ErrorReason string
 }
 
 type FileData struct {
 	Path       string
 	RelPath    string
 	Lines      []LineCoverage
+	Stats      ReportStats
+	Score      float64
+	ScoreClass string
+	Functions  []FunctionData
+	Package    string   // as named in the package breakdown
+	Tests      []string // tests of the package, each run against every mutant of the file
+}
+
+// FunctionData is a function listed under its file in the tree.
+type FunctionData struct {
+	Name       string
+	Line       int
 	Score      float64
 	ScoreClass string
+	Scored     bool
 }
 
 type TreeNode struct {
@@ -260,28 +68,94 @@ type TreeNode struct {
 	Path       string
 	IsDir      bool
 	Children   []*TreeNode
+	Stats      ReportStats
+	Scored     bool // whether any mutant below counts towards Score
 	Score      float64
 	ScoreClass string
+	Functions  []FunctionData
 }
 
 type ReportData struct {
 	Stats      ReportStats
 	ScoreClass string
+	Threshold  float64
 	Tree       *TreeNode
 	Files      map[string]*FileData
+	Operators  []OperatorStats
+	History    []HistoryRecord
+	Tests      []TestData
 }
 
-func writeHTMLReport(mutants []testing.Mutant, stats ReportStats, threshold float64, resolver *subconfig.Resolver, outputFile string) error {
+// TestData is a test on the tests tab with the mutants it killed.
+type TestData struct {
+	Name    string
+	Package string
+	Killed  []int // mutant IDs
+}
+
+// maxHTMLHistory bounds the runs charted on the trends tab.
+const maxHTMLHistory = 500
+
+// maxHTMLKillOutput bounds the test output kept per mutant; the start of
+// the output names the failing test and assertion.
+const maxHTMLKillOutput = 4 << 10
+
+func writeHTMLReport(mutants []testing.Mutant, stats ReportStats, bd Breakdown, threshold float64, resolver *subconfig.Resolver, history []HistoryRecord, outputFile string) error {
 	if outputFile == "" {
 		return fmt.Errorf("output file path is required for HTML format")
 	}
 
+	if err := os.MkdirAll(outputFile, 0o755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	indexPath := filepath.Join(outputFile, "index.html")
+	f, err := os.Create(indexPath)
+	if err != nil {
+		return fmt.Errorf("failed to create index.html: %w", err)
+	}
+	defer f.Close()
+
+	if err := renderHTMLReport(f, mutants, stats, bd, threshold, resolver, history); err != nil {
+		return fmt.Errorf("failed to write index.html: %w", err)
+	}
+	return nil
+}
+
+// renderHTMLPage renders the HTML report page of in.
+func renderHTMLPage(in reportInput) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := renderHTMLReport(&buf, in.mutants, in.stats, in.breakdown, in.threshold, in.resolver, in.history); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// renderHTMLReport executes the report template for mutants into w.
+func renderHTMLReport(w io.Writer, mutants []testing.Mutant, stats ReportStats, bd Breakdown, threshold float64, resolver *subconfig.Resolver, history []HistoryRecord) error {
 	byFile := GroupMutantsByFile(mutants)
 
 	scoreClass := ScoreClass(stats.Score, threshold)
 
+	summaries := bd.fileSummaries()
+	packageOf := make(map[string]string)
+	for _, p := range bd.Packages {
+		for _, f := range p.Files {
+			packageOf[f.Path] = p.Name
+		}
+	}
+	thresholdFor := func(path string) float64 {
+		if resolver != nil {
+			return resolver.EffectiveThreshold(path, threshold)
+		}
+		return threshold
+	}
+
 	cwd, _ := os.Getwd()
+	relative := relativizer()
 	filesData := make(map[string]*FileData)
+	dirTests := make(map[string][]string)
+	tests := make(map[string]*TestData)
 	for filePath, lineMutants := range byFile {
 		content, err := os.ReadFile(filePath)
 		if err != nil {
@@ -308,10 +182,18 @@ func writeHTMLReport(mutants []testing.Mutant, stats ReportStats, threshold floa
 
 				for _, m := range mutantsOnLine {
 					lineStatuses[i].Mutants = append(lineStatuses[i].Mutants, MutantInfo{
-						ID:       m.ID,
-						Operator: m.Operator.Name(),
-						Status:   m.Status,
-						KilledBy: m.KilledBy,
+						ID:          m.ID,
+						Column:      m.Site.Column,
+						Fingerprint: m.Fingerprint,
+						Operator:    m.Operator.Name(),
+						Status:      m.Status,
+						KilledBy:    m.KilledBy,
+						Original:    m.Original,
+						Mutated:     m.Mutated,
+						Diff:        m.Diff,
+						KillOutput:  trimKillOutput(m.KillOutput),
+						DurationMS:  m.KillDuration.Milliseconds(),
+						ErrorReason: m.ErrorReason,
 					})
 
 					switch m.Status {
@@ -334,6 +216,11 @@ func writeHTMLReport(mutants []testing.Mutant, stats ReportStats, threshold floa
 					}
 				}
 
+				// Mutants sharing the line are listed left to right.
+				sort.SliceStable(lineStatuses[i].Mutants, func(a, b int) bool {
+					return lineStatuses[i].Mutants[a].Column < lineStatuses[i].Mutants[b].Column
+				})
+
 				// Priority: survived > timeout > untested > error > killed
 				if hasSurvived {
 					lineStatuses[i].Status = testing.StatusSurvived
@@ -349,29 +236,49 @@ func writeHTMLReport(mutants []testing.Mutant, stats ReportStats, threshold floa
 			}
 		}
 
-		fileKilled, fileSurvived, fileUntested, fileTimeout := 0, 0, 0, 0
-		for _, mutantsOnLine := range lineMutants {
-			for _, m := range mutantsOnLine {
-				switch m.Status {
-				case testing.StatusKilled:
-					fileKilled++
-				case testing.StatusSurvived:
-					fileSurvived++
-				case testing.StatusUntested:
-					fileUntested++
-				case testing.StatusTimeout:
-					fileTimeout++
-				}
-			}
+		summary := summaries[filePath]
+		fileThreshold := thresholdFor(filePath)
+		var functions []FunctionData
+		for _, fn := range summary.Functions {
+			functions = append(functions, FunctionData{
+				Name:       fn.Name,
+				Line:       fn.Line,
+				Score:      fn.Stats.Score,
+				ScoreClass: ScoreClass(fn.Stats.Score, fileThreshold),
+				Scored:     fn.Scored(),
+			})
 		}
 
-		fileScore := CalculateScore(fileKilled, fileSurvived, fileUntested, fileTimeout)
-		fileThreshold := threshold
-		if resolver != nil {
-			fileThreshold = resolver.EffectiveThreshold(filePath, threshold)
+		pkg, ok := packageOf[filePath]
+		if !ok {
+			pkg = filepath.ToSlash(filepath.Dir(relative(filePath)))
+		}
+		dir := filepath.Dir(filePath)
+		pkgTests, ok := dirTests[dir]
+		if !ok {
+			for name := range collectTests(dir, relative, nil) {
+				pkgTests = append(pkgTests, name)
+			}
+			sort.Strings(pkgTests)
+			dirTests[dir] = pkgTests
+			for _, name := range pkgTests {
+				tests[pkg+"."+name] = &TestData{Name: name, Package: pkg}
+			}
+		}
+		for _, line := range lineMutants {
+			for _, m := range line {
+				if m.Status != testing.StatusKilled || m.KilledBy == "" {
+					continue
+				}
+				// Subtests and suite tags are shown with each mutant; the
+				// tab lists the test functions.
+				key := pkg + "." + topLevelTest(m.KilledBy)
+				if tests[key] == nil {
+					tests[key] = &TestData{Name: topLevelTest(m.KilledBy), Package: pkg}
+				}
+				tests[key].Killed = append(tests[key].Killed, m.ID)
+			}
 		}
-
-		fileScoreClass := ScoreClass(fileScore, fileThreshold)
 
 		relPath := filePath
 		if cwd != "" {
@@ -383,37 +290,64 @@ func writeHTMLReport(mutants []testing.Mutant, stats ReportStats, threshold floa
 			Path:       filePath,
 			RelPath:    relPath,
 			Lines:      lineStatuses,
-			Score:      fileScore,
-			ScoreClass: fileScoreClass,
+			Stats:      summary.Stats,
+			Score:      summary.Stats.Score,
+			ScoreClass: ScoreClass(summary.Stats.Score, fileThreshold),
+			Functions:  functions,
+			Package:    pkg,
+			Tests:      pkgTests,
 		}
 	}
 
 	tree := buildTree(filesData)
+	scoreTree(tree, thresholdFor)
 
 	data := ReportData{
 		Stats:      stats,
 		ScoreClass: scoreClass,
+		Threshold:  threshold,
 		Tree:       tree,
 		Files:      filesData,
-	}
-
-	if err := os.MkdirAll(outputFile, 0o755); err != nil {
-		return fmt.Errorf("failed to create output directory: %w", err)
+		Operators:  bd.Operators,
+		History:    history[max(0, len(history)-maxHTMLHistory):],
+		Tests:      sortedTests(tests),
 	}
 
 	tmpl := template.Must(template.New("report").Parse(reportTemplate))
-	indexPath := filepath.Join(outputFile, "index.html")
-	f, err := os.Create(indexPath)
-	if err != nil {
-		return fmt.Errorf("failed to create index.html: %w", err)
-	}
-	defer f.Close()
+	return tmpl.Execute(w, data)
+}
 
-	if err := tmpl.Execute(f, data); err != nil {
-		return fmt.Errorf("failed to write index.html: %w", err)
+// trimKillOutput cuts out down to maxHTMLKillOutput bytes at a line break.
+func trimKillOutput(out string) string {
+	if len(out) <= maxHTMLKillOutput {
+		return out
+	}
+	cut := out[:maxHTMLKillOutput]
+	if i := strings.LastIndexByte(cut, '\n'); i > 0 {
+		cut = cut[:i+1]
 	}
+	return cut + fmt.Sprintf("... (%d more bytes)\n", len(out)-len(cut))
+}
 
-	return nil
+// sortedTests orders the tests by the mutants they killed, most first, then
+// by package and name.
+func sortedTests(tests map[string]*TestData) []TestData {
+	out := make([]TestData, 0, len(tests))
+	for _, t := range tests {
+		sort.Ints(t.Killed)
+		out = append(out, *t)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		a, b := out[i], out[j]
+		if len(a.Killed) != len(b.Killed) {
+			return len(a.Killed) > len(b.Killed)
+		}
+		if a.Package != b.Package {
+			return a.Package < b.Package
+		}
+		return a.Name < b.Name
+	})
+	return out
 }
 
 func buildTree(filesData map[string]*FileData) *TreeNode {
@@ -441,8 +375,10 @@ func buildTree(filesData map[string]*FileData) *TreeNode {
 					Children: []*TreeNode{},
 				}
 				if isLast {
+					child.Stats = fileData.Stats
 					child.Score = fileData.Score
 					child.ScoreClass = fileData.ScoreClass
+					child.Functions = fileData.Functions
 				}
 				current.Children = append(current.Children, child)
 			}
@@ -454,6 +390,27 @@ func buildTree(filesData map[string]*FileData) *TreeNode {
 	return root
 }
 
+// scoreTree sums the stats of every directory from the files below it and
+// scores it against the threshold of its first file.
+func scoreTree(node *TreeNode, thresholdFor func(path string) float64) {
+	if !node.IsDir {
+		node.Scored = node.Stats.Killed+node.Stats.Survived+node.Stats.Untested+node.Stats.Timeout > 0
+		return
+	}
+	var sum ReportStats
+	for _, child := range node.Children {
+		scoreTree(child, thresholdFor)
+		sum.Killed += child.Stats.Killed
+		sum.Survived += child.Stats.Survived
+		sum.Untested += child.Stats.Untested
+		sum.Timeout += child.Stats.Timeout
+	}
+	node.Stats = sum
+	node.Scored = sum.Killed+sum.Survived+sum.Untested+sum.Timeout > 0
+	node.Score = CalculateScore(sum.Killed, sum.Survived, sum.Untested, sum.Timeout)
+	node.ScoreClass = ScoreClass(node.Score, thresholdFor(node.Path))
+}
+
 func sortTree(node *TreeNode) {
 	sort.Slice(node.Children, func(i, j int) bool {
 		if node.Children[i].IsDir != node.Children[j].IsDir {
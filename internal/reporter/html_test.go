@@ -0,0 +1,78 @@
+//go:build unit
+// +build unit
+
+package reporter
+
+import (
+	"bytes"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	core "github.com/aclfe/gorgon/internal/core"
+	"github.com/aclfe/gorgon/internal/engine"
+	"github.com/aclfe/gorgon/pkg/mutator/operators/arithmetic_flip"
+)
+
+func TestHTMLReportMutantDetailsAndTests(t *testing.T) {
+	root := t.TempDir()
+	dir := filepath.Join(root, "calc")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	src := filepath.Join(dir, "calc.go")
+	for path, content := range map[string]string{
+		src:                                "package calc\n\nfunc Add(a, b int) int { return a + b }\n",
+		filepath.Join(dir, "calc_test.go"): "package calc\n\nimport \"testing\"\n\nfunc TestAdd(t *testing.T) {}\n\nfunc TestSub(t *testing.T) {}\n",
+	} {
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	file := token.NewFileSet().AddFile(src, -1, 100)
+	survivor := core.Mutant{
+		ID:          1,
+		Fingerprint: "5124b415cdde32e6",
+		Status:      core.StatusSurvived,
+		Operator:    arithmetic_flip.ArithmeticFlip{},
+		Original:    "a + b",
+		Mutated:     "a - b",
+		Diff:        "@@ -3 +3 @@\n-a + b\n+a - b\n",
+		Site:        engine.Site{File: file, Line: 3, Column: 40},
+	}
+	killed := survivor
+	killed.ID, killed.Status, killed.Site.Column = 2, core.StatusKilled, 33
+	killed.KilledBy = "TestAdd/positive"
+	killed.KillDuration = 120 * time.Millisecond
+	killed.KillOutput = strings.Repeat("--- FAIL: TestAdd/positive\n", 1000)
+	mutants := []core.Mutant{survivor, killed}
+
+	var buf bytes.Buffer
+	if err := renderHTMLReport(&buf, mutants, computeStats(mutants, 0), Aggregate(mutants, root), 80, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	page := buf.String()
+	for _, s := range []string{
+		`"Tests":["TestAdd","TestSub"]`,
+		`{"Name":"TestAdd","Package":"calc","Killed":[2]}`,
+		`{"Name":"TestSub","Package":"calc","Killed":null}`,
+		`"DurationMS":120`,
+		`id="tab-tests"`,
+		`id="filter-status"`,
+	} {
+		if !strings.Contains(page, s) {
+			t.Errorf("report lacks %s", s)
+		}
+	}
+	// Mutants sharing a line are listed by column.
+	if i, j := strings.Index(page, `"ID":2,"Column":33`), strings.Index(page, `"ID":1,"Column":40`); i < 0 || j < 0 || i > j {
+		t.Error("mutants on a line are not ordered by column")
+	}
+	if strings.Count(page, "--- FAIL: TestAdd/positive") > maxHTMLKillOutput/len("--- FAIL: TestAdd/positive\n") {
+		t.Error("kill output was not trimmed")
+	}
+}
@@ -2,44 +2,104 @@ package reporter
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/token"
 	"os"
+	"path/filepath"
+	"time"
 
 	testing "github.com/aclfe/gorgon/internal/core"
+	"github.com/aclfe/gorgon/internal/engine"
+	"github.com/aclfe/gorgon/pkg/mutator"
 )
 
+// JSONSchemaVersion is the version of the JSON report layout. It changes
+// whenever a field is removed or changes meaning, so that `gorgon report
+// --from` can refuse reports it would misread. Adding fields keeps it.
+const JSONSchemaVersion = 1
+
 type jsonReport struct {
-	Summary ReportStats  `json:"summary"`
-	Mutants []jsonMutant `json:"mutants"`
+	SchemaVersion int              `json:"schema_version"`
+	GeneratedAt   time.Time        `json:"generated_at"`
+	ProjectRoot   string           `json:"project_root,omitempty"`
+	Threshold     float64          `json:"threshold"`
+	Summary       ReportStats      `json:"summary"`
+	Packages      []PackageSummary `json:"packages"` // summaries by package, file and function
+	Operators     []OperatorStats  `json:"operators"`
+	Mutants       []jsonMutant     `json:"mutants"`
 }
 
 type jsonMutant struct {
-	ID       int    `json:"id"`
-	Status   string `json:"status"`
-	Operator string `json:"operator"`
-	File     string `json:"file"`
-	Line     int    `json:"line"`
-	Column   int    `json:"column"`
-	KilledBy string `json:"killed_by,omitempty"`
-	Error    string `json:"error,omitempty"`
+	ID             int       `json:"id"`
+	Fingerprint    string    `json:"fingerprint"`
+	Status         string    `json:"status"`
+	Operator       string    `json:"operator"`
+	File           string    `json:"file"`           // absolute path at the time of the run
+	Path           string    `json:"path,omitempty"` // slash-separated, relative to project_root
+	Line           int       `json:"line"`
+	Column         int       `json:"column"`
+	Function       string    `json:"function,omitempty"` // Func, T.Method or (*T).Method
+	Span           *jsonSpan `json:"span,omitempty"`     // extent of the mutated node
+	Original       string    `json:"original,omitempty"`
+	Mutated        string    `json:"mutated,omitempty"`
+	Diff           string    `json:"diff,omitempty"`
+	KilledBy       string    `json:"killed_by,omitempty"`
+	KillDurationMS float64   `json:"kill_duration_ms,omitempty"`
+	Error          string    `json:"error,omitempty"`
+	ErrorReason    string    `json:"error_reason,omitempty"`
+	Preflight      bool      `json:"preflight,omitempty"` // rejected before any build
+}
+
+// jsonSpan is a 1-based source range; the end column is exclusive.
+type jsonSpan struct {
+	StartLine   int `json:"start_line"`
+	StartColumn int `json:"start_column"`
+	EndLine     int `json:"end_line"`
+	EndColumn   int `json:"end_column"`
 }
 
-func writeJSONReport(mutants []testing.Mutant, stats ReportStats, outputFile string) error {
+func writeJSONReport(mutants []testing.Mutant, stats ReportStats, bd Breakdown, threshold float64, root string, outputFile string) error {
+	if root != "" {
+		if abs, err := filepath.Abs(root); err == nil {
+			root = abs
+		}
+	}
 	report := jsonReport{
-		Summary: stats,
-		Mutants: make([]jsonMutant, 0, len(mutants)),
+		SchemaVersion: JSONSchemaVersion,
+		GeneratedAt:   time.Now().UTC(),
+		ProjectRoot:   root,
+		Threshold:     threshold,
+		Summary:       stats,
+		Packages:      bd.Packages,
+		Operators:     bd.Operators,
+		Mutants:       make([]jsonMutant, 0, len(mutants)),
 	}
+	rel := rootRelativizer(root)
 
 	for _, m := range mutants {
+		sp := mutantSpan(m)
 		jm := jsonMutant{
-			ID:       m.ID,
-			Status:   m.Status,
-			Operator: m.Operator.Name(),
-			File:     m.Site.File.Name(),
-			Line:     m.Site.Line,
-			Column:   m.Site.Column,
+			ID:          m.ID,
+			Fingerprint: m.Fingerprint,
+			Status:      m.Status,
+			Operator:    m.Operator.Name(),
+			File:        m.Site.File.Name(),
+			Path:        rel(m.Site.File.Name()),
+			Line:        m.Site.Line,
+			Column:      m.Site.Column,
+			Function:    funcName(m),
+			Span:        &jsonSpan{sp.StartLine, sp.StartColumn, sp.EndLine, sp.EndColumn},
+			Original:    m.Original,
+			Mutated:     m.Mutated,
+			Diff:        m.Diff,
+			KilledBy:    m.KilledBy,
+			ErrorReason: m.ErrorReason,
+			Preflight:   m.Preflight,
 		}
-		if m.KilledBy != "" {
-			jm.KilledBy = m.KilledBy
+		if m.KillDuration > 0 {
+			jm.KillDurationMS = float64(m.KillDuration) / float64(time.Millisecond)
 		}
 		if m.Error != nil {
 			jm.Error = m.Error.Error()
@@ -54,3 +114,198 @@ func writeJSONReport(mutants []testing.Mutant, stats ReportStats, outputFile str
 
 	return os.WriteFile(outputFile, data, 0644)
 }
+
+// Results is a run restored from a JSON report.
+type Results struct {
+	Mutants     []testing.Mutant
+	Total       int
+	Threshold   float64
+	ProjectRoot string // "" for reports written before schema version 1
+}
+
+// LoadJSONReport restores the mutants of a JSON report so any output format
+// can be rendered from it. A file is looked up at its recorded absolute path
+// first and then at its project-relative path under the working directory,
+// so a report from CI can be rendered inside a local checkout. Positions
+// come from the file's source when it is available and covers them;
+// otherwise they are rebuilt from the recorded lines and columns alone.
+func LoadJSONReport(path string) (*Results, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read JSON report: %w", err)
+	}
+	var report jsonReport
+	if err := json.Unmarshal(data, &report); err != nil {
+		return nil, fmt.Errorf("failed to parse JSON report %s: %w", path, err)
+	}
+	if report.SchemaVersion > JSONSchemaVersion {
+		return nil, fmt.Errorf("JSON report %s has schema version %d; this gorgon reads up to %d", path, report.SchemaVersion, JSONSchemaVersion)
+	}
+
+	byFile := make(map[string][]*jsonMutant)
+	var order []string
+	for i := range report.Mutants {
+		jm := &report.Mutants[i]
+		name := localFile(jm)
+		if _, ok := byFile[name]; !ok {
+			order = append(order, name)
+		}
+		byFile[name] = append(byFile[name], jm)
+	}
+
+	fset := token.NewFileSet()
+	files := make(map[string]*token.File, len(order))
+	for _, name := range order {
+		files[name] = restoreFile(fset, name, byFile[name])
+	}
+
+	res := &Results{
+		Mutants:     make([]testing.Mutant, 0, len(report.Mutants)),
+		Total:       report.Summary.Total,
+		Threshold:   report.Threshold,
+		ProjectRoot: report.ProjectRoot,
+	}
+	for i := range report.Mutants {
+		jm := &report.Mutants[i]
+		file := files[localFile(jm)]
+		m := testing.Mutant{
+			ID:          jm.ID,
+			Fingerprint: jm.Fingerprint,
+			Site: engine.Site{
+				File:         file,
+				Fset:         fset,
+				Line:         jm.Line,
+				Column:       jm.Column,
+				FunctionName: jm.Function,
+				Node:         restoredNode(file, jm),
+			},
+			Original:     jm.Original,
+			Mutated:      jm.Mutated,
+			Diff:         jm.Diff,
+			Operator:     restoredOperator(jm.Operator),
+			Status:       jm.Status,
+			KilledBy:     jm.KilledBy,
+			KillDuration: time.Duration(jm.KillDurationMS * float64(time.Millisecond)),
+			ErrorReason:  jm.ErrorReason,
+			Preflight:    jm.Preflight,
+		}
+		if jm.Error != "" {
+			m.Error = errors.New(jm.Error)
+		}
+		res.Mutants = append(res.Mutants, m)
+	}
+	if res.Total < len(res.Mutants) {
+		res.Total = len(res.Mutants)
+	}
+	return res, nil
+}
+
+// localFile is where a reported file lives on this machine.
+func localFile(jm *jsonMutant) string {
+	if _, err := os.Stat(jm.File); err == nil || jm.Path == "" {
+		return jm.File
+	}
+	if abs, err := filepath.Abs(filepath.FromSlash(jm.Path)); err == nil {
+		if _, err := os.Stat(abs); err == nil {
+			return abs
+		}
+	}
+	return jm.File
+}
+
+// restoreFile adds name to fset with a line table that covers every
+// recorded position: the file's own when its source is readable and long
+// enough, otherwise lines of a fixed width wide enough for every column.
+func restoreFile(fset *token.FileSet, name string, mutants []*jsonMutant) *token.File {
+	maxLine, maxCol := 1, 1 // positions are 1-based
+	for _, jm := range mutants {
+		maxLine = max(maxLine, jm.Line)
+		maxCol = max(maxCol, jm.Column)
+		if sp := jm.Span; sp != nil {
+			maxLine = max(maxLine, sp.StartLine, sp.EndLine)
+			maxCol = max(maxCol, sp.StartColumn, sp.EndColumn)
+		}
+	}
+
+	if src, err := os.ReadFile(name); err == nil {
+		f := fset.AddFile(name, -1, len(src))
+		f.SetLinesForContent(src)
+		if f.LineCount() >= maxLine && fits(f, len(src), mutants) {
+			return f
+		}
+		// The source changed since the run; fall through to a synthetic
+		// file rather than point at the wrong code.
+	}
+
+	width := maxCol + 1
+	f := fset.AddFile(name, -1, maxLine*width)
+	lines := make([]int, maxLine)
+	for i := range lines {
+		lines[i] = i * width
+	}
+	f.SetLines(lines)
+	return f
+}
+
+// fits reports whether every recorded position lies within its line of f.
+func fits(f *token.File, size int, mutants []*jsonMutant) bool {
+	// within allows an exclusive end column one past the last byte.
+	within := func(line, col int, exclusive bool) bool {
+		if line < 1 || line > f.LineCount() || col < 1 {
+			return false
+		}
+		lineEnd := size
+		if line < f.LineCount() {
+			lineEnd = f.Offset(f.LineStart(line+1)) - 1
+		}
+		off := f.Offset(f.LineStart(line)) + col - 1
+		return off < lineEnd || (exclusive && off == lineEnd)
+	}
+	for _, jm := range mutants {
+		if !within(jm.Line, jm.Column, false) {
+			return false
+		}
+		if sp := jm.Span; sp != nil && (!within(sp.StartLine, sp.StartColumn, false) || !within(sp.EndLine, sp.EndColumn, true)) {
+			return false
+		}
+	}
+	return true
+}
+
+// restoredNode stands in for the mutated node, covering its recorded span,
+// or just the site for reports that predate spans.
+func restoredNode(f *token.File, jm *jsonMutant) ast.Node {
+	pos := func(line, col int) token.Pos {
+		if line < 1 || line > f.LineCount() || col < 1 {
+			return token.NoPos
+		}
+		return f.LineStart(line) + token.Pos(col-1)
+	}
+	if sp := jm.Span; sp != nil {
+		return spanNode{from: pos(sp.StartLine, sp.StartColumn), to: pos(sp.EndLine, sp.EndColumn)}
+	}
+	return spanNode{from: pos(jm.Line, jm.Column)}
+}
+
+// spanNode is an ast.Node known only by its extent.
+type spanNode struct{ from, to token.Pos }
+
+func (n spanNode) Pos() token.Pos { return n.from }
+func (n spanNode) End() token.Pos { return n.to }
+
+// restoredOperator returns the registered operator of that name, or a
+// stand-in that only knows its name.
+func restoredOperator(name string) mutator.Operator {
+	if op, ok := mutator.Get(name); ok {
+		return op
+	}
+	return namedOperator(name)
+}
+
+// namedOperator is an operator that no longer mutates anything; reports
+// only need its name.
+type namedOperator string
+
+func (o namedOperator) Name() string           { return string(o) }
+func (namedOperator) CanApply(ast.Node) bool   { return false }
+func (namedOperator) Mutate(ast.Node) ast.Node { return nil }
@@ -0,0 +1,98 @@
+//go:build unit
+// +build unit
+
+package reporter
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	core "github.com/aclfe/gorgon/internal/core"
+)
+
+func TestJSONReportRoundTrip(t *testing.T) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	mutants := ciMutants(wd)
+	mutants[1].KilledBy, mutants[1].KillDuration = "TestAdd", 1500*time.Millisecond
+	stats := computeStats(mutants, 2)
+
+	out := filepath.Join(t.TempDir(), "results.json")
+	if err := writeJSONReport(mutants, stats, Aggregate(mutants, wd), 80, wd, out); err != nil {
+		t.Fatal(err)
+	}
+	res, err := LoadJSONReport(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Total != 2 || res.Threshold != 80 || res.ProjectRoot != wd || len(res.Mutants) != 2 {
+		t.Fatalf("unexpected results: %+v", res)
+	}
+
+	got := res.Mutants[0]
+	if got.Fingerprint != mutants[0].Fingerprint || got.Operator.Name() != "arithmetic_flip" ||
+		got.Site.Line != 3 || got.Site.Column != 33 || got.Diff != mutants[0].Diff {
+		t.Errorf("survivor not restored: %+v", got)
+	}
+	if sp, want := mutantSpan(got), mutantSpan(mutants[0]); sp != want {
+		t.Errorf("span = %+v, want %+v", sp, want)
+	}
+	if got.Site.File.Name() != mutants[0].Site.File.Name() {
+		t.Errorf("file = %s", got.Site.File.Name())
+	}
+	if k := res.Mutants[1]; k.KilledBy != "TestAdd" || k.KillDuration != 1500*time.Millisecond {
+		t.Errorf("kill not restored: %+v", k)
+	}
+	if computeStats(res.Mutants, res.Total) != stats {
+		t.Errorf("stats differ after round trip")
+	}
+}
+
+func TestLoadJSONReportRelocatesFiles(t *testing.T) {
+	dir := t.TempDir()
+	src := "package calc\n\nfunc Add(a, b int) int { return a + b }\n"
+	if err := os.MkdirAll(filepath.Join(dir, "calc"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "calc", "calc.go"), []byte(src), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	report := `{"schema_version": 1, "project_root": "/ci/build", "summary": {"total": 1}, "mutants": [
+		{"id": 1, "status": "survived", "operator": "gone_operator", "file": "/ci/build/calc/calc.go", "path": "calc/calc.go",
+		 "line": 3, "column": 35, "span": {"start_line": 3, "start_column": 33, "end_line": 3, "end_column": 38}}]}`
+	path := filepath.Join(dir, "results.json")
+	if err := os.WriteFile(path, []byte(report), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Chdir(dir)
+
+	res, err := LoadJSONReport(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := res.Mutants[0]
+	if want := filepath.Join(dir, "calc", "calc.go"); m.Site.File.Name() != want {
+		t.Errorf("file = %s, want %s", m.Site.File.Name(), want)
+	}
+	if m.Operator.Name() != "gone_operator" || m.Status != core.StatusSurvived {
+		t.Errorf("unexpected mutant: %+v", m)
+	}
+	if sp := mutantSpan(m); sp != (span{3, 33, 3, 38}) {
+		t.Errorf("span = %+v", sp)
+	}
+}
+
+func TestLoadJSONReportRejectsNewerSchema(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "results.json")
+	if err := os.WriteFile(path, []byte(`{"schema_version": 99, "mutants": []}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadJSONReport(path); err == nil || !strings.Contains(err.Error(), "schema version 99") {
+		t.Fatalf("expected a schema version error, got %v", err)
+	}
+}
@@ -9,10 +9,25 @@ import (
 	testing "github.com/aclfe/gorgon/internal/core"
 )
 
+// junitTestSuites carries the run's stats; each package is a suite.
+type junitTestSuites struct {
+	XMLName xml.Name `xml:"testsuites"`
+	Name    string   `xml:"name,attr"`
+	ReportStats
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Skipped  int              `xml:"skipped,attr"`
+	Time     float64          `xml:"time,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
 type junitTestSuite struct {
 	XMLName xml.Name `xml:"testsuite"`
 	Name    string   `xml:"name,attr"`
 	ReportStats
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Skipped   int             `xml:"skipped,attr"`
 	Time      float64         `xml:"time,attr"`
 	TestCases []junitTestCase `xml:"testcase"`
 }
@@ -22,10 +37,20 @@ type junitTestCase struct {
 	Name      string   `xml:"name,attr"`
 	Classname string   `xml:"classname,attr"`
 	Time      float64  `xml:"time,attr"`
+	Properties *junitProperties `xml:"properties,omitempty"`
 	Failure   *junitFailure `xml:"failure,omitempty"`
 	Skipped   *junitSkipped `xml:"skipped,omitempty"`
 }
 
+type junitProperties struct {
+	Property []junitProperty `xml:"property"`
+}
+
+type junitProperty struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value,attr"`
+}
+
 type junitFailure struct {
 	XMLName xml.Name `xml:"failure"`
 	Message string   `xml:"message,attr"`
@@ -37,54 +62,47 @@ type junitSkipped struct {
 	Message string   `xml:"message,attr"`
 }
 
-func writeJUnitReport(mutants []testing.Mutant, stats ReportStats, outputFile string) error {
-	suite := junitTestSuite{
+func writeJUnitReport(mutants []testing.Mutant, stats ReportStats, bd Breakdown, outputFile string) error {
+	suites := junitTestSuites{
 		Name:        "Mutation Testing",
 		ReportStats: stats,
 	}
 
+	files := bd.fileSummaries()
+	byPkg := make(map[string][]testing.Mutant)
 	for _, m := range mutants {
-		tc := junitTestCase{
-			Name:      fmt.Sprintf("%s:%d:%d", filepath.Base(m.Site.File.Name()), m.Site.Line, m.Site.Column),
-			Classname: m.Operator.Name(),
+		pkg := "."
+		if f, ok := files[m.Site.File.Name()]; ok {
+			pkg = filepath.ToSlash(filepath.Dir(f.Name))
 		}
+		byPkg[pkg] = append(byPkg[pkg], m)
+	}
 
-		switch m.Status {
-		case testing.StatusKilled:
-			tc.Time = m.KillDuration.Seconds()
-		case testing.StatusSurvived:
-			tc.Failure = &junitFailure{
-				Message: "Mutant survived",
-				Text:    formatMutantInfo(m),
-			}
-		case testing.StatusTimeout:
-			tc.Failure = &junitFailure{
-				Message: "Mutant timeout",
-				Text:    formatMutantInfo(m),
-			}
-		case testing.StatusError:
-			errMsg := ""
-			if m.Error != nil {
-				errMsg = m.Error.Error()
-			}
-			tc.Failure = &junitFailure{
-				Message: "Execution error",
-				Text:    errMsg,
-			}
-		case testing.StatusUntested:
-			tc.Skipped = &junitSkipped{
-				Message: "Package failed to compile or has no tests",
-			}
-		case testing.StatusInvalid:
-			tc.Skipped = &junitSkipped{
-				Message: "Mutant marked invalid",
+	for _, p := range bd.Packages {
+		suite := junitTestSuite{
+			Name:        p.Name,
+			ReportStats: p.Stats,
+		}
+		for _, m := range byPkg[p.Name] {
+			tc := junitCase(m)
+			suite.Time += tc.Time
+			switch {
+			case tc.Failure != nil:
+				suite.Failures++
+			case tc.Skipped != nil:
+				suite.Skipped++
 			}
+			suite.TestCases = append(suite.TestCases, tc)
 		}
-
-		suite.TestCases = append(suite.TestCases, tc)
+		suite.Tests = len(suite.TestCases)
+		suites.Tests += suite.Tests
+		suites.Failures += suite.Failures
+		suites.Skipped += suite.Skipped
+		suites.Time += suite.Time
+		suites.Suites = append(suites.Suites, suite)
 	}
 
-	data, err := xml.MarshalIndent(suite, "", "  ")
+	data, err := xml.MarshalIndent(suites, "", "  ")
 	if err != nil {
 		return err
 	}
@@ -92,6 +110,55 @@ func writeJUnitReport(mutants []testing.Mutant, stats ReportStats, outputFile st
 	return os.WriteFile(outputFile, append([]byte(xml.Header), data...), 0644)
 }
 
+// junitCase reports a mutant as a test case: survivors, timeouts and errors
+// fail; untested and invalid mutants are skipped.
+func junitCase(m testing.Mutant) junitTestCase {
+	tc := junitTestCase{
+		Name:      fmt.Sprintf("%s:%d:%d", filepath.Base(m.Site.File.Name()), m.Site.Line, m.Site.Column),
+		Classname: m.Operator.Name(),
+		Properties: &junitProperties{Property: []junitProperty{
+			{Name: "fingerprint", Value: m.Fingerprint},
+		}},
+	}
+
+	switch m.Status {
+	case testing.StatusKilled:
+		tc.Time = m.KillDuration.Seconds()
+	case testing.StatusSurvived:
+		tc.Failure = &junitFailure{
+			Message: "Mutant survived",
+			Text:    formatMutantInfo(m),
+		}
+	case testing.StatusTimeout:
+		tc.Failure = &junitFailure{
+			Message: "Mutant timeout",
+			Text:    formatMutantInfo(m),
+		}
+	case testing.StatusError:
+		errMsg := ""
+		if m.Error != nil {
+			errMsg = m.Error.Error()
+		}
+		tc.Failure = &junitFailure{
+			Message: "Execution error",
+			Text:    errMsg,
+		}
+	case testing.StatusUntested:
+		tc.Skipped = &junitSkipped{
+			Message: "Package failed to compile or has no tests",
+		}
+	case testing.StatusInvalid:
+		tc.Skipped = &junitSkipped{
+			Message: "Mutant marked invalid",
+		}
+	}
+	return tc
+}
+
 func formatMutantInfo(m testing.Mutant) string {
-	return fmt.Sprintf("Operator: %s\nFile: %s:%d\nMutant ID: %d", m.Operator.Name(), m.Site.File.Name(), m.Site.Line, m.ID)
+	info := fmt.Sprintf("Operator: %s\nFile: %s:%d\nMutant ID: %d\nFingerprint: %s", m.Operator.Name(), m.Site.File.Name(), m.Site.Line, m.ID, m.Fingerprint)
+	if m.Diff != "" {
+		info += "\n\n" + m.Diff
+	}
+	return info
 }
@@ -0,0 +1,211 @@
+package reporter
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/aclfe/gorgon/internal/baseline"
+	testing "github.com/aclfe/gorgon/internal/core"
+	"github.com/aclfe/gorgon/internal/diff"
+)
+
+// markdownCommentLimit is GitHub's maximum comment body size. The markdown
+// report is meant to be posted as a pull-request comment, so it never grows
+// past it.
+const markdownCommentLimit = 65536
+
+// markdownReserve is kept free for closing tags and truncation notes.
+const markdownReserve = 1024
+
+// markdownBudget is a builder that knows how much room is left.
+type markdownBudget struct {
+	strings.Builder
+	limit int
+}
+
+func (b *markdownBudget) fits(s string) bool {
+	return b.Len()+len(s) <= b.limit-markdownReserve
+}
+
+// writeMarkdownReport renders a pull-request comment: the score and its
+// change against prev (the baseline as it was before this run, or nil), a
+// per-package table, and collapsible lists of survivors with their code at
+// paths relative to root.
+// When changed is set, survivors on those lines get their own section ahead
+// of the rest.
+func writeMarkdownReport(mutants []testing.Mutant, stats ReportStats, bd Breakdown, threshold float64, root string, prev *baseline.Data, changed diff.FileLines, outputFile string) error {
+	data := renderMarkdownReport(mutants, stats, bd, threshold, root, prev, changed, markdownCommentLimit)
+	return os.WriteFile(outputFile, []byte(data), 0644)
+}
+
+func renderMarkdownReport(mutants []testing.Mutant, stats ReportStats, bd Breakdown, threshold float64, root string, prev *baseline.Data, changed diff.FileLines, limit int) string {
+	rel := rootRelativizer(root)
+	b := &markdownBudget{limit: limit}
+	b.WriteString("## Gorgon mutation testing\n\n")
+
+	score := fmt.Sprintf("**Mutation score: %.2f%%**", stats.Score)
+	if prev != nil {
+		score += fmt.Sprintf(" (%+.2f pp vs baseline %.2f%%)", stats.Score-prev.Score, prev.Score)
+	}
+	if threshold > 0 {
+		verdict := "passed"
+		if stats.Score < threshold {
+			verdict = "failed"
+		}
+		score += fmt.Sprintf(" — threshold %.2f%% %s", threshold, verdict)
+	}
+	b.WriteString(score + "\n\n")
+	b.WriteString("| Killed | Survived | Timeout | Untested | Compile Errors | Runtime Errors | Invalid | Total |\n")
+	b.WriteString("|---:|---:|---:|---:|---:|---:|---:|---:|\n")
+	fmt.Fprintf(b, "| %d | %d | %d | %d | %d | %d | %d | %d |\n\n",
+		stats.Killed, stats.Survived, stats.Timeout, stats.Untested, stats.CompileErrors, stats.RuntimeErrors, stats.Invalid, stats.Total)
+
+	var newSurvivors, otherSurvivors []testing.Mutant
+	var newStats ReportStats
+	for _, m := range mutants {
+		inNew := changed != nil && onChangedLine(m, changed)
+		if inNew {
+			countStatus(&newStats, m)
+		}
+		if m.Status != testing.StatusSurvived {
+			continue
+		}
+		if inNew {
+			newSurvivors = append(newSurvivors, m)
+		} else {
+			otherSurvivors = append(otherSurvivors, m)
+		}
+	}
+
+	if changed != nil {
+		b.WriteString("### New code\n\n")
+		newStats.Score = CalculateScore(newStats.Killed, newStats.Survived, newStats.Untested, newStats.Timeout)
+		if newStats.Total == 0 {
+			b.WriteString("No mutants on changed lines.\n\n")
+		} else {
+			fmt.Fprintf(b, "Score on changed lines: **%.2f%%** (%d killed, %d survived of %d mutants)\n\n",
+				newStats.Score, newStats.Killed, newStats.Survived, newStats.Total)
+		}
+	}
+
+	writeMarkdownPackages(b, bd)
+
+	if changed != nil {
+		writeMarkdownSurvivors(b, "surviving mutant(s) in new code", newSurvivors, true, rel)
+		writeMarkdownSurvivors(b, "other surviving mutant(s)", otherSurvivors, false, rel)
+	} else {
+		writeMarkdownSurvivors(b, "surviving mutant(s)", otherSurvivors, false, rel)
+	}
+	return b.String()
+}
+
+func onChangedLine(m testing.Mutant, changed diff.FileLines) bool {
+	if m.Site.File == nil {
+		return false
+	}
+	path, err := filepath.Abs(m.Site.File.Name())
+	if err != nil {
+		path = m.Site.File.Name()
+	}
+	return changed[path][m.Site.Line]
+}
+
+func countStatus(s *ReportStats, m testing.Mutant) {
+	s.Total++
+	switch m.Status {
+	case testing.StatusKilled:
+		s.Killed++
+	case testing.StatusSurvived:
+		s.Survived++
+	case testing.StatusUntested:
+		s.Untested++
+	case testing.StatusTimeout:
+		s.Timeout++
+	}
+}
+
+// writeMarkdownPackages writes one row per package of the breakdown, lowest
+// score first, so the packages that need attention survive truncation.
+func writeMarkdownPackages(b *markdownBudget, bd Breakdown) {
+	if len(bd.Packages) == 0 {
+		return
+	}
+	pkgs := append([]PackageSummary(nil), bd.Packages...)
+	sort.SliceStable(pkgs, func(i, j int) bool {
+		return pkgs[i].Stats.Score < pkgs[j].Stats.Score
+	})
+
+	b.WriteString("### Packages\n\n")
+	b.WriteString("| Package | Score | Killed | Survived | Timeout | Untested | Total |\n")
+	b.WriteString("|---|---:|---:|---:|---:|---:|---:|\n")
+	for i, pkg := range pkgs {
+		s := pkg.Stats
+		row := fmt.Sprintf("| `%s` | %.2f%% | %d | %d | %d | %d | %d |\n", pkg.Name, s.Score, s.Killed, s.Survived, s.Timeout, s.Untested, s.Total)
+		if !b.fits(row) {
+			fmt.Fprintf(b, "\n_…and %d more package(s)._\n", len(pkgs)-i)
+			break
+		}
+		b.WriteString(row)
+	}
+	b.WriteString("\n")
+}
+
+// writeMarkdownSurvivors writes a collapsible list of survivors. Entries
+// lose their code once it no longer fits, and the list ends with a count of
+// what was left out once even that is too much.
+func writeMarkdownSurvivors(b *markdownBudget, title string, survivors []testing.Mutant, open bool, rel func(string) string) {
+	if len(survivors) == 0 {
+		return
+	}
+	sort.SliceStable(survivors, func(i, j int) bool {
+		fi, fj := survivors[i].Site.File.Name(), survivors[j].Site.File.Name()
+		if fi != fj {
+			return fi < fj
+		}
+		if survivors[i].Site.Line != survivors[j].Site.Line {
+			return survivors[i].Site.Line < survivors[j].Site.Line
+		}
+		return survivors[i].Site.Column < survivors[j].Site.Column
+	})
+
+	attr := ""
+	if open {
+		attr = " open"
+	}
+	fmt.Fprintf(b, "<details%s>\n<summary>%d %s</summary>\n\n", attr, len(survivors), title)
+	for i, m := range survivors {
+		head := fmt.Sprintf("- `%s:%d:%d` `%s` `%s`\n", rel(m.Site.File.Name()), m.Site.Line, m.Site.Column, m.Operator.Name(), m.Fingerprint)
+		entry := head
+		if m.Diff != "" {
+			entry += markdownCodeBlock("diff", m.Diff)
+		}
+		if !b.fits(entry) {
+			entry = head
+		}
+		if !b.fits(entry) {
+			fmt.Fprintf(b, "\n_…and %d more not shown; see the full report._\n", len(survivors)-i)
+			break
+		}
+		b.WriteString(entry)
+	}
+	b.WriteString("\n</details>\n\n")
+}
+
+// markdownCodeBlock fences code in a list item, using a fence longer than
+// any backtick run inside it.
+func markdownCodeBlock(lang, code string) string {
+	fence := "```"
+	for strings.Contains(code, fence) {
+		fence += "`"
+	}
+	var sb strings.Builder
+	sb.WriteString("\n  " + fence + lang + "\n")
+	for _, line := range strings.Split(strings.TrimSuffix(code, "\n"), "\n") {
+		sb.WriteString("  " + line + "\n")
+	}
+	sb.WriteString("  " + fence + "\n\n")
+	return sb.String()
+}
@@ -0,0 +1,78 @@
+//go:build unit
+// +build unit
+
+package reporter
+
+import (
+	"go/token"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/aclfe/gorgon/internal/baseline"
+	core "github.com/aclfe/gorgon/internal/core"
+	"github.com/aclfe/gorgon/internal/diff"
+	"github.com/aclfe/gorgon/internal/engine"
+	"github.com/aclfe/gorgon/pkg/mutator/operators/arithmetic_flip"
+)
+
+func markdownMutants(t *testing.T, n int) ([]core.Mutant, string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "calc", "calc.go")
+	file := token.NewFileSet().AddFile(path, -1, 1000)
+	mutants := make([]core.Mutant, n)
+	for i := range mutants {
+		mutants[i] = core.Mutant{
+			ID:          i + 1,
+			Fingerprint: strings.Repeat("f", 15) + string(rune('a'+i%26)),
+			Status:      core.StatusSurvived,
+			Operator:    arithmetic_flip.ArithmeticFlip{},
+			Diff:        "--- calc.go\n+++ calc.go\n@@ -1 +1 @@\n-a + b\n+a - b\n",
+			Site:        engine.Site{File: file, Line: i + 1, Column: 1},
+		}
+	}
+	mutants[0].Status = core.StatusKilled
+	return mutants, path
+}
+
+func TestRenderMarkdownReport(t *testing.T) {
+	mutants, path := markdownMutants(t, 4)
+	stats := computeStats(mutants, len(mutants))
+	changed := diff.FileLines{path: {2: true}}
+
+	root := filepath.Dir(filepath.Dir(path))
+	bd := Aggregate(mutants, root)
+
+	out := renderMarkdownReport(mutants, stats, bd, 50, root, &baseline.Data{Score: 30}, changed, markdownCommentLimit)
+	for _, want := range []string{
+		"**Mutation score: 25.00%** (-5.00 pp vs baseline 30.00%) — threshold 50.00% failed",
+		"### New code",
+		"1 surviving mutant(s) in new code",
+		"2 other surviving mutant(s)",
+		"### Packages",
+		"| `calc` | 25.00% | 1 | 3 | 0 | 0 | 4 |",
+		"- `calc/calc.go:2:1`",
+		"```diff",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in:\n%s", want, out)
+		}
+	}
+}
+
+func TestRenderMarkdownReport_StaysWithinBudget(t *testing.T) {
+	mutants, _ := markdownMutants(t, 500)
+	stats := computeStats(mutants, len(mutants))
+	limit := 8 * 1024
+
+	out := renderMarkdownReport(mutants, stats, Aggregate(mutants, ""), 0, "", nil, nil, limit)
+	if len(out) > limit {
+		t.Fatalf("report is %d bytes, over the %d byte budget", len(out), limit)
+	}
+	if !strings.Contains(out, "more not shown") {
+		t.Error("expected a truncation note")
+	}
+	if !strings.HasSuffix(out, "</details>\n\n") {
+		t.Error("expected the survivor list to be closed after truncation")
+	}
+}
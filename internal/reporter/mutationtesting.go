@@ -0,0 +1,286 @@
+package reporter
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	testing "github.com/aclfe/gorgon/internal/core"
+)
+
+// Types below follow the mutation-testing-report-schema
+// (https://github.com/stryker-mutator/mutation-testing-elements), which the
+// mutation-testing-elements web component and the Stryker dashboard read.
+
+const mutationTestingSchemaVersion = "2"
+
+type mteReport struct {
+	Schema        string                 `json:"$schema"`
+	SchemaVersion string                 `json:"schemaVersion"`
+	Thresholds    mteThresholds          `json:"thresholds"`
+	ProjectRoot   string                 `json:"projectRoot,omitempty"`
+	Files         map[string]mteFile     `json:"files"`
+	TestFiles     map[string]mteTestFile `json:"testFiles,omitempty"`
+	Framework     mteFramework           `json:"framework"`
+}
+
+type mteThresholds struct {
+	High float64 `json:"high"`
+	Low  float64 `json:"low"`
+}
+
+type mteFramework struct {
+	Name string `json:"name"`
+}
+
+type mteFile struct {
+	Language string      `json:"language"`
+	Source   string      `json:"source"`
+	Mutants  []mteMutant `json:"mutants"`
+}
+
+type mteMutant struct {
+	ID           string      `json:"id"`
+	MutatorName  string      `json:"mutatorName"`
+	Replacement  string      `json:"replacement,omitempty"`
+	Location     mteLocation `json:"location"`
+	Status       string      `json:"status"`
+	StatusReason string      `json:"statusReason,omitempty"`
+	KilledBy     []string    `json:"killedBy,omitempty"`
+	CoveredBy    []string    `json:"coveredBy,omitempty"`
+	Duration     int64       `json:"duration,omitempty"`
+}
+
+type mteLocation struct {
+	Start mtePosition `json:"start"`
+	End   mtePosition `json:"end"`
+}
+
+type mtePosition struct {
+	Line   int `json:"line"`
+	Column int `json:"column"`
+}
+
+type mteTestFile struct {
+	Source string    `json:"source,omitempty"`
+	Tests  []mteTest `json:"tests"`
+}
+
+type mteTest struct {
+	ID       string       `json:"id"`
+	Name     string       `json:"name"`
+	Location *mteLocation `json:"location,omitempty"`
+}
+
+// mteStatus maps a Gorgon status onto the schema's mutant status enum.
+func mteStatus(m testing.Mutant) string {
+	switch m.Status {
+	case testing.StatusKilled:
+		return "Killed"
+	case testing.StatusSurvived:
+		return "Survived"
+	case testing.StatusUntested:
+		return "NoCoverage"
+	case testing.StatusTimeout:
+		return "Timeout"
+	case testing.StatusError:
+		if m.KilledBy == "(compiler)" {
+			return "CompileError"
+		}
+		return "RuntimeError"
+	case testing.StatusInvalid:
+		// Invalid mutants are rejected by type checking before they run.
+		return "CompileError"
+	}
+	return "Pending"
+}
+
+// packageTests indexes the tests of one package directory by function name.
+type packageTests map[string]string
+
+// writeMutationTestingReport writes mutants in the mutation-testing-report
+// schema, with file paths relative to root. Files whose source can't be read
+// are left out, since the viewers need the source to place mutants.
+func writeMutationTestingReport(mutants []testing.Mutant, threshold float64, root, outputFile string) error {
+	if root == "" {
+		root, _ = os.Getwd()
+	}
+	rel := relativeTo(root)
+
+	high := threshold
+	if high <= 0 {
+		high = 80
+	}
+	report := mteReport{
+		Schema:        "https://git.io/mutation-testing-schema",
+		SchemaVersion: mutationTestingSchemaVersion,
+		Thresholds:    mteThresholds{High: high, Low: min(60, high)},
+		ProjectRoot:   root,
+		Files:         make(map[string]mteFile),
+		TestFiles:     make(map[string]mteTestFile),
+		Framework:     mteFramework{Name: "Gorgon"},
+	}
+
+	tests := make(map[string]packageTests)
+	skipped := 0
+	for filePath, fileMutants := range groupMutantsByPath(mutants) {
+		source, err := os.ReadFile(filePath)
+		if err != nil {
+			skipped += len(fileMutants)
+			continue
+		}
+		dir := filepath.Dir(filePath)
+		pkgTests, ok := tests[dir]
+		if !ok {
+			pkgTests = collectTests(dir, rel, report.TestFiles)
+			tests[dir] = pkgTests
+		}
+
+		file := mteFile{Language: "go", Source: string(source)}
+		for _, m := range fileMutants {
+			file.Mutants = append(file.Mutants, mteMutantFor(m, pkgTests))
+		}
+		report.Files[rel(filePath)] = file
+	}
+	if skipped > 0 {
+		fmt.Fprintf(os.Stderr, "Warning: left %d mutant(s) out of %s: their source could not be read\n", skipped, outputFile)
+	}
+
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(outputFile, data, 0644)
+}
+
+func mteMutantFor(m testing.Mutant, pkgTests packageTests) mteMutant {
+	// Fingerprints keep ids stable across runs for dashboards that track
+	// mutants over time.
+	id := m.Fingerprint
+	if id == "" {
+		id = strconv.Itoa(m.ID)
+	}
+	mm := mteMutant{
+		ID:          id,
+		MutatorName: m.Operator.Name(),
+		Replacement: m.Mutated,
+		Location:    mteSpan(mutantSpan(m)),
+		Status:      mteStatus(m),
+		Duration:    m.KillDuration.Milliseconds(),
+	}
+
+	switch m.Status {
+	case testing.StatusKilled, testing.StatusSurvived, testing.StatusTimeout:
+		// Every test of the package runs against each of its mutants.
+		for _, id := range pkgTests {
+			mm.CoveredBy = append(mm.CoveredBy, id)
+		}
+		sort.Strings(mm.CoveredBy)
+	}
+	if m.Status == testing.StatusKilled && m.KilledBy != "" {
+		if id, ok := pkgTests[topLevelTest(m.KilledBy)]; ok {
+			mm.KilledBy = []string{id}
+		} else {
+			mm.StatusReason = "killed by " + m.KilledBy
+		}
+	}
+	if m.Status == testing.StatusError {
+		mm.StatusReason = m.ErrorReason
+		if mm.StatusReason == "" && m.Error != nil {
+			mm.StatusReason = m.Error.Error()
+		}
+	}
+	return mm
+}
+
+func mteSpan(sp span) mteLocation {
+	return mteLocation{
+		Start: mtePosition{Line: sp.StartLine, Column: sp.StartColumn},
+		End:   mtePosition{Line: sp.EndLine, Column: sp.EndColumn},
+	}
+}
+
+// topLevelTest reduces a KilledBy value such as "TestFoo/case [suite]" to the
+// test function name.
+func topLevelTest(killedBy string) string {
+	name, _, _ := strings.Cut(killedBy, " ")
+	name, _, _ = strings.Cut(name, "/")
+	return name
+}
+
+// collectTests parses the _test.go files in dir, records them in testFiles
+// unless it is nil and returns their tests by name.
+func collectTests(dir string, rel func(string) string, testFiles map[string]mteTestFile) packageTests {
+	paths, _ := filepath.Glob(filepath.Join(dir, "*_test.go"))
+	pkgTests := make(packageTests)
+	fset := token.NewFileSet()
+	for _, path := range paths {
+		source, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		f, err := parser.ParseFile(fset, path, source, parser.SkipObjectResolution)
+		if err != nil {
+			continue
+		}
+		relPath := rel(path)
+		tf := mteTestFile{Source: string(source)}
+		for _, d := range f.Decls {
+			fn, ok := d.(*ast.FuncDecl)
+			if !ok || fn.Recv != nil || !isTestFunc(fn.Name.Name) {
+				continue
+			}
+			id := fmt.Sprintf("%s#%s", relPath, fn.Name.Name)
+			start, end := fset.Position(fn.Pos()), fset.Position(fn.End())
+			tf.Tests = append(tf.Tests, mteTest{
+				ID:   id,
+				Name: fn.Name.Name,
+				Location: &mteLocation{
+					Start: mtePosition{Line: start.Line, Column: start.Column},
+					End:   mtePosition{Line: end.Line, Column: end.Column},
+				},
+			})
+			pkgTests[fn.Name.Name] = id
+		}
+		if testFiles != nil && len(tf.Tests) > 0 {
+			testFiles[relPath] = tf
+		}
+	}
+	return pkgTests
+}
+
+func isTestFunc(name string) bool {
+	for _, prefix := range []string{"Test", "Fuzz", "Example"} {
+		if rest, ok := strings.CutPrefix(name, prefix); ok {
+			// TestMain is the test harness, not a test.
+			if name == "TestMain" {
+				return false
+			}
+			return rest == "" || !isLowerASCII(rest[0])
+		}
+	}
+	return false
+}
+
+func isLowerASCII(b byte) bool {
+	return b >= 'a' && b <= 'z'
+}
+
+// groupMutantsByPath groups mutants by file, keeping their order.
+func groupMutantsByPath(mutants []testing.Mutant) map[string][]testing.Mutant {
+	byFile := make(map[string][]testing.Mutant)
+	for _, m := range mutants {
+		if m.Site.File == nil {
+			continue
+		}
+		byFile[m.Site.File.Name()] = append(byFile[m.Site.File.Name()], m)
+	}
+	return byFile
+}
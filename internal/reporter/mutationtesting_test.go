@@ -0,0 +1,93 @@
+//go:build unit
+// +build unit
+
+package reporter
+
+import (
+	"encoding/json"
+	"go/token"
+	"os"
+	"path/filepath"
+	"testing"
+
+	core "github.com/aclfe/gorgon/internal/core"
+	"github.com/aclfe/gorgon/internal/engine"
+	"github.com/aclfe/gorgon/pkg/mutator/operators/arithmetic_flip"
+)
+
+func TestMutationTestingReport(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "calc.go")
+	if err := os.WriteFile(src, []byte("package calc\n\nfunc Add(a, b int) int { return a + b }\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "calc_test.go"), []byte("package calc\n\nimport \"testing\"\n\nfunc TestAdd(t *testing.T) {}\n\nfunc TestMain(m *testing.M) {}\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	file := token.NewFileSet().AddFile(src, -1, 100)
+	mutant := func(id int, status, killedBy string) core.Mutant {
+		return core.Mutant{
+			ID:       id,
+			Status:   status,
+			KilledBy: killedBy,
+			Mutated:  "a - b",
+			Operator: arithmetic_flip.ArithmeticFlip{},
+			Site:     engine.Site{File: file, Line: 3, Column: 33},
+		}
+	}
+	out := filepath.Join(dir, "report.json")
+	err := writeMutationTestingReport([]core.Mutant{
+		mutant(1, core.StatusKilled, "TestAdd/small"),
+		mutant(2, core.StatusSurvived, ""),
+		mutant(3, core.StatusUntested, ""),
+		mutant(4, core.StatusError, "(compiler)"),
+		mutant(5, core.StatusInvalid, ""),
+		{ID: 6, Status: core.StatusSurvived, Operator: arithmetic_flip.ArithmeticFlip{},
+			Site: engine.Site{File: token.NewFileSet().AddFile(filepath.Join(dir, "gone.go"), -1, 100), Line: 1}},
+	}, 0, dir, out)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var report mteReport
+	if err := json.Unmarshal(data, &report); err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Files) != 1 || len(report.TestFiles) != 1 {
+		t.Fatalf("expected one source and one test file, got %d and %d", len(report.Files), len(report.TestFiles))
+	}
+	if _, ok := report.Files["calc.go"]; !ok || report.ProjectRoot != dir {
+		t.Fatalf("expected paths relative to %s, got root %s and files %v", dir, report.ProjectRoot, report.Files)
+	}
+	var mutants []mteMutant
+	for _, f := range report.Files {
+		mutants = f.Mutants
+	}
+	var testID string
+	for _, tf := range report.TestFiles {
+		if len(tf.Tests) != 1 || tf.Tests[0].Name != "TestAdd" {
+			t.Fatalf("expected only TestAdd, got %+v", tf.Tests)
+		}
+		testID = tf.Tests[0].ID
+	}
+
+	want := []string{"Killed", "Survived", "NoCoverage", "CompileError", "CompileError"}
+	for i, m := range mutants {
+		if m.Status != want[i] {
+			t.Errorf("mutant %s: status %s, want %s", m.ID, m.Status, want[i])
+		}
+	}
+	if len(mutants[0].KilledBy) != 1 || mutants[0].KilledBy[0] != testID {
+		t.Errorf("expected killedBy to reference %s, got %v", testID, mutants[0].KilledBy)
+	}
+	if len(mutants[1].CoveredBy) != 1 || len(mutants[2].CoveredBy) != 0 {
+		t.Errorf("unexpected coveredBy: %v, %v", mutants[1].CoveredBy, mutants[2].CoveredBy)
+	}
+	if mutants[1].Replacement != "a - b" {
+		t.Errorf("unexpected replacement %q", mutants[1].Replacement)
+	}
+}
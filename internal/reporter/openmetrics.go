@@ -0,0 +1,224 @@
+package reporter
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	testing "github.com/aclfe/gorgon/internal/core"
+	"github.com/aclfe/gorgon/internal/subconfig"
+)
+
+// Compile error causes, by the stage that rejected the mutant.
+const (
+	causePreflight = "preflight" // type-checked out before any build
+	causeVerify    = "verify"    // removed while verifying the schemata build
+	causeBuild     = "build"     // failed its package's go test -c
+)
+
+// writeOpenMetrics writes the run as OpenMetrics text, for the Prometheus
+// node_exporter textfile collector. Every metric is a gauge: the file is a
+// snapshot of one run and counts may go down from one run to the next.
+func writeOpenMetrics(in reportInput, outputFile string) error {
+	if dir := filepath.Dir(outputFile); dir != "." {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return fmt.Errorf("failed to create output directory: %w", err)
+		}
+	}
+	f, err := os.Create(outputFile)
+	if err != nil {
+		return fmt.Errorf("failed to create output file: %w", err)
+	}
+	defer f.Close()
+
+	w := bufio.NewWriter(f)
+	writeMetrics(w, in)
+	return w.Flush()
+}
+
+// metricsWriter writes metric families, each announced once by its first
+// sample.
+type metricsWriter struct {
+	w       io.Writer
+	written map[string]bool
+}
+
+// label is a metric label; labels with an empty value are left out.
+type label struct{ name, value string }
+
+func (m *metricsWriter) sample(name, typ, help string, value float64, labels ...label) {
+	if !m.written[name] {
+		m.written[name] = true
+		fmt.Fprintf(m.w, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, typ)
+		if strings.HasSuffix(name, "_seconds") {
+			fmt.Fprintf(m.w, "# UNIT %s seconds\n", name)
+		}
+	}
+	var b strings.Builder
+	b.WriteString(name)
+	sep := "{"
+	for _, l := range labels {
+		if l.value == "" {
+			continue
+		}
+		b.WriteString(sep + l.name + `="` + escapeLabel(l.value) + `"`)
+		sep = ","
+	}
+	if sep == "," {
+		b.WriteString("}")
+	}
+	fmt.Fprintf(m.w, "%s %s\n", b.String(), strconv.FormatFloat(value, 'f', -1, 64))
+}
+
+func escapeLabel(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s)
+}
+
+func writeMetrics(w io.Writer, in reportInput) {
+	m := &metricsWriter{w: w, written: make(map[string]bool)}
+	module := label{"module", metricsModule(in.root)}
+
+	m.sample("gorgon_mutation_score", "gauge", "Mutation score of the run, in percent.", in.stats.Score, module)
+	for _, s := range statusCounts(in.stats) {
+		m.sample("gorgon_mutants", "gauge", "Mutants of the run by status.", float64(s.count), module, label{"status", s.status})
+	}
+
+	// The samples of a family must be contiguous, hence a pass per family.
+	subconfigs := make([]label, len(in.breakdown.Packages))
+	for i, p := range in.breakdown.Packages {
+		subconfigs[i] = label{"subconfig", packageConfigDir(p, in.resolver)}
+		if p.Scored() {
+			m.sample("gorgon_package_mutation_score", "gauge", "Mutation score of a package, in percent.", p.Stats.Score, module, label{"package", p.Name}, subconfigs[i])
+		}
+	}
+	for i, p := range in.breakdown.Packages {
+		for _, s := range statusCounts(p.Stats) {
+			m.sample("gorgon_package_mutants", "gauge", "Mutants of a package by status.", float64(s.count), module, label{"package", p.Name}, subconfigs[i], label{"status", s.status})
+		}
+	}
+
+	for _, op := range in.breakdown.Operators {
+		name := label{"operator", op.Operator}
+		for _, s := range []struct {
+			status string
+			count  int
+		}{
+			{"killed", op.Killed},
+			{"survived", op.Survived},
+			{"timeout", op.Timeouts},
+			{"untested", op.Untested},
+			{"compile_error", op.CompileErrors},
+			{"runtime_error", op.RuntimeErrors},
+			{"invalid", op.PreflightInvalid},
+		} {
+			m.sample("gorgon_operator_mutants", "gauge", "Mutants of an operator by status.", float64(s.count), module, name, label{"status", s.status})
+		}
+	}
+
+	causes := map[string]int{causePreflight: 0, causeVerify: 0, causeBuild: 0}
+	var executed, cached, lookedUp int
+	for _, mu := range in.mutants {
+		if cause := compileErrorCause(mu); cause != "" {
+			causes[cause]++
+		}
+		if !mu.Preflight {
+			lookedUp++
+		}
+		if mu.Cached {
+			cached++
+		}
+		if executedThisRun(mu) {
+			executed++
+		}
+	}
+	for _, cause := range []string{causePreflight, causeVerify, causeBuild} {
+		m.sample("gorgon_compile_errors", "gauge", "Mutants that failed to compile, by the stage that rejected them.", float64(causes[cause]), module, label{"cause", cause})
+	}
+
+	if in.wallTime > 0 {
+		m.sample("gorgon_run_duration_seconds", "gauge", "Wall-clock duration of the run.", in.wallTime.Seconds(), module)
+		m.sample("gorgon_mutants_per_second", "gauge", "Mutants whose tests ran this run, per second of the run.", float64(executed)/in.wallTime.Seconds(), module)
+	}
+	if in.cacheEnabled && lookedUp > 0 {
+		m.sample("gorgon_cache_hit_ratio", "gauge", "Share of the mutants looked up in the result cache that were found, from 0 to 1.", float64(cached)/float64(lookedUp), module)
+	}
+	m.sample("gorgon_last_run_timestamp_seconds", "gauge", "When the run finished, in seconds since the Unix epoch.", float64(time.Now().Unix()), module)
+	fmt.Fprintln(w, "# EOF")
+}
+
+type statusCount struct {
+	status string
+	count  int
+}
+
+func statusCounts(s ReportStats) []statusCount {
+	return []statusCount{
+		{"killed", s.Killed},
+		{"survived", s.Survived},
+		{"timeout", s.Timeout},
+		{"untested", s.Untested},
+		{"compile_error", s.CompileErrors},
+		{"runtime_error", s.RuntimeErrors},
+		{"invalid", s.Invalid},
+	}
+}
+
+// compileErrorCause names the stage that rejected m for not compiling, ""
+// if it compiled. Mutants restored from the cache keep the output their
+// stage recorded, so it is read from there.
+func compileErrorCause(m testing.Mutant) string {
+	if m.Status != testing.StatusError || m.KilledBy != "(compiler)" {
+		return ""
+	}
+	switch {
+	case m.Preflight:
+		return causePreflight
+	case strings.HasPrefix(m.KillOutput, "build verification"), strings.HasPrefix(m.KillOutput, "bisect:"):
+		return causeVerify
+	default:
+		return causeBuild
+	}
+}
+
+// executedThisRun reports whether m's tests ran in this run.
+func executedThisRun(m testing.Mutant) bool {
+	if m.Cached || m.Preflight {
+		return false
+	}
+	switch m.Status {
+	case testing.StatusKilled, testing.StatusSurvived, testing.StatusTimeout:
+		return true
+	case testing.StatusError:
+		return m.KilledBy != "(compiler)"
+	}
+	return false
+}
+
+// metricsModule labels the run with its module path, or the name of the
+// project directory outside a module.
+func metricsModule(root string) string {
+	if root == "" {
+		root = "."
+	}
+	if path := testing.ModulePath(root); path != "" {
+		return path
+	}
+	if abs, err := filepath.Abs(root); err == nil {
+		return filepath.Base(abs)
+	}
+	return ""
+}
+
+// packageConfigDir is the directory of the sub-config that applies to p,
+// "" when only the root config does.
+func packageConfigDir(p PackageSummary, resolver *subconfig.Resolver) string {
+	if resolver == nil || len(p.Files) == 0 {
+		return ""
+	}
+	return resolver.ConfigDir(p.Files[0].Path)
+}
@@ -0,0 +1,102 @@
+//go:build unit
+// +build unit
+
+package reporter
+
+import (
+	"bytes"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	core "github.com/aclfe/gorgon/internal/core"
+	"github.com/aclfe/gorgon/internal/engine"
+	"github.com/aclfe/gorgon/pkg/mutator/operators/arithmetic_flip"
+)
+
+func TestWriteMetrics(t *testing.T) {
+	root := t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, "go.mod"), []byte("module example.com/calc\n\ngo 1.22\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	file := token.NewFileSet().AddFile(filepath.Join(root, "calc", "calc.go"), -1, 100)
+	mutant := func(id int, status string) core.Mutant {
+		return core.Mutant{ID: id, Status: status, Operator: arithmetic_flip.ArithmeticFlip{}, Site: engine.Site{File: file, Line: id}}
+	}
+	survivor := mutant(1, core.StatusSurvived)
+	killed := mutant(2, core.StatusKilled)
+	cached := mutant(3, core.StatusKilled)
+	cached.Cached = true
+	preflight := mutant(4, core.StatusError)
+	preflight.KilledBy, preflight.Preflight = "(compiler)", true
+	verify := mutant(5, core.StatusError)
+	verify.KilledBy, verify.KillOutput = "(compiler)", "build verification: removed in round 1"
+	mutants := []core.Mutant{survivor, killed, cached, preflight, verify}
+
+	stats := computeStats(mutants, len(mutants))
+	in := reportInput{
+		mutants:      mutants,
+		stats:        stats,
+		root:         root,
+		breakdown:    Aggregate(mutants, root),
+		wallTime:     2 * time.Second,
+		cacheEnabled: true,
+	}
+	var buf bytes.Buffer
+	writeMetrics(&buf, in)
+	out := buf.String()
+
+	for _, want := range []string{
+		"# TYPE gorgon_mutation_score gauge\n",
+		`gorgon_mutation_score{module="example.com/calc"} 66.66666666666666` + "\n",
+		`gorgon_mutants{module="example.com/calc",status="killed"} 2` + "\n",
+		`gorgon_mutants{module="example.com/calc",status="compile_error"} 2` + "\n",
+		`gorgon_package_mutants{module="example.com/calc",package="calc",status="survived"} 1` + "\n",
+		`gorgon_operator_mutants{module="example.com/calc",operator="arithmetic_flip",status="invalid"} 1` + "\n",
+		`gorgon_compile_errors{module="example.com/calc",cause="preflight"} 1` + "\n",
+		`gorgon_compile_errors{module="example.com/calc",cause="verify"} 1` + "\n",
+		`gorgon_compile_errors{module="example.com/calc",cause="build"} 0` + "\n",
+		"# UNIT gorgon_run_duration_seconds seconds\n",
+		`gorgon_run_duration_seconds{module="example.com/calc"} 2` + "\n",
+		// The survivor and the killed mutant ran; the rest did not.
+		`gorgon_mutants_per_second{module="example.com/calc"} 1` + "\n",
+		// Four mutants got past preflight and one came from the cache.
+		`gorgon_cache_hit_ratio{module="example.com/calc"} 0.25` + "\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("metrics lack %q:\n%s", want, out)
+		}
+	}
+	if !strings.HasSuffix(out, "# EOF\n") {
+		t.Error("metrics do not end with # EOF")
+	}
+
+	// Each family is announced once, before all of its samples.
+	seen := map[string]bool{}
+	current := ""
+	for _, line := range strings.Split(strings.TrimSuffix(out, "\n"), "\n") {
+		if strings.HasPrefix(line, "# TYPE ") {
+			current = strings.Fields(line)[2]
+			if seen[current] {
+				t.Errorf("family %s announced twice", current)
+			}
+			seen[current] = true
+			continue
+		}
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+		if name := strings.FieldsFunc(line, func(r rune) bool { return r == '{' || r == ' ' })[0]; name != current {
+			t.Errorf("sample %q outside its family %s", line, current)
+		}
+	}
+}
+
+func TestEscapeLabel(t *testing.T) {
+	if got := escapeLabel("a\\b\"c\nd"); got != `a\\b\"c\nd` {
+		t.Errorf("escapeLabel = %q", got)
+	}
+}
@@ -0,0 +1,86 @@
+package reporter
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/aclfe/gorgon/internal/baseline"
+	testing "github.com/aclfe/gorgon/internal/core"
+	"github.com/aclfe/gorgon/internal/diff"
+	"github.com/aclfe/gorgon/internal/subconfig"
+)
+
+// reportInput is everything an output format may draw on.
+type reportInput struct {
+	mutants      []testing.Mutant
+	stats        ReportStats
+	threshold    float64
+	resolver     *subconfig.Resolver
+	debug        bool
+	showKilled   bool
+	showSurvived bool
+	prevBaseline *baseline.Data // baseline before this run, nil if none
+	changed      diff.FileLines // lines selected by diff:, nil if unset
+	root         string         // project root, "" for the working directory
+	sarif        SARIFOptions
+	history      []HistoryRecord // recorded runs, oldest first
+	breakdown    Breakdown
+	wallTime     time.Duration // duration of the whole run, 0 if unknown
+	cacheEnabled bool
+}
+
+// outputFormat names an `outputs:` format and writes it to a file.
+type outputFormat struct {
+	name  string // used in error messages
+	write func(in reportInput, file string) error
+}
+
+var outputFormats = map[string]outputFormat{
+	"textfile": {"text", func(in reportInput, file string) error {
+		return writeTextReport(in.mutants, in.stats, in.breakdown, in.debug, in.showKilled, in.showSurvived, file)
+	}},
+	"html": {"HTML", func(in reportInput, file string) error {
+		return writeHTMLReport(in.mutants, in.stats, in.breakdown, in.threshold, in.resolver, in.history, file)
+	}},
+	"junit": {"JUnit", func(in reportInput, file string) error {
+		return writeJUnitReport(in.mutants, in.stats, in.breakdown, file)
+	}},
+	"sarif": {"SARIF", func(in reportInput, file string) error {
+		return writeSARIFReport(in.mutants, in.stats, in.root, in.sarif, file)
+	}},
+	"json": {"JSON", func(in reportInput, file string) error {
+		return writeJSONReport(in.mutants, in.stats, in.breakdown, in.threshold, in.root, file)
+	}},
+	"mutation-testing-elements": {"mutation-testing-elements", func(in reportInput, file string) error {
+		return writeMutationTestingReport(in.mutants, in.threshold, in.root, file)
+	}},
+	"markdown": {"markdown", func(in reportInput, file string) error {
+		return writeMarkdownReport(in.mutants, in.stats, in.breakdown, in.threshold, in.root, in.prevBaseline, in.changed, file)
+	}},
+	"github-annotations": {"GitHub annotations", func(in reportInput, file string) error {
+		return writeGitHubAnnotations(in.mutants, in.root, file)
+	}},
+	"gitlab-codequality": {"GitLab Code Quality", func(in reportInput, file string) error {
+		return writeGitLabCodeQuality(in.mutants, in.root, file)
+	}},
+	"sonarqube": {"SonarQube", func(in reportInput, file string) error {
+		return writeSonarQubeIssues(in.mutants, in.root, file)
+	}},
+	"openmetrics": {"OpenMetrics", writeOpenMetrics},
+}
+
+// writeFormat writes the report for format to file. Unknown formats are
+// ignored.
+func writeFormat(in reportInput, format, file string) error {
+	f, ok := outputFormats[format]
+	if !ok {
+		return nil
+	}
+	if err := f.write(in, file); err != nil {
+		if file == "" {
+			return fmt.Errorf("failed to write %s report: %w", f.name, err)
+		}
+		return fmt.Errorf("failed to write %s report to %s: %w", f.name, file, err)
+	}
+	return nil
+}
@@ -10,6 +10,7 @@ import (
 
 	"github.com/aclfe/gorgon/internal/baseline"
 	testing "github.com/aclfe/gorgon/internal/core"
+	"github.com/aclfe/gorgon/internal/diff"
 	"github.com/aclfe/gorgon/internal/subconfig"
 )
 
@@ -19,7 +20,14 @@ type BaselineOptions struct {
 	Tolerance    float64
 	Dir          string
 	File         string
-	MultiOutputs []string // format:file pairs from config
+	Mode         string         // baseline.ModeScore (default) or baseline.ModeMutants
+	MultiOutputs []string       // format:file pairs from config
+	ChangedLines diff.FileLines // lines selected by diff:, for the markdown "new code" section
+	ProjectRoot  string         // module root; reports name files relative to it
+	SARIF        SARIFOptions
+	History      HistoryOptions
+	LiveReport   func(page []byte) // receives the HTML report page for the live dashboard
+	Cache        bool              // whether the result cache was on, for the openmetrics cache hit ratio
 }
 
 // ReportStats holds all categorized mutant counts and the final score.
@@ -245,9 +253,48 @@ func computeStats(mutants []testing.Mutant, totalMutants int) ReportStats {
 	return s
 }
 
+// baselineSurvivors lists the surviving mutants for a mutants baseline, with
+// paths relative to dir, along with the set of files the run mutated.
+func baselineSurvivors(mutants []testing.Mutant, dir string) ([]baseline.Survivor, map[string]bool) {
+	var survivors []baseline.Survivor
+	covered := make(map[string]bool)
+	if abs, err := filepath.Abs(dir); err == nil {
+		dir = abs
+	}
+	for _, m := range mutants {
+		if m.Site.File == nil {
+			continue
+		}
+		file := m.Site.File.Name()
+		if rel, err := filepath.Rel(dir, file); err == nil {
+			file = rel
+		}
+		file = filepath.ToSlash(file)
+		covered[file] = true
+		if m.Status != testing.StatusSurvived {
+			continue
+		}
+		survivors = append(survivors, baseline.Survivor{
+			Fingerprint: m.Fingerprint,
+			File:        file,
+			Line:        m.Site.Line,
+			Column:      m.Site.Column,
+			Operator:    m.Operator.Name(),
+		})
+	}
+	return survivors, covered
+}
+
 func Report(mutants []testing.Mutant, totalMutants int, threshold float64, resolver *subconfig.Resolver, debug bool, showKilled bool, showSurvived bool, outputFile string, debugFile string, format string, blOpts BaselineOptions) (ReportStats, error) {
 	stats := computeStats(mutants, totalMutants)
 
+	// The markdown report compares against the baseline as it was before
+	// this run, which may overwrite it below.
+	prevBaseline, err := baseline.Load(blOpts.Dir, blOpts.File)
+	if err != nil {
+		prevBaseline = nil
+	}
+
 	// Baseline / ratchet handling - do this BEFORE threshold checks
 	if blOpts.Save || blOpts.NoRegression {
 		current := &baseline.Data{
@@ -257,16 +304,26 @@ func Report(mutants []testing.Mutant, totalMutants int, threshold float64, resol
 			Untested: stats.Untested,
 			Total:    totalMutants,
 		}
+		blPath := blOpts.File
+		if blPath == "" {
+			blPath = baseline.DefaultFile
+		}
+		mutantsMode := blOpts.Mode == baseline.ModeMutants
+		var covered map[string]bool
+		if mutantsMode {
+			current.Mode = baseline.ModeMutants
+			current.Survivors, covered = baselineSurvivors(mutants, blOpts.Dir)
+		}
 
 		if blOpts.Save {
 			if err := baseline.Save(blOpts.Dir, blOpts.File, current); err != nil {
 				return stats, fmt.Errorf("failed to save baseline: %w", err)
 			}
-			path := blOpts.File
-			if path == "" {
-				path = baseline.DefaultFile
+			if mutantsMode {
+				fmt.Fprintf(os.Stdout, "\nBaseline saved: %d surviving mutant(s) → %s\n", len(current.Survivors), blPath)
+			} else {
+				fmt.Fprintf(os.Stdout, "\nBaseline saved: %.2f%% → %s\n", stats.Score, blPath)
 			}
-			fmt.Fprintf(os.Stdout, "\nBaseline saved: %.2f%% → %s\n", stats.Score, path)
 		}
 
 		if blOpts.NoRegression {
@@ -276,14 +333,33 @@ func Report(mutants []testing.Mutant, totalMutants int, threshold float64, resol
 					if saveErr := baseline.Save(blOpts.Dir, blOpts.File, current); saveErr != nil {
 						return stats, fmt.Errorf("failed to auto-save baseline: %w", saveErr)
 					}
-					path := blOpts.File
-					if path == "" {
-						path = baseline.DefaultFile
+					if mutantsMode {
+						fmt.Fprintf(os.Stdout, "\nNo baseline found — saved %d current surviving mutant(s) as baseline: %s\n", len(current.Survivors), blPath)
+					} else {
+						fmt.Fprintf(os.Stdout, "\nNo baseline found — saved current score %.2f%% as baseline: %s\n", stats.Score, blPath)
 					}
-					fmt.Fprintf(os.Stdout, "\nNo baseline found — saved current score %.2f%% as baseline: %s\n", stats.Score, path)
 				} else {
 					return stats, fmt.Errorf("failed to load baseline: %w", err)
 				}
+			} else if mutantsMode {
+				if saved.Mode != baseline.ModeMutants {
+					return stats, fmt.Errorf("baseline %s records only a score; save it once with baseline.save: true to record survivors", blPath)
+				}
+				diff := baseline.DiffSurvivors(current, saved, covered)
+				if len(diff.Fixed) > 0 {
+					fmt.Fprintf(os.Stdout, "\n%d baseline survivor(s) no longer survive; set baseline.save: true to drop them:\n", len(diff.Fixed))
+					for _, s := range diff.Fixed {
+						fmt.Fprintf(os.Stdout, "  %s\n", s)
+					}
+				}
+				if err := baseline.CheckSurvivors(diff); err != nil {
+					fmt.Fprintln(os.Stdout, "\nSurviving mutants not in baseline:")
+					for _, s := range diff.New {
+						fmt.Fprintf(os.Stdout, "  %s\n", s)
+					}
+					return stats, err
+				}
+				fmt.Fprintf(os.Stdout, "\nBaseline check passed: no new surviving mutants (%d in baseline)\n", len(saved.Survivors))
 			} else {
 				if err := baseline.CheckRegression(current, saved, blOpts.Tolerance); err != nil {
 					return stats, err
@@ -301,36 +377,49 @@ func Report(mutants []testing.Mutant, totalMutants int, threshold float64, resol
 		}
 	}
 
+	breakdown := Aggregate(mutants, blOpts.ProjectRoot)
+
+	historyFile := historyPath(blOpts.Dir, blOpts.History.File)
+	if blOpts.History.Record {
+		rec := newHistoryRecord(stats, breakdown, blOpts.ProjectRoot, blOpts.History.WallTime)
+		if err := appendHistory(historyFile, rec); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to record run history: %v\n", err)
+		}
+	}
+	history, err := LoadHistory(historyFile)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+
+	in := reportInput{
+		mutants:      mutants,
+		stats:        stats,
+		threshold:    threshold,
+		resolver:     resolver,
+		debug:        debug,
+		showKilled:   showKilled,
+		showSurvived: showSurvived,
+		prevBaseline: prevBaseline,
+		changed:      blOpts.ChangedLines,
+		root:         blOpts.ProjectRoot,
+		sarif:        blOpts.SARIF,
+		history:      history,
+		breakdown:    breakdown,
+		wallTime:     blOpts.History.WallTime,
+		cacheEnabled: blOpts.Cache,
+	}
+
 	// Write format-specific reports
 	if outputFile != "" || format == "textfile" {
-		switch format {
-		case "textfile":
-			if err := writeTextReport(mutants, stats, debug, showKilled, showSurvived, outputFile); err != nil {
-				return stats, fmt.Errorf("failed to write text report: %w", err)
-			}
-		case "html":
-			if err := writeHTMLReport(mutants, stats, threshold, resolver, outputFile); err != nil {
-				return stats, fmt.Errorf("failed to write HTML report: %w", err)
-			}
-		case "junit":
-			if err := writeJUnitReport(mutants, stats, outputFile); err != nil {
-				return stats, fmt.Errorf("failed to write JUnit report: %w", err)
-			}
-		case "sarif":
-			if err := writeSARIFReport(mutants, stats, outputFile); err != nil {
-				return stats, fmt.Errorf("failed to write SARIF report: %w", err)
-			}
-		case "json":
-			if err := writeJSONReport(mutants, stats, outputFile); err != nil {
-				return stats, fmt.Errorf("failed to write JSON report: %w", err)
-			}
+		if err := writeFormat(in, format, outputFile); err != nil {
+			return stats, err
 		}
 	}
 
 	// Always write text report to terminal exactly once.
 	// If the legacy path already wrote to stdout (because outputFile was ""), skip it.
 	if outputFile != "" || format != "textfile" {
-		if err := writeTextReport(mutants, stats, debug, showKilled, showSurvived, ""); err != nil {
+		if err := writeTextReport(mutants, stats, in.breakdown, debug, showKilled, showSurvived, ""); err != nil {
 			return stats, fmt.Errorf("failed to write text report to terminal: %w", err)
 		}
 	}
@@ -353,37 +442,27 @@ func Report(mutants []testing.Mutant, totalMutants int, threshold float64, resol
 				continue
 			}
 
-			switch fmtType {
-			case "textfile":
-				if err := writeTextReport(mutants, stats, debug, showKilled, showSurvived, file); err != nil {
-					fmt.Fprintf(os.Stderr, "Warning: failed to write text report to %s: %v\n", file, err)
-				}
-			case "html":
-				if err := writeHTMLReport(mutants, stats, threshold, resolver, file); err != nil {
-					fmt.Fprintf(os.Stderr, "Warning: failed to write HTML report to %s: %v\n", file, err)
-				}
-			case "junit":
-				if err := writeJUnitReport(mutants, stats, file); err != nil {
-					fmt.Fprintf(os.Stderr, "Warning: failed to write JUnit report to %s: %v\n", file, err)
-				}
-			case "sarif":
-				if err := writeSARIFReport(mutants, stats, file); err != nil {
-					fmt.Fprintf(os.Stderr, "Warning: failed to write SARIF report to %s: %v\n", file, err)
-				}
-			case "json":
-				if err := writeJSONReport(mutants, stats, file); err != nil {
-					fmt.Fprintf(os.Stderr, "Warning: failed to write JSON report to %s: %v\n", file, err)
-				}
+			if err := writeFormat(in, fmtType, file); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
 			}
 		}
 	}
 
+	if blOpts.LiveReport != nil {
+		page, err := renderHTMLPage(in)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to render the live dashboard report: %v\n", err)
+		} else {
+			blOpts.LiveReport(page)
+		}
+	}
+
 	// Centralized threshold check — applies regardless of output format
 	if threshold > 0 {
 		denom := stats.Killed + stats.Survived + stats.Untested + stats.Timeout
 		if denom > 0 && stats.Score < threshold {
 			if resolver != nil && resolver.HasAnyOverrides() {
-				if err := checkPerPackageThresholds(mutants, threshold, resolver, os.Stdout); err != nil {
+				if err := checkPerPackageThresholds(in.breakdown, threshold, resolver, os.Stdout); err != nil {
 					return stats, err
 				}
 			} else {
@@ -395,48 +474,20 @@ func Report(mutants []testing.Mutant, totalMutants int, threshold float64, resol
 	return stats, nil
 }
 
-func checkPerPackageThresholds(mutants []testing.Mutant, rootThreshold float64, resolver *subconfig.Resolver, out io.Writer) error {
-	type pkgStats struct {
-		killed, survived, untested, timeout int
-		sampleFile                          string
-	}
-	pkgs := make(map[string]*pkgStats)
-	for _, m := range mutants {
-		if m.Site.File == nil {
-			continue
-		}
-		dir := filepath.Dir(m.Site.File.Name())
-		if pkgs[dir] == nil {
-			pkgs[dir] = &pkgStats{sampleFile: m.Site.File.Name()}
-		}
-		switch m.Status {
-		case testing.StatusKilled:
-			pkgs[dir].killed++
-		case testing.StatusSurvived:
-			pkgs[dir].survived++
-		case testing.StatusUntested:
-			pkgs[dir].untested++
-		case testing.StatusTimeout:
-			pkgs[dir].timeout++
-		}
-	}
-
+func checkPerPackageThresholds(bd Breakdown, rootThreshold float64, resolver *subconfig.Resolver, out io.Writer) error {
 	var failures []string
-	for dir, stats := range pkgs {
-		denom := stats.killed + stats.survived + stats.untested + stats.timeout
-		if denom == 0 {
+	for _, pkg := range bd.Packages {
+		if !pkg.Scored() {
 			continue
 		}
-		score := float64(stats.killed) / float64(denom) * percentageMultiplier
-		threshold := resolver.EffectiveThreshold(stats.sampleFile, rootThreshold)
-		if threshold > 0 && score < threshold {
+		threshold := resolver.EffectiveThreshold(pkg.Files[0].Path, rootThreshold)
+		if threshold > 0 && pkg.Stats.Score < threshold {
 			failures = append(failures,
-				fmt.Sprintf(" %s: %.2f%% (threshold %.2f%%)", dir, score, threshold))
+				fmt.Sprintf(" %s: %.2f%% (threshold %.2f%%)", pkg.Name, pkg.Stats.Score, threshold))
 		}
 	}
 
 	if len(failures) > 0 {
-		sort.Strings(failures)
 		fmt.Fprintln(out, "\nPackages below threshold:")
 		for _, f := range failures {
 			fmt.Fprintln(out, f)
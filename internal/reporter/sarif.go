@@ -3,20 +3,114 @@ package reporter
 import (
 	"encoding/json"
 	"fmt"
+	"net/url"
 	"os"
+	"path/filepath"
+	"strings"
 
+	operatordocs "github.com/aclfe/gorgon/docs/documentation/operators"
 	testing "github.com/aclfe/gorgon/internal/core"
+	"github.com/aclfe/gorgon/pkg/mutator"
 )
 
+const (
+	sarifVersion = "2.1.0"
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+
+	// sarifRootBaseID names the project root in originalUriBaseIds; result
+	// URIs are relative to it.
+	sarifRootBaseID = "PROJECTROOT"
+
+	// sarifFingerprintKey versions the fingerprint scheme for SARIF consumers.
+	sarifFingerprintKey = "gorgonMutant/v1"
+
+	// sarifSurvivorLevel is the result level of survivors.
+	sarifSurvivorLevel = "warning"
+
+	sarifInformationURI = "https://github.com/aclfe/gorgon"
+	sarifDocsURI        = "https://github.com/aclfe/gorgon/blob/main/docs/documentation/operators/"
+)
+
+// SARIFOptions controls what the SARIF report includes beyond survivors.
+type SARIFOptions struct {
+	TimeoutLevel  string // level for timed-out mutants, "" to leave them out
+	UntestedLevel string // level for untested mutants, "" to leave them out
+}
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool               sarifTool                        `json:"tool"`
+	Invocations        []sarifInvocation                `json:"invocations"`
+	OriginalURIBaseIDs map[string]sarifArtifactLocation `json:"originalUriBaseIds"`
+	Results            []sarifResult                    `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+// sarifInvocation carries the run's counts, which have no place of their own
+// in SARIF.
+type sarifInvocation struct {
+	ExecutionSuccessful bool                      `json:"executionSuccessful"`
+	Properties          sarifInvocationProperties `json:"properties"`
+}
+
+type sarifInvocationProperties struct {
+	Stats ReportStats `json:"stats"`
+}
+
+type sarifRule struct {
+	ID                   string             `json:"id"`
+	Name                 string             `json:"name"`
+	ShortDescription     sarifMessage       `json:"shortDescription"`
+	FullDescription      sarifMessage       `json:"fullDescription"`
+	Help                 sarifMessage       `json:"help"`
+	HelpURI              string             `json:"helpUri,omitempty"`
+	DefaultConfiguration sarifConfiguration `json:"defaultConfiguration"`
+	Properties           sarifRuleProps     `json:"properties"`
+}
+
+type sarifConfiguration struct {
+	Level string `json:"level"`
+}
+
+type sarifRuleProps struct {
+	Category string   `json:"category,omitempty"`
+	Tags     []string `json:"tags"`
+}
+
 type sarifResult struct {
-	RuleID    string      `json:"ruleId"`
-	Message   sarifMessage `json:"message"`
+	RuleID    string          `json:"ruleId"`
+	RuleIndex int             `json:"ruleIndex"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
 	Locations []sarifLocation `json:"locations"`
-	Level     string      `json:"level"`
+	// Fingerprints and PartialFingerprints let code-scanning UIs track a
+	// mutant across commits even as its line moves.
+	Fingerprints        map[string]string `json:"fingerprints"`
+	PartialFingerprints map[string]string `json:"partialFingerprints"`
+	Properties          sarifResultProps  `json:"properties"`
+}
+
+type sarifResultProps struct {
+	Status string `json:"status"`
 }
 
 type sarifMessage struct {
-	Text string `json:"text"`
+	Text     string `json:"text"`
+	Markdown string `json:"markdown,omitempty"`
 }
 
 type sarifLocation struct {
@@ -29,110 +123,167 @@ type sarifPhysicalLocation struct {
 }
 
 type sarifArtifactLocation struct {
-	URI string `json:"uri"`
+	URI       string `json:"uri"`
+	URIBaseID string `json:"uriBaseId,omitempty"`
 }
 
 type sarifRegion struct {
 	StartLine   int `json:"startLine"`
 	StartColumn int `json:"startColumn"`
+	EndLine     int `json:"endLine"`
+	EndColumn   int `json:"endColumn"`
 }
 
-type sarifRule struct {
-	ID              string `json:"id"`
-	Name            string `json:"name"`
-	ShortDescription sarifText `json:"shortDescription"`
-	HelpURI         string `json:"helpUri,omitempty"`
-}
-
-type sarifText struct {
-	Text string `json:"text"`
-}
-
-type sarifRun struct {
-	Tool       sarifTool     `json:"tool"`
-	Results    []sarifResult `json:"results"`
-	Properties interface{}   `json:"properties,omitempty"`
-}
-
-type sarifTool struct {
-	Driver sarifDriver `json:"driver"`
-}
+// writeSARIFReport writes survivors, and timed-out and untested mutants when
+// opts gives them a level, as a SARIF 2.1.0 log with URIs relative to root
+// (default: the working directory).
+func writeSARIFReport(mutants []testing.Mutant, stats ReportStats, root string, opts SARIFOptions, outputFile string) error {
+	if root == "" {
+		root, _ = os.Getwd()
+	}
+	root, err := filepath.Abs(root)
+	if err != nil {
+		return fmt.Errorf("failed to resolve project root: %w", err)
+	}
 
-type sarifDriver struct {
-	Name  string      `json:"name"`
-	Rules []sarifRule `json:"rules"`
-}
+	levels := map[string]string{testing.StatusSurvived: sarifSurvivorLevel}
+	statuses := []string{testing.StatusSurvived}
+	if opts.TimeoutLevel != "" {
+		levels[testing.StatusTimeout] = opts.TimeoutLevel
+		statuses = append(statuses, testing.StatusTimeout)
+	}
+	if opts.UntestedLevel != "" {
+		levels[testing.StatusUntested] = opts.UntestedLevel
+		statuses = append(statuses, testing.StatusUntested)
+	}
 
-type sarifLog struct {
-	Version string     `json:"version"`
-	Runs    []sarifRun `json:"runs"`
-}
+	ruleIndex := make(map[string]int)
+	rules := make([]sarifRule, 0)
+	results := make([]sarifResult, 0)
+	for _, f := range findingsWithStatus(mutants, relativeTo(root), statuses...) {
+		m := f.Mutant
+		ruleID := m.Operator.Name()
+		idx, ok := ruleIndex[ruleID]
+		if !ok {
+			idx = len(rules)
+			ruleIndex[ruleID] = idx
+			rules = append(rules, sarifRuleFor(ruleID))
+		}
 
-func writeSARIFReport(mutants []testing.Mutant, stats ReportStats, outputFile string) error {
-	ruleMap := make(map[string]bool)
-	var results []sarifResult
-	var rules []sarifRule
-
-	for _, m := range mutants {
-		if m.Status == testing.StatusSurvived {
-			ruleID := m.Operator.Name()
-			
-			// Add rule if not seen
-			if !ruleMap[ruleID] {
-				ruleMap[ruleID] = true
-				rules = append(rules, sarifRule{
-					ID:   ruleID,
-					Name: ruleID,
-					ShortDescription: sarifText{
-						Text: fmt.Sprintf("Mutation operator: %s", ruleID),
-					},
-				})
-			}
-
-			// Add result for survived mutant
-			results = append(results, sarifResult{
-				RuleID: ruleID,
-				Message: sarifMessage{
-					Text: fmt.Sprintf("Mutant survived: %s at line %d", ruleID, m.Site.Line),
-				},
-				Locations: []sarifLocation{
-					{
-						PhysicalLocation: sarifPhysicalLocation{
-							ArtifactLocation: sarifArtifactLocation{
-								URI: m.Site.File.Name(),
-							},
-							Region: sarifRegion{
-								StartLine:   m.Site.Line,
-								StartColumn: m.Site.Column,
-							},
-						},
+		results = append(results, sarifResult{
+			RuleID:    ruleID,
+			RuleIndex: idx,
+			Level:     levels[m.Status],
+			Message:   sarifMutantMessage(f),
+			Locations: []sarifLocation{{
+				PhysicalLocation: sarifPhysicalLocation{
+					ArtifactLocation: sarifArtifact(f.Path),
+					Region: sarifRegion{
+						StartLine:   f.Span.StartLine,
+						StartColumn: f.Span.StartColumn,
+						EndLine:     f.Span.EndLine,
+						EndColumn:   f.Span.EndColumn,
 					},
 				},
-				Level: "warning",
-			})
-		}
+			}},
+			Fingerprints:        map[string]string{sarifFingerprintKey: m.Fingerprint},
+			PartialFingerprints: map[string]string{sarifFingerprintKey: m.Fingerprint},
+			Properties:          sarifResultProps{Status: m.Status},
+		})
 	}
 
 	log := sarifLog{
-		Version: "2.1.0",
-		Runs: []sarifRun{
-			{
-				Tool: sarifTool{
-					Driver: sarifDriver{
-						Name:  "Gorgon",
-						Rules: rules,
-					},
-				},
-				Results:    results,
-				Properties: stats,
+		Schema:  sarifSchema,
+		Version: sarifVersion,
+		Runs: []sarifRun{{
+			Tool: sarifTool{Driver: sarifDriver{
+				Name:           "Gorgon",
+				InformationURI: sarifInformationURI,
+				Rules:          rules,
+			}},
+			Invocations: []sarifInvocation{{
+				ExecutionSuccessful: true,
+				Properties:          sarifInvocationProperties{Stats: stats},
+			}},
+			OriginalURIBaseIDs: map[string]sarifArtifactLocation{
+				sarifRootBaseID: {URI: strings.TrimSuffix(fileURI(root), "/") + "/"},
 			},
-		},
+			Results: results,
+		}},
 	}
 
 	data, err := json.MarshalIndent(log, "", "  ")
 	if err != nil {
 		return err
 	}
-
 	return os.WriteFile(outputFile, data, 0644)
 }
+
+// sarifRuleFor describes an operator from its section in
+// docs/documentation/operators, falling back to its name.
+func sarifRuleFor(op string) sarifRule {
+	category := mutator.CategoryOf(op)
+	rule := sarifRule{
+		ID:                   op,
+		Name:                 ruleName(op),
+		DefaultConfiguration: sarifConfiguration{Level: sarifSurvivorLevel},
+		Properties:           sarifRuleProps{Category: category, Tags: []string{"mutation-testing"}},
+	}
+	if category != "" {
+		rule.Properties.Tags = append(rule.Properties.Tags, category)
+	}
+	doc, ok := operatordocs.Lookup(op)
+	if !ok {
+		text := fmt.Sprintf("Mutation operator: %s", op)
+		rule.ShortDescription = sarifMessage{Text: text}
+		rule.FullDescription = sarifMessage{Text: text}
+		rule.Help = sarifMessage{Text: text}
+		return rule
+	}
+	rule.ShortDescription = sarifMessage{Text: doc.Summary}
+	rule.FullDescription = sarifMessage{Text: doc.Description}
+	rule.Help = sarifMessage{Text: doc.Description, Markdown: doc.Help}
+	rule.HelpURI = sarifDocsURI + doc.Page + "#" + op
+	return rule
+}
+
+// ruleName turns an operator name into the PascalCase name SARIF recommends.
+func ruleName(op string) string {
+	var sb strings.Builder
+	for _, part := range strings.Split(op, "_") {
+		if part != "" {
+			sb.WriteString(strings.ToUpper(part[:1]) + part[1:])
+		}
+	}
+	return sb.String()
+}
+
+// sarifArtifact locates a file under the project root, or by absolute URI
+// when it lies outside it.
+func sarifArtifact(path string) sarifArtifactLocation {
+	if filepath.IsAbs(filepath.FromSlash(path)) {
+		return sarifArtifactLocation{URI: fileURI(path)}
+	}
+	return sarifArtifactLocation{URI: (&url.URL{Path: path}).EscapedPath(), URIBaseID: sarifRootBaseID}
+}
+
+// fileURI converts an absolute path to a file:// URI.
+func fileURI(path string) string {
+	p := filepath.ToSlash(path)
+	if !strings.HasPrefix(p, "/") {
+		p = "/" + p // Windows drive letter
+	}
+	return (&url.URL{Scheme: "file", Path: p}).String()
+}
+
+// sarifMutantMessage describes a mutant, with the mutated code as a diff so
+// viewers can show the change without re-running the operator.
+func sarifMutantMessage(f finding) sarifMessage {
+	msg := sarifMessage{Text: f.Message}
+	if f.Mutant.Diff == "" {
+		return msg
+	}
+	msg.Markdown = msg.Text + "\n\n```diff\n" + f.Mutant.Diff + "```"
+	msg.Text += "\n" + f.Mutant.Diff
+	return msg
+}
@@ -0,0 +1,95 @@
+//go:build unit
+// +build unit
+
+package reporter
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	core "github.com/aclfe/gorgon/internal/core"
+)
+
+func TestSARIFReport(t *testing.T) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	mutants := ciMutants(wd)
+	timedOut := mutants[0]
+	timedOut.ID, timedOut.Status, timedOut.Fingerprint = 3, core.StatusTimeout, "0000000000000003"
+	mutants = append(mutants, timedOut)
+
+	out := filepath.Join(t.TempDir(), "report.sarif")
+	if err := writeSARIFReport(mutants, ReportStats{Survived: 1, Killed: 1, Timeout: 1, Total: 3}, wd, SARIFOptions{}, out); err != nil {
+		t.Fatal(err)
+	}
+	var log sarifLog
+	readJSON(t, out, &log)
+	if log.Version != "2.1.0" || log.Schema == "" || len(log.Runs) != 1 {
+		t.Fatalf("unexpected log header: %+v", log)
+	}
+	run := log.Runs[0]
+	if base := run.OriginalURIBaseIDs[sarifRootBaseID].URI; base != fileURI(wd)+"/" {
+		t.Errorf("project root base = %q, want %q", base, fileURI(wd)+"/")
+	}
+	if len(run.Invocations) != 1 || run.Invocations[0].Properties.Stats.Total != 3 {
+		t.Errorf("stats not carried by the invocation: %+v", run.Invocations)
+	}
+
+	if len(run.Results) != 1 {
+		t.Fatalf("expected only the survivor by default, got %d results", len(run.Results))
+	}
+	res := run.Results[0]
+	loc := res.Locations[0].PhysicalLocation
+	if loc.ArtifactLocation.URI != "calc/calc.go" || loc.ArtifactLocation.URIBaseID != sarifRootBaseID {
+		t.Errorf("unexpected artifact location: %+v", loc.ArtifactLocation)
+	}
+	if loc.Region.EndLine != 3 || loc.Region.EndColumn != 34 {
+		t.Errorf("unexpected region: %+v", loc.Region)
+	}
+	if res.Level != "warning" || res.PartialFingerprints[sarifFingerprintKey] != "5124b415cdde32e6" ||
+		res.Fingerprints[sarifFingerprintKey] != "5124b415cdde32e6" {
+		t.Errorf("unexpected result: %+v", res)
+	}
+
+	rule := run.Tool.Driver.Rules[res.RuleIndex]
+	if rule.ID != "arithmetic_flip" || rule.Name != "ArithmeticFlip" {
+		t.Errorf("unexpected rule: %+v", rule)
+	}
+	if !strings.Contains(rule.ShortDescription.Text, "Swaps `+` with `-`") || !strings.Contains(rule.Help.Markdown, "```go") ||
+		!strings.HasSuffix(rule.HelpURI, "arithmetic.md#arithmetic_flip") {
+		t.Errorf("rule not described from the operator docs: %+v", rule)
+	}
+}
+
+func TestSARIFReportIncludesTimeoutsAtTheirLevel(t *testing.T) {
+	mutants := ciMutants(t.TempDir())
+	timedOut := mutants[0]
+	timedOut.ID, timedOut.Status = 3, core.StatusTimeout
+	mutants = append(mutants, timedOut)
+
+	out := filepath.Join(t.TempDir(), "report.sarif")
+	if err := writeSARIFReport(mutants, ReportStats{}, "", SARIFOptions{TimeoutLevel: "note"}, out); err != nil {
+		t.Fatal(err)
+	}
+	var log sarifLog
+	readJSON(t, out, &log)
+	results := log.Runs[0].Results
+	if len(results) != 2 || len(log.Runs[0].Tool.Driver.Rules) != 1 {
+		t.Fatalf("expected survivor and timeout under one rule, got %d results", len(results))
+	}
+	if results[1].Level != "note" || results[1].Properties.Status != core.StatusTimeout ||
+		!strings.HasPrefix(results[1].Message.Text, "Mutant timed out") {
+		t.Errorf("unexpected timeout result: %+v", results[1])
+	}
+}
+
+func TestSARIFArtifactOutsideRoot(t *testing.T) {
+	loc := sarifArtifact("/elsewhere/a b.go")
+	if loc.URI != "file:///elsewhere/a%20b.go" || loc.URIBaseID != "" {
+		t.Errorf("unexpected location: %+v", loc)
+	}
+}
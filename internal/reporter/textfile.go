@@ -4,13 +4,14 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"strings"
 	"text/tabwriter"
 	"time"
 
 	testing "github.com/aclfe/gorgon/internal/core"
 )
 
-func writeTextReport(mutants []testing.Mutant, stats ReportStats, debug, showKilled, showSurvived bool, outputFile string) error {
+func writeTextReport(mutants []testing.Mutant, stats ReportStats, bd Breakdown, debug, showKilled, showSurvived bool, outputFile string) error {
 	var outWriters []io.Writer
 	if outputFile == "" {
 		outWriters = append(outWriters, os.Stdout)
@@ -49,6 +50,9 @@ func writeTextReport(mutants []testing.Mutant, stats ReportStats, debug, showKil
 	fmt.Fprintf(writer, "%.2f%%\t%d\t%d\t%d\t%d\t%d\t%d\t%d\t%d\n", stats.Score, stats.Killed, stats.Survived, stats.CompileErrors, stats.RuntimeErrors, stats.Timeout, stats.Untested, stats.Invalid, stats.Total)
 	writer.Flush()
 
+	writeWeakest(out, bd, weakestShown)
+	writeOperatorEffectiveness(out, bd.Operators)
+
 	if stats.Killed > 0 {
 		fmt.Fprintf(out, "\n%s", FormatTopKillingTests(mutants, 10))
 	}
@@ -80,9 +84,10 @@ func writeTextReport(mutants []testing.Mutant, stats ReportStats, debug, showKil
 			if mutant.Status == testing.StatusSurvived {
 				hasSurvived = true
 				col := getVisualColumn(fileCache, mutant.Site.File.Name(), mutant.Site.Line, mutant.Site.Column)
-				fmt.Fprintf(out, "- %s in %s:%d:%d (Operator: %s)\n",
+				fmt.Fprintf(out, "- %s in %s:%d:%d (Operator: %s) [%s]\n",
 					mutant.Status, mutant.Site.File.Name(), mutant.Site.Line, col,
-					mutant.Operator.Name())
+					mutant.Operator.Name(), mutant.Fingerprint)
+				writeIndented(out, mutant.Diff, "    ")
 			}
 		}
 		if !hasSurvived {
@@ -92,3 +97,37 @@ func writeTextReport(mutants []testing.Mutant, stats ReportStats, debug, showKil
 
 	return nil
 }
+
+// weakestShown is how many packages and functions the text report ranks.
+const weakestShown = 5
+
+// writeWeakest lists the lowest-scoring packages, when there is more than
+// one, and functions.
+func writeWeakest(out io.Writer, bd Breakdown, n int) {
+	section := func(title string, summaries []Summary) {
+		if len(summaries) == 0 {
+			return
+		}
+		fmt.Fprintf(out, "\n%s:\n", title)
+		w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+		for _, s := range summaries {
+			scored := s.Stats.Killed + s.Stats.Survived + s.Stats.Untested + s.Stats.Timeout
+			fmt.Fprintf(w, "  %s\t%.2f%%\t%d of %d survived\n", s.Name, s.Stats.Score, s.Stats.Survived, scored)
+		}
+		w.Flush()
+	}
+	if len(bd.Packages) > 1 {
+		section("Weakest Packages", weakest(bd.packageSummaries(), n))
+	}
+	section("Weakest Functions", weakest(bd.functionSummaries(), n))
+}
+
+// writeIndented writes each line of text to out prefixed with indent.
+func writeIndented(out io.Writer, text, indent string) {
+	if text == "" {
+		return
+	}
+	for _, line := range strings.Split(strings.TrimSuffix(text, "\n"), "\n") {
+		fmt.Fprintf(out, "%s%s\n", indent, line)
+	}
+}
@@ -7,22 +7,30 @@ import (
 	"go/ast"
 	"go/parser"
 	"go/token"
+	"io"
 	"os"
+	"os/signal"
 	"path/filepath"
+	"slices"
+	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/aclfe/gorgon/internal/cache"
 	"github.com/aclfe/gorgon/internal/cli"
 	"github.com/aclfe/gorgon/internal/core"
 	"github.com/aclfe/gorgon/internal/diff"
 	"github.com/aclfe/gorgon/internal/engine"
+	"github.com/aclfe/gorgon/internal/events"
 	"github.com/aclfe/gorgon/internal/gowork"
+	"github.com/aclfe/gorgon/internal/live"
 	"github.com/aclfe/gorgon/internal/logger"
 	"github.com/aclfe/gorgon/internal/orgpolicy"
 	"github.com/aclfe/gorgon/internal/reporter"
 	"github.com/aclfe/gorgon/internal/subconfig"
 	"github.com/aclfe/gorgon/internal/suppressions"
+	"github.com/aclfe/gorgon/internal/trace"
 	"github.com/aclfe/gorgon/internal/badge"
 	"github.com/aclfe/gorgon/pkg/config"
 	"github.com/aclfe/gorgon/pkg/mutator"
@@ -41,6 +49,7 @@ func cleanStaleTempDirs() {
 }
 
 func Run(flags *cli.Flags, cfg *config.Config, targets []string, configPath string) error {
+	start := time.Now()
 	cleanStaleTempDirs()
 	if len(targets) == 0 {
 		cli.PrintUsage()
@@ -124,8 +133,34 @@ func Run(flags *cli.Flags, cfg *config.Config, targets []string, configPath stri
 		}
 	}
 
+	// Deferred first so it runs last: the trace and event outputs are
+	// complete before the dashboard waits for Ctrl-C. It only waits for an
+	// interactive run that got as far as its report; anything else, CI
+	// jobs included, shuts it down on return.
+	var dash *live.Server
+	var dashReport bool
+	defer func() {
+		switch {
+		case dash == nil:
+		case dashReport && isTerminal(os.Stdout):
+			serveUntilInterrupt(dash)
+		default:
+			_ = dash.Close()
+		}
+	}()
+
+	stopTrace, err := recordTrace(cfg.Outputs)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return err
+	}
+	defer stopTrace()
+
 	for _, target := range targets {
-		if err := eng.Traverse(target, nil); err != nil {
+		end := trace.Begin("traverse", map[string]any{"target": target})
+		err := eng.Traverse(target, nil)
+		end()
+		if err != nil {
 			return err
 		}
 	}
@@ -154,7 +189,11 @@ func Run(flags *cli.Flags, cfg *config.Config, targets []string, configPath stri
 
 	var c *cache.Cache
 	if cfg.Cache {
-		c, err = cache.Load(baseDir)
+		backend, err := cache.NewBackend(cfg.CacheBackend, baseDir)
+		if err != nil {
+			return err
+		}
+		c, err = cache.Open(backend)
 		if err != nil {
 			return err
 		}
@@ -197,8 +236,9 @@ func Run(flags *cli.Flags, cfg *config.Config, targets []string, configPath stri
 		log.Info("Loaded sub-configs from %d directories", resolver.Entries())
 	}
 
+	var changedLines diff.FileLines
 	if cfg.Diff != "" {
-		changedLines, err := diff.Resolve(cfg.Diff)
+		changedLines, err = diff.Resolve(cfg.Diff)
 		if err != nil {
 			log.Warn("failed to resolve diff %q: %v", cfg.Diff, err)
 			return fmt.Errorf("failed to resolve diff %q: %w", cfg.Diff, err)
@@ -212,11 +252,20 @@ func Run(flags *cli.Flags, cfg *config.Config, targets []string, configPath stri
 		}
 	}
 
+	if flags.Mutant != "" {
+		sites, ops, err = selectMutant(flags.Mutant, sites, ops, allOps, projectRoot, cfg, resolver, log)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return err
+		}
+	}
+
 	if cfg.DryRun {
 		mutants := testing.GenerateMutants(sites, ops, allOps, projectRoot, cfg.DirRules, resolver, log)
+		mutants = testing.FilterSuppressedFingerprints(mutants, cfg.Suppress, resolver)
 		fmt.Printf("Total mutants: %d\n\n", len(mutants))
 		for _, m := range mutants {
-			fmt.Printf("#%d %s:%d:%d (%s)\n", m.ID, m.Site.File.Name(), m.Site.Line, m.Site.Column, m.Operator.Name())
+			fmt.Printf("#%d %s %s:%d:%d (%s)\n", m.ID, m.Fingerprint, m.Site.File.Name(), m.Site.Line, m.Site.Column, m.Operator.Name())
 		}
 		return nil
 	}
@@ -256,9 +305,34 @@ func Run(flags *cli.Flags, cfg *config.Config, targets []string, configPath stri
 		log.Debug("Auto-detected build tags from test files: %v", autoTags)
 	}
 
+	stopEvents, err := recordEvents(cfg.Outputs)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return err
+	}
+	defer stopEvents()
+
+	if cfg.LiveDashboard != "" && flags.Mutant == "" {
+		dash, err = live.Start(cfg.LiveDashboard)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "Live dashboard: %s\n", dash.URL())
+	}
+
+	events.Emit(events.Event{Type: events.RunStarted, Root: projectRoot})
 	mutants, err := testing.GenerateAndRunSchemata(ctx, sites, ops, allOps, baseDir, projectRoot, cfg.DirRules, resolver, concurrent, c, testsByPkg, testPaths, log, cfg.ProgBar, cfg.UnitTestsEnabled, cfg.ExternalSuites, cfg)
 	totalMutants := testing.GetTotalMutants()
 
+	if flags.Mutant != "" {
+		if err != nil {
+			return err
+		}
+		printMutantResult(os.Stdout, flags.Mutant, mutants)
+		return nil
+	}
+
 	if len(mutants) > 0 {
 		blOpts := reporter.BaselineOptions{
 			Save:         cfg.Baseline.Save,
@@ -266,7 +340,26 @@ func Run(flags *cli.Flags, cfg *config.Config, targets []string, configPath stri
 			Tolerance:    cfg.Baseline.Tolerance,
 			Dir:          baseDir,
 			File:         cfg.Baseline.File,
+			Mode:         string(cfg.Baseline.Mode),
 			MultiOutputs: cfg.Outputs,
+			ChangedLines: changedLines,
+			ProjectRoot:  projectRoot,
+			SARIF: reporter.SARIFOptions{
+				TimeoutLevel:  cfg.SARIF.TimeoutLevel,
+				UntestedLevel: cfg.SARIF.UntestedLevel,
+			},
+			History: reporter.HistoryOptions{
+				Record:   cfg.History.Enabled,
+				File:     cfg.History.File,
+				WallTime: time.Since(start),
+			},
+			Cache: c != nil,
+		}
+		if dash != nil {
+			blOpts.LiveReport = func(page []byte) {
+				dash.SetReport(page)
+				dashReport = true
+			}
 		}
 
 		// Extract format and output from first outputs entry for backward compatibility
@@ -282,6 +375,7 @@ func Run(flags *cli.Flags, cfg *config.Config, targets []string, configPath stri
 
 		// Always write text report to terminal exactly once (handled inside reporter.Report)
 		stats, reportErr := reporter.Report(mutants, totalMutants, cfg.Threshold, resolver, cfg.Debug, cfg.ShowKilled, cfg.ShowSurvived, output, debugFilePath, format, blOpts)
+		emitRunFinished(stats)
 		
 		// Generate badge even if report had errors (e.g., threshold failure)
 		if cfg.Badge != "" {
@@ -291,31 +385,210 @@ func Run(flags *cli.Flags, cfg *config.Config, targets []string, configPath stri
 		}
 		
 		if reportErr != nil {
-			if cfg.Cache {
-				path, pathErr := cache.Path(baseDir)
-				if pathErr == nil {
-					fmt.Printf("\nCache stored at: %s\n", path)
-				}
+			if c != nil {
+				fmt.Printf("\nCache stored at: %s\n", c.Location())
 			}
 			return reportErr
 		}
 	}
 
+	if len(mutants) == 0 {
+		emitRunFinished(reporter.ReportStats{})
+	}
+
 	if err != nil {
 		return err
 	}
 
-	if cfg.Cache {
-		path, err := cache.Path(baseDir)
-		if err == nil {
-			fmt.Printf("\nCache stored at: %s\n", path)
-		}
+	if c != nil {
+		fmt.Printf("\nCache stored at: %s\n", c.Location())
 	}
 
 	suppressions.SyncSuppressions(configPath, eng)
 	return nil
 }
 
+// recordEvents starts writing the event stream of the run to every
+// `events:` output: a file, or fd:N for a file descriptor inherited from
+// the parent process. The returned func stops recording.
+func recordEvents(outputs []string) (stop func(), err error) {
+	var stops []func()
+	stop = func() {
+		for _, s := range stops {
+			s()
+		}
+	}
+	for _, spec := range outputs {
+		parts := strings.SplitN(spec, ":", 2)
+		if len(parts) != 2 || strings.TrimSpace(parts[0]) != "events" {
+			continue
+		}
+		target := strings.TrimSpace(parts[1])
+		f, err := openEventsOutput(target)
+		if err != nil {
+			stop()
+			return nil, err
+		}
+		record := events.Record(f)
+		stops = append(stops, func() {
+			if err := record(); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: failed to write events to %s: %v\n", target, err)
+			}
+			if !isStdio(f) {
+				_ = f.Close()
+			}
+		})
+	}
+	return stop, nil
+}
+
+// recordTrace starts recording the spans of the run if there are `trace:`
+// outputs; the returned func writes them to each.
+func recordTrace(outputs []string) (stop func(), err error) {
+	var files []string
+	for _, spec := range outputs {
+		parts := strings.SplitN(spec, ":", 2)
+		if len(parts) != 2 || strings.TrimSpace(parts[0]) != "trace" {
+			continue
+		}
+		file := strings.TrimSpace(parts[1])
+		if file == "" {
+			return nil, fmt.Errorf("trace output requires a file")
+		}
+		files = append(files, file)
+	}
+	if len(files) == 0 {
+		return func() {}, nil
+	}
+	trace.Start()
+	return func() {
+		t := trace.Stop()
+		for _, file := range files {
+			if err := writeTrace(t, file); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+			}
+		}
+	}, nil
+}
+
+func writeTrace(t *trace.Trace, file string) error {
+	if dir := filepath.Dir(file); dir != "." {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return fmt.Errorf("failed to create trace output directory: %w", err)
+		}
+	}
+	f, err := os.Create(file)
+	if err != nil {
+		return fmt.Errorf("failed to create trace output: %w", err)
+	}
+	defer f.Close()
+	if err := t.Write(f); err != nil {
+		return fmt.Errorf("failed to write trace to %s: %w", file, err)
+	}
+	return nil
+}
+
+func openEventsOutput(target string) (*os.File, error) {
+	if fd, ok := strings.CutPrefix(target, "fd:"); ok {
+		n, err := strconv.Atoi(fd)
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("invalid events output %q: want a file or fd:N", target)
+		}
+		// Standard streams are shared with the rest of gorgon: wrapping them
+		// again would close them, by Close or finalizer, before the report.
+		if n <= 2 {
+			return []*os.File{os.Stdin, os.Stdout, os.Stderr}[n], nil
+		}
+		f := os.NewFile(uintptr(n), target)
+		if f == nil {
+			return nil, fmt.Errorf("invalid events output %q: bad file descriptor", target)
+		}
+		return f, nil
+	}
+	if target == "" {
+		return nil, fmt.Errorf("events output requires a file or fd:N")
+	}
+	if dir := filepath.Dir(target); dir != "." {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return nil, fmt.Errorf("failed to create events output directory: %w", err)
+		}
+	}
+	f, err := os.Create(target)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create events output: %w", err)
+	}
+	return f, nil
+}
+
+func isStdio(f *os.File) bool {
+	return f == os.Stdin || f == os.Stdout || f == os.Stderr
+}
+
+// emitRunFinished announces the outcome of the run on the event stream.
+func emitRunFinished(stats reporter.ReportStats) {
+	events.Emit(events.Event{
+		Type: events.RunFinished,
+		Stats: &events.Stats{
+			Score:    stats.Score,
+			Total:    stats.Total,
+			Killed:   stats.Killed,
+			Survived: stats.Survived,
+			Timeout:  stats.Timeout,
+			Untested: stats.Untested,
+			Errors:   stats.TotalErrors,
+			Invalid:  stats.Invalid,
+		},
+	})
+}
+
+// serveUntilInterrupt keeps the live dashboard up after the run, so the
+// report it switched to can still be read, until the user presses Ctrl-C.
+func serveUntilInterrupt(dash *live.Server) {
+	defer dash.Close()
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	fmt.Fprintf(os.Stderr, "\nThe report is on the live dashboard at %s; press Ctrl-C to stop serving it.\n", dash.URL())
+	<-ctx.Done()
+}
+
+// isTerminal reports whether f is a terminal rather than a pipe or file.
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// selectMutant narrows a run to the mutant with fingerprint: its site and
+// its operator.
+func selectMutant(fingerprint string, sites []engine.Site, ops, allOps []mutator.Operator, projectRoot string, cfg *config.Config, resolver *subconfig.Resolver, log *logger.Logger) ([]engine.Site, []mutator.Operator, error) {
+	for _, m := range testing.GenerateMutants(sites, ops, allOps, projectRoot, cfg.DirRules, resolver, log) {
+		if m.Fingerprint == fingerprint {
+			return []engine.Site{m.Site}, []mutator.Operator{m.Operator}, nil
+		}
+	}
+	return nil, nil, fmt.Errorf("no mutant with fingerprint %s (the code may have changed since it was reported)", fingerprint)
+}
+
+// printMutantResult prints the outcome of a -mutant run. The Status and
+// Killed by lines are read back by `gorgon tui`.
+func printMutantResult(w io.Writer, fingerprint string, mutants []testing.Mutant) {
+	// Other mutants may share the site, as when a sub-config adds operators
+	// there; only the requested one is reported.
+	i := slices.IndexFunc(mutants, func(m testing.Mutant) bool { return m.Fingerprint == fingerprint })
+	if i < 0 {
+		fmt.Fprintf(w, "Mutant %s was not run\n", fingerprint)
+		return
+	}
+	m := mutants[i]
+	fmt.Fprintf(w, "\nMutant %s (%s) %s:%d:%d\n", fingerprint, m.Operator.Name(), m.Site.File.Name(), m.Site.Line, m.Site.Column)
+	fmt.Fprintf(w, "Status: %s\n", m.Status)
+	if m.KilledBy != "" {
+		fmt.Fprintf(w, "Killed by: %s\n", m.KilledBy)
+	}
+	if m.ErrorReason != "" {
+		fmt.Fprintf(w, "Reason: %s\n", m.ErrorReason)
+	}
+}
+
 func FilterSites(sites []engine.Site, targets []string, cfg *config.Config, resolver *subconfig.Resolver) []engine.Site {
 	var filtered []engine.Site
 	for _, site := range sites {
@@ -280,6 +280,32 @@ func (r *Resolver) EffectiveTests(filePath string, rootTests []string) []string
 	return rootTests
 }
 
+// ConfigDir returns the directory of the most specific sub-config that
+// applies to filePath, relative to the project root, or "" when none does.
+func (r *Resolver) ConfigDir(filePath string) string {
+	absFile, err := filepath.Abs(filePath)
+	if err != nil {
+		return ""
+	}
+	fileDir := filepath.Dir(absFile)
+
+	dir := ""
+	for i := range r.entries {
+		e := &r.entries[i]
+		if fileDir == e.dir || (r.mode != config.SubConfigIsolate &&
+			strings.HasPrefix(fileDir+string(filepath.Separator), e.dir+string(filepath.Separator))) {
+			dir = e.dir
+		}
+	}
+	if dir == "" {
+		return ""
+	}
+	if rel, err := filepath.Rel(r.projectRoot, dir); err == nil {
+		return filepath.ToSlash(rel)
+	}
+	return dir
+}
+
 // HasAnyOverrides returns false when no sub-configs were discovered,
 // letting callers skip resolution overhead entirely.
 func (r *Resolver) HasAnyOverrides() bool {
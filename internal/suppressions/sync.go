@@ -50,7 +50,9 @@ func SyncSuppressions(configPath string, eng *engine.Engine) {
 	mergeInlineDirectives(existingConfigSuppress, directives, projectRoot)
 
 	
-	cfg.Suppress = buildSuppressEntries(existingConfigSuppress)
+	entries := buildSuppressEntries(existingConfigSuppress)
+	keepReasons(entries, cfg.Suppress)
+	cfg.Suppress = append(entries, fingerprintEntries(cfg.Suppress)...)
 
 	if err := cfg.Save(configPath); err != nil {
 		_, _ = fmt.Fprintf(os.Stderr, "warning: failed to save config: %v\n", err)
@@ -60,6 +62,9 @@ func SyncSuppressions(configPath string, eng *engine.Engine) {
 func buildSuppressMap(entries []config.SuppressEntry) map[string]map[string]bool {
 	result := make(map[string]map[string]bool)
 	for _, entry := range entries {
+		if entry.Location == "" {
+			continue
+		}
 		if result[entry.Location] == nil {
 			result[entry.Location] = make(map[string]bool)
 		}
@@ -128,3 +133,29 @@ func buildSuppressEntries(suppressMap map[string]map[string]bool) []config.Suppr
 	}
 	return entries
 }
+
+// keepReasons copies the reason of each location entry in old onto the
+// rebuilt entry for the same location.
+func keepReasons(entries, old []config.SuppressEntry) {
+	reasons := make(map[string]string)
+	for _, entry := range old {
+		if entry.Location != "" && entry.Reason != "" {
+			reasons[entry.Location] = entry.Reason
+		}
+	}
+	for i := range entries {
+		entries[i].Reason = reasons[entries[i].Location]
+	}
+}
+
+// fingerprintEntries returns the entries that select mutants by fingerprint;
+// inline directives never produce them, so they are carried over as is.
+func fingerprintEntries(entries []config.SuppressEntry) []config.SuppressEntry {
+	var out []config.SuppressEntry
+	for _, entry := range entries {
+		if entry.Fingerprint != "" {
+			out = append(out, entry)
+		}
+	}
+	return out
+}
@@ -0,0 +1,233 @@
+// Package trace records where the wall-clock time of a run goes: the
+// orchestration phases, each test binary build and each mutant execution,
+// as spans on lanes. It writes them in the Chrome trace-event format, which
+// Perfetto (ui.perfetto.dev) and chrome://tracing open.
+package trace
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/aclfe/gorgon/internal/events"
+)
+
+// Lanes are threads in the trace viewer. Phases of the run nest on the main
+// lane; builds run concurrently, each on the first free compile lane; each
+// test worker has its own lane.
+const (
+	mainLane    = 1
+	compileLane = 1000 // + compile lane number, from 1
+	workerLane  = 2000 // + worker number, from 1
+)
+
+// span is a finished span.
+type span struct {
+	name  string
+	cat   string
+	lane  int
+	start time.Time
+	dur   time.Duration
+	args  map[string]any
+}
+
+// recorder holds the spans of the current recording.
+type recorder struct {
+	mu        sync.Mutex
+	spans     []span
+	compiling map[string]openSpan // by package
+	compiles  map[int]bool        // compile lanes in use
+	running   map[int]openSpan    // by worker
+	cancel    func()              // ends the events subscription
+}
+
+type openSpan struct {
+	name  string
+	lane  int
+	start time.Time
+	args  map[string]any
+}
+
+var (
+	mu  sync.Mutex
+	rec *recorder
+)
+
+// Start begins recording. Spans begun before Start or after Stop are not
+// recorded.
+func Start() {
+	r := &recorder{
+		compiling: make(map[string]openSpan),
+		compiles:  make(map[int]bool),
+		running:   make(map[int]openSpan),
+	}
+	mu.Lock()
+	rec = r
+	mu.Unlock()
+	r.cancel = events.Subscribe(r.event)
+}
+
+// Stop ends the recording and returns its trace.
+func Stop() *Trace {
+	mu.Lock()
+	r := rec
+	rec = nil
+	mu.Unlock()
+	if r == nil {
+		return &Trace{}
+	}
+	r.cancel()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return &Trace{spans: r.spans}
+}
+
+// Begin starts a span of the run's phase name on the main lane; calling the
+// returned func ends it. args are shown with the span.
+func Begin(name string, args map[string]any) (end func()) {
+	mu.Lock()
+	r := rec
+	mu.Unlock()
+	if r == nil {
+		return func() {}
+	}
+	start := time.Now()
+	return func() {
+		r.add(span{name: name, cat: "phase", lane: mainLane, start: start, dur: time.Since(start), args: args})
+	}
+}
+
+func (r *recorder) add(s span) {
+	r.mu.Lock()
+	r.spans = append(r.spans, s)
+	r.mu.Unlock()
+}
+
+// event turns the compiles and mutant executions of the event stream into
+// spans.
+func (r *recorder) event(e events.Event) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	switch e.Type {
+	case events.CompileStarted:
+		lane := 1
+		for r.compiles[lane] {
+			lane++
+		}
+		r.compiles[lane] = true
+		r.compiling[e.Package] = openSpan{
+			name:  "go test -c " + e.Package,
+			lane:  lane,
+			start: e.Time,
+			args:  map[string]any{"package": e.Package},
+		}
+	case events.CompileFinished:
+		o, ok := r.compiling[e.Package]
+		if !ok {
+			return
+		}
+		delete(r.compiling, e.Package)
+		delete(r.compiles, o.lane)
+		o.args["compile_errors"] = len(e.Failures)
+		r.spans = append(r.spans, span{name: o.name, cat: "compile", lane: compileLane + o.lane, start: o.start, dur: e.Time.Sub(o.start), args: o.args})
+	case events.MutantStarted:
+		m := e.Mutant
+		r.running[e.Worker] = openSpan{
+			name:  fmt.Sprintf("#%d %s", m.ID, m.Operator),
+			lane:  e.Worker,
+			start: e.Time,
+			args: map[string]any{
+				"id":       m.ID,
+				"operator": m.Operator,
+				"site":     fmt.Sprintf("%s:%d:%d", m.File, m.Line, m.Column),
+			},
+		}
+	case events.MutantFinished:
+		if e.Worker == 0 {
+			return
+		}
+		o, ok := r.running[e.Worker]
+		if !ok || o.args["id"] != e.Mutant.ID {
+			return
+		}
+		delete(r.running, e.Worker)
+		o.args["status"] = e.Mutant.Status
+		if e.Mutant.KilledBy != "" {
+			o.args["killed_by"] = e.Mutant.KilledBy
+		}
+		r.spans = append(r.spans, span{name: o.name, cat: "mutant", lane: workerLane + o.lane, start: o.start, dur: e.Time.Sub(o.start), args: o.args})
+	}
+}
+
+// Trace is a finished recording.
+type Trace struct {
+	spans []span
+}
+
+// traceEvent is one entry of the Chrome trace-event format: a complete
+// span ("X") or thread metadata ("M"). Times are in microseconds.
+type traceEvent struct {
+	Name string         `json:"name"`
+	Cat  string         `json:"cat,omitempty"`
+	Ph   string         `json:"ph"`
+	Ts   float64        `json:"ts"`
+	Dur  float64        `json:"dur,omitempty"`
+	Pid  int            `json:"pid"`
+	Tid  int            `json:"tid"`
+	Args map[string]any `json:"args,omitempty"`
+}
+
+// Write writes t as Chrome trace-event JSON, times relative to its first
+// span.
+func (t *Trace) Write(w io.Writer) error {
+	var origin time.Time
+	for _, s := range t.spans {
+		if origin.IsZero() || s.start.Before(origin) {
+			origin = s.start
+		}
+	}
+	micros := func(d time.Duration) float64 { return float64(d) / float64(time.Microsecond) }
+
+	out := []traceEvent{{Name: "process_name", Ph: "M", Pid: 1, Args: map[string]any{"name": "gorgon"}}}
+	lanes := map[int]bool{}
+	for _, s := range t.spans {
+		lanes[s.lane] = true
+	}
+	for lane := range lanes {
+		out = append(out,
+			traceEvent{Name: "thread_name", Ph: "M", Pid: 1, Tid: lane, Args: map[string]any{"name": laneName(lane)}},
+			traceEvent{Name: "thread_sort_index", Ph: "M", Pid: 1, Tid: lane, Args: map[string]any{"sort_index": lane}},
+		)
+	}
+	for _, s := range t.spans {
+		out = append(out, traceEvent{
+			Name: s.name,
+			Cat:  s.cat,
+			Ph:   "X",
+			Ts:   micros(s.start.Sub(origin)),
+			Dur:  micros(s.dur),
+			Pid:  1,
+			Tid:  s.lane,
+			Args: s.args,
+		})
+	}
+
+	enc := json.NewEncoder(w)
+	return enc.Encode(struct {
+		TraceEvents     []traceEvent `json:"traceEvents"`
+		DisplayTimeUnit string       `json:"displayTimeUnit"`
+	}{out, "ms"})
+}
+
+func laneName(lane int) string {
+	switch {
+	case lane >= workerLane:
+		return fmt.Sprintf("worker %d", lane-workerLane)
+	case lane >= compileLane:
+		return fmt.Sprintf("compile %d", lane-compileLane)
+	default:
+		return "run"
+	}
+}
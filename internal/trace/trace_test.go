@@ -0,0 +1,76 @@
+//go:build unit
+// +build unit
+
+package trace
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/aclfe/gorgon/internal/events"
+)
+
+func TestTrace_SpansOnLanes(t *testing.T) {
+	Begin("before start", nil)()
+
+	Start()
+	end := Begin("traverse", map[string]any{"target": "./calc"})
+	end()
+
+	t0 := time.Now()
+	at := func(ms int) time.Time { return t0.Add(time.Duration(ms) * time.Millisecond) }
+	mutant := &events.Mutant{ID: 3, Operator: "arithmetic_flip", File: "calc.go", Line: 5, Column: 9}
+	// Two packages build at once, so they take two compile lanes.
+	events.Emit(events.Event{Type: events.CompileStarted, Time: at(0), Package: "a"})
+	events.Emit(events.Event{Type: events.CompileStarted, Time: at(1), Package: "b"})
+	events.Emit(events.Event{Type: events.CompileFinished, Time: at(10), Package: "a"})
+	events.Emit(events.Event{Type: events.CompileFinished, Time: at(12), Package: "b", Failures: []events.CompileFailure{{MutantID: 4}}})
+	events.Emit(events.Event{Type: events.MutantStarted, Time: at(20), Worker: 2, Mutant: mutant})
+	done := *mutant
+	done.Status = "killed"
+	events.Emit(events.Event{Type: events.MutantFinished, Time: at(25), Worker: 2, Mutant: &done})
+
+	tr := Stop()
+	Begin("after stop", nil)()
+
+	var buf bytes.Buffer
+	if err := tr.Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+	var out struct {
+		TraceEvents []traceEvent `json:"traceEvents"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &out); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, buf.String())
+	}
+
+	spans := map[string]traceEvent{}
+	threads := map[int]string{}
+	for _, e := range out.TraceEvents {
+		switch {
+		case e.Ph == "X":
+			spans[e.Name] = e
+		case e.Name == "thread_name":
+			threads[e.Tid] = e.Args["name"].(string)
+		}
+	}
+	if len(spans) != 4 {
+		t.Fatalf("spans = %v, want traverse, two compiles and a mutant", spans)
+	}
+	if s := spans["traverse"]; s.Tid != mainLane || s.Args["target"] != "./calc" {
+		t.Errorf("traverse = %+v", s)
+	}
+	a, b := spans["go test -c a"], spans["go test -c b"]
+	if a.Tid == b.Tid || threads[a.Tid] != "compile 1" || threads[b.Tid] != "compile 2" {
+		t.Errorf("compile lanes = %q, %q", threads[a.Tid], threads[b.Tid])
+	}
+	if b.Dur != 11000 || b.Args["compile_errors"] != float64(1) {
+		t.Errorf("compile b = %+v", b)
+	}
+	m := spans["#3 arithmetic_flip"]
+	if threads[m.Tid] != "worker 2" || m.Dur != 5000 || m.Args["status"] != "killed" || m.Args["site"] != "calc.go:5:9" {
+		t.Errorf("mutant span = %+v on %q", m, threads[m.Tid])
+	}
+}
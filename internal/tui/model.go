@@ -0,0 +1,450 @@
+package tui
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	testing "github.com/aclfe/gorgon/internal/core"
+	"github.com/aclfe/gorgon/internal/reporter"
+)
+
+type rowKind int
+
+const (
+	rowPackage rowKind = iota
+	rowFile
+	rowMutant
+)
+
+// row is one line of the tree: a package, a file of the package above it
+// or a mutant of the file above it.
+type row struct {
+	kind   rowKind
+	pkg    int // index into model.pkgs
+	file   int // index into the package's Files
+	mutant int // index into model.mutants
+}
+
+// model is the state of the browser. It knows nothing about the terminal:
+// keys go in through the methods below and the screen comes out of render.
+type model struct {
+	mutants  []testing.Mutant
+	root     string
+	source   string // where the results were loaded from
+	pkgs     []reporter.PackageSummary
+	byFile   map[string][]int // file path → mutant indexes, by position
+	expanded map[string]bool  // "p:" + package name or "f:" + file path
+	accepted map[int]string   // mutant index → reason it was suppressed
+	all      bool             // list every mutant, not only survivors
+	rows     []row
+	cursor   int
+	offset   int // first tree row on screen
+	message  string
+}
+
+func newModel(mutants []testing.Mutant, root, source string) *model {
+	m := &model{
+		mutants:  mutants,
+		root:     root,
+		source:   source,
+		expanded: make(map[string]bool),
+		accepted: make(map[int]string),
+	}
+	m.refresh()
+	// Open the packages that have survivors; they are what there is to
+	// triage.
+	for _, p := range m.pkgs {
+		if p.Stats.Survived > 0 {
+			m.expanded["p:"+p.Name] = true
+		}
+	}
+	m.buildRows()
+	return m
+}
+
+// refresh recomputes the scores after a mutant's status changed.
+func (m *model) refresh() {
+	m.pkgs = reporter.Aggregate(m.mutants, m.root).Packages
+	m.byFile = make(map[string][]int)
+	for i, mu := range m.mutants {
+		if mu.Site.File == nil {
+			continue
+		}
+		name := mu.Site.File.Name()
+		m.byFile[name] = append(m.byFile[name], i)
+	}
+	for _, idx := range m.byFile {
+		sort.SliceStable(idx, func(a, b int) bool {
+			x, y := m.mutants[idx[a]].Site, m.mutants[idx[b]].Site
+			if x.Line != y.Line {
+				return x.Line < y.Line
+			}
+			return x.Column < y.Column
+		})
+	}
+}
+
+// listed reports whether mutant i has a row under its file.
+func (m *model) listed(i int) bool {
+	return m.all || m.mutants[i].Status == testing.StatusSurvived
+}
+
+// buildRows flattens the expanded part of the tree, keeping the cursor on
+// the same package, file or mutant where it can, otherwise on the same line.
+func (m *model) buildRows() {
+	var keepKey string
+	if r, ok := m.current(); ok {
+		keepKey = m.rowKey(r)
+	}
+	line := m.cursor
+
+	m.rows = m.rows[:0]
+	for pi, p := range m.pkgs {
+		m.rows = append(m.rows, row{kind: rowPackage, pkg: pi})
+		if !m.expanded["p:"+p.Name] {
+			continue
+		}
+		for fi, f := range p.Files {
+			m.rows = append(m.rows, row{kind: rowFile, pkg: pi, file: fi})
+			if !m.expanded["f:"+f.Path] {
+				continue
+			}
+			for _, i := range m.byFile[f.Path] {
+				if m.listed(i) {
+					m.rows = append(m.rows, row{kind: rowMutant, pkg: pi, file: fi, mutant: i})
+				}
+			}
+		}
+	}
+
+	for i, r := range m.rows {
+		if m.rowKey(r) == keepKey {
+			m.cursor = i
+			return
+		}
+	}
+	m.cursor = 0
+	m.move(line)
+}
+
+func (m *model) rowKey(r row) string {
+	if r.pkg >= len(m.pkgs) {
+		return ""
+	}
+	p := m.pkgs[r.pkg]
+	switch r.kind {
+	case rowPackage:
+		return "p:" + p.Name
+	case rowFile:
+		if r.file < len(p.Files) {
+			return "f:" + p.Files[r.file].Path
+		}
+	case rowMutant:
+		return fmt.Sprintf("m:%d", r.mutant)
+	}
+	return ""
+}
+
+// current is the row under the cursor.
+func (m *model) current() (row, bool) {
+	if m.cursor < 0 || m.cursor >= len(m.rows) {
+		return row{}, false
+	}
+	return m.rows[m.cursor], true
+}
+
+// selected is the mutant under the cursor, if the cursor is on one.
+func (m *model) selected() (int, bool) {
+	r, ok := m.current()
+	if !ok || r.kind != rowMutant {
+		return 0, false
+	}
+	return r.mutant, true
+}
+
+func (m *model) move(delta int) {
+	m.cursor += delta
+	if m.cursor >= len(m.rows) {
+		m.cursor = len(m.rows) - 1
+	}
+	if m.cursor < 0 {
+		m.cursor = 0
+	}
+}
+
+// toggle expands or collapses the package or file under the cursor.
+func (m *model) toggle() {
+	r, ok := m.current()
+	if !ok || r.kind == rowMutant {
+		return
+	}
+	key := m.rowKey(r)
+	m.expanded[key] = !m.expanded[key]
+	m.buildRows()
+}
+
+// collapse closes the node under the cursor, or moves to its parent when
+// it is closed or a mutant.
+func (m *model) collapse() {
+	r, ok := m.current()
+	if !ok {
+		return
+	}
+	if key := m.rowKey(r); r.kind != rowMutant && m.expanded[key] {
+		m.expanded[key] = false
+		m.buildRows()
+		return
+	}
+	for i := m.cursor - 1; i >= 0; i-- {
+		if m.rows[i].kind < r.kind {
+			m.cursor = i
+			return
+		}
+	}
+}
+
+// toggleAll switches between listing survivors and every mutant.
+func (m *model) toggleAll() {
+	m.all = !m.all
+	m.buildRows()
+	if m.all {
+		m.message = "Listing every mutant"
+	} else {
+		m.message = "Listing survivors"
+	}
+}
+
+// setResult records the outcome of re-running mutant i.
+func (m *model) setResult(i int, status, killedBy string) {
+	m.mutants[i].Status = status
+	m.mutants[i].KilledBy = killedBy
+	m.refresh()
+	m.buildRows()
+}
+
+// render draws the screen: a header, the tree on the left, the detail of
+// the row under the cursor on the right and a footer of keys or the
+// current message.
+func (m *model) render(width, height int, footer string) []string {
+	if width < 20 {
+		width = 20
+	}
+	if height < 4 {
+		height = 4
+	}
+	body := height - 2
+	treeWidth := width * 2 / 5
+	if treeWidth > 60 {
+		treeWidth = 60
+	}
+	detailWidth := width - treeWidth - 3
+
+	if m.cursor < m.offset {
+		m.offset = m.cursor
+	}
+	if m.cursor >= m.offset+body {
+		m.offset = m.cursor - body + 1
+	}
+
+	detail := m.detail(detailWidth)
+	lines := make([]string, 0, height)
+	lines = append(lines, bold+fit(m.header(), width)+reset)
+	for i := 0; i < body; i++ {
+		var left string
+		if ri := m.offset + i; ri < len(m.rows) {
+			text := fit(m.rowText(m.rows[ri]), treeWidth)
+			switch {
+			case ri == m.cursor:
+				left = reverse + text + reset
+			case m.rows[ri].kind != rowMutant:
+				left = text
+			case m.accepted[m.rows[ri].mutant] != "":
+				left = yellow + text + reset
+			case m.mutants[m.rows[ri].mutant].Status == testing.StatusSurvived:
+				left = red + text + reset
+			default:
+				left = text
+			}
+		} else {
+			left = fit("", treeWidth)
+		}
+		var right string
+		if i < len(detail) {
+			right = fit(detail[i], detailWidth)
+		}
+		lines = append(lines, left+" "+dim+"│"+reset+" "+right)
+	}
+	lines = append(lines, fit(footer, width))
+	return lines
+}
+
+func (m *model) header() string {
+	s := reporter.StatsForFile(m.mutants)
+	return fmt.Sprintf("gorgon  %.1f%%  %d killed, %d survived, %d accepted  (%s)",
+		s.Score, s.Killed, s.Survived, len(m.accepted), m.source)
+}
+
+func (m *model) rowText(r row) string {
+	p := m.pkgs[r.pkg]
+	switch r.kind {
+	case rowPackage:
+		return fmt.Sprintf("%s %s  %s", arrow(m.expanded["p:"+p.Name]), p.Name, scoreText(p.Summary))
+	case rowFile:
+		f := p.Files[r.file]
+		return fmt.Sprintf("  %s %s  %s", arrow(m.expanded["f:"+f.Path]), filepath.Base(f.Name), scoreText(f.Summary))
+	}
+	mu := m.mutants[r.mutant]
+	text := fmt.Sprintf("      %d:%d %s %s", mu.Site.Line, mu.Site.Column, mu.Status, mu.Operator.Name())
+	if _, ok := m.accepted[r.mutant]; ok {
+		text += " (accepted)"
+	}
+	return text
+}
+
+func arrow(open bool) string {
+	if open {
+		return "▾"
+	}
+	return "▸"
+}
+
+func scoreText(s reporter.Summary) string {
+	if !s.Scored() {
+		return "-"
+	}
+	text := fmt.Sprintf("%.1f%%", s.Stats.Score)
+	if s.Stats.Survived > 0 {
+		text += fmt.Sprintf(" (%d survived)", s.Stats.Survived)
+	}
+	return text
+}
+
+// detail describes the row under the cursor, wrapped to width.
+func (m *model) detail(width int) []string {
+	r, ok := m.current()
+	if !ok {
+		return []string{"No mutants in the report."}
+	}
+	var lines []string
+	p := m.pkgs[r.pkg]
+	switch r.kind {
+	case rowPackage:
+		lines = append(lines, "Package "+p.Name, "")
+		lines = append(lines, statsLines(p.Stats)...)
+		lines = append(lines, "", "Files:")
+		for _, f := range p.Files {
+			lines = append(lines, fmt.Sprintf("  %s  %s", filepath.Base(f.Name), scoreText(f.Summary)))
+		}
+	case rowFile:
+		f := p.Files[r.file]
+		lines = append(lines, "File "+f.Name, "")
+		lines = append(lines, statsLines(f.Stats)...)
+		lines = append(lines, "", "Functions:")
+		for _, fn := range f.Functions {
+			lines = append(lines, fmt.Sprintf("  %s (line %d)  %s", fn.Name, fn.Line, scoreText(fn.Summary)))
+		}
+	case rowMutant:
+		lines = m.mutantLines(r.mutant)
+	}
+
+	var out []string
+	for _, l := range lines {
+		out = append(out, wrap(l, width)...)
+	}
+	return out
+}
+
+func statsLines(s reporter.ReportStats) []string {
+	return []string{
+		fmt.Sprintf("Score:    %.1f%%", s.Score),
+		fmt.Sprintf("Killed:   %d", s.Killed),
+		fmt.Sprintf("Survived: %d", s.Survived),
+		fmt.Sprintf("Timeout:  %d", s.Timeout),
+		fmt.Sprintf("Untested: %d", s.Untested),
+		fmt.Sprintf("Errors:   %d", s.TotalErrors),
+	}
+}
+
+func (m *model) mutantLines(i int) []string {
+	mu := m.mutants[i]
+	lines := []string{
+		"Mutant " + mu.Fingerprint,
+		fmt.Sprintf("%s:%d:%d", m.relPath(mu.Site.File.Name()), mu.Site.Line, mu.Site.Column),
+	}
+	if fn := mu.Site.FunctionName; fn != "" {
+		lines = append(lines, "in "+fn)
+	}
+	lines = append(lines, "",
+		"Operator:  "+mu.Operator.Name(),
+		"Status:    "+mu.Status)
+	if mu.KilledBy != "" {
+		lines = append(lines, "Killed by: "+mu.KilledBy)
+	}
+	if mu.ErrorReason != "" {
+		lines = append(lines, "Reason:    "+mu.ErrorReason)
+	}
+	if reason, ok := m.accepted[i]; ok {
+		lines = append(lines, "Accepted:  "+reason)
+	}
+	lines = append(lines, "", "Original:")
+	lines = append(lines, indent(mu.Original)...)
+	lines = append(lines, "Mutated:")
+	lines = append(lines, indent(mu.Mutated)...)
+	if mu.Diff != "" {
+		lines = append(lines, "")
+		lines = append(lines, strings.Split(strings.TrimRight(mu.Diff, "\n"), "\n")...)
+	}
+	return lines
+}
+
+func (m *model) relPath(path string) string {
+	if m.root != "" {
+		if rel, err := filepath.Rel(m.root, path); err == nil && !strings.HasPrefix(rel, "..") {
+			return filepath.ToSlash(rel)
+		}
+	}
+	return path
+}
+
+func indent(code string) []string {
+	if code == "" {
+		return []string{"  (unknown)"}
+	}
+	var out []string
+	for _, l := range strings.Split(strings.TrimRight(code, "\n"), "\n") {
+		out = append(out, "  "+l)
+	}
+	return out
+}
+
+// fit pads or cuts s to exactly width columns, counting one column per
+// rune and four per tab.
+func fit(s string, width int) string {
+	s = strings.ReplaceAll(s, "\t", "    ")
+	n := utf8.RuneCountInString(s)
+	if n <= width {
+		return s + strings.Repeat(" ", width-n)
+	}
+	if width <= 1 {
+		return string([]rune(s)[:width])
+	}
+	return string([]rune(s)[:width-1]) + "…"
+}
+
+// wrap breaks s into lines of at most width columns.
+func wrap(s string, width int) []string {
+	s = strings.ReplaceAll(s, "\t", "    ")
+	r := []rune(s)
+	if width < 1 || len(r) <= width {
+		return []string{s}
+	}
+	var out []string
+	for len(r) > width {
+		out = append(out, string(r[:width]))
+		r = r[width:]
+	}
+	return append(out, string(r))
+}
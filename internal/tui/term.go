@@ -0,0 +1,186 @@
+package tui
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Escape sequences used to draw the screen.
+const (
+	altScreenOn  = "\x1b[?1049h"
+	altScreenOff = "\x1b[?1049l"
+	cursorHide   = "\x1b[?25l"
+	cursorShow   = "\x1b[?25h"
+	clearScreen  = "\x1b[H\x1b[2J"
+	reverse      = "\x1b[7m"
+	bold         = "\x1b[1m"
+	red          = "\x1b[31m"
+	yellow       = "\x1b[33m"
+	dim          = "\x1b[2m"
+	reset        = "\x1b[0m"
+)
+
+// terminal switches the controlling terminal between raw mode, where the
+// browser reads single keys, and its original mode. It drives stty rather
+// than ioctls so that it needs no platform-specific code; gorgon tui is
+// therefore limited to terminals that have stty.
+type terminal struct {
+	in    *os.File
+	out   io.Writer
+	saved string // stty -g state to restore
+}
+
+func openTerminal(in *os.File, out io.Writer) (*terminal, error) {
+	info, err := in.Stat()
+	if err != nil || info.Mode()&os.ModeCharDevice == 0 {
+		return nil, errors.New("gorgon tui needs an interactive terminal")
+	}
+	t := &terminal{in: in, out: out}
+	saved, err := t.stty("-g")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read terminal state: %w", err)
+	}
+	t.saved = strings.TrimSpace(saved)
+	if err := t.enter(); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// enter puts the terminal in raw mode on the alternate screen.
+func (t *terminal) enter() error {
+	if _, err := t.stty("raw", "-echo"); err != nil {
+		return fmt.Errorf("failed to enter raw mode: %w", err)
+	}
+	fmt.Fprint(t.out, altScreenOn+cursorHide)
+	return nil
+}
+
+// leave restores the terminal as it was before enter, so that another
+// program (an editor, a gorgon run) can use it.
+func (t *terminal) leave() {
+	fmt.Fprint(t.out, cursorShow+altScreenOff)
+	_, _ = t.stty(t.saved)
+}
+
+// size returns the terminal's width and height, 80x24 when unknown.
+func (t *terminal) size() (width, height int) {
+	out, err := t.stty("size")
+	if err == nil {
+		if f := strings.Fields(out); len(f) == 2 {
+			h, errH := strconv.Atoi(f[0])
+			w, errW := strconv.Atoi(f[1])
+			if errH == nil && errW == nil && w > 0 && h > 0 {
+				return w, h
+			}
+		}
+	}
+	return 80, 24
+}
+
+// readKeys blocks until input arrives and decodes it.
+func (t *terminal) readKeys() ([]string, error) {
+	buf := make([]byte, 64)
+	n, err := t.in.Read(buf)
+	if err != nil {
+		return nil, err
+	}
+	return decodeKeys(buf[:n]), nil
+}
+
+// waitKey prints msg and waits for a key in raw mode, without the
+// alternate screen, so output printed before it stays visible.
+func (t *terminal) waitKey(msg string) {
+	fmt.Fprint(t.out, "\n"+msg)
+	if _, err := t.stty("raw", "-echo"); err == nil {
+		_, _ = t.readKeys()
+		_, _ = t.stty(t.saved)
+	}
+	fmt.Fprint(t.out, "\n")
+}
+
+func (t *terminal) stty(args ...string) (string, error) {
+	cmd := exec.Command("stty", args...)
+	cmd.Stdin = t.in
+	out, err := cmd.Output()
+	return string(out), err
+}
+
+// Key names for the input that is not a printable character.
+const (
+	keyUp        = "up"
+	keyDown      = "down"
+	keyLeft      = "left"
+	keyRight     = "right"
+	keyPageUp    = "pgup"
+	keyPageDown  = "pgdn"
+	keyHome      = "home"
+	keyEnd       = "end"
+	keyEnter     = "enter"
+	keyEscape    = "esc"
+	keyBackspace = "backspace"
+	keyCtrlC     = "ctrl-c"
+)
+
+var escapeKeys = map[string]string{
+	"[A": keyUp, "OA": keyUp,
+	"[B": keyDown, "OB": keyDown,
+	"[C": keyRight, "OC": keyRight,
+	"[D": keyLeft, "OD": keyLeft,
+	"[5~": keyPageUp, "[6~": keyPageDown,
+	"[H": keyHome, "OH": keyHome, "[1~": keyHome, "[7~": keyHome,
+	"[F": keyEnd, "OF": keyEnd, "[4~": keyEnd, "[8~": keyEnd,
+}
+
+// decodeKeys splits raw terminal input into keys: a key name for arrows,
+// paging, enter, escape, backspace and Ctrl-C, otherwise the character
+// itself. Unknown escape sequences are dropped.
+func decodeKeys(b []byte) []string {
+	var keys []string
+	s := string(b)
+	for len(s) > 0 {
+		switch c := s[0]; {
+		case c == 0x1b:
+			if len(s) == 1 || (s[1] != '[' && s[1] != 'O') {
+				keys = append(keys, keyEscape)
+				s = s[1:]
+				continue
+			}
+			// CSI and SS3 sequences end at the first byte in @..~ after
+			// the introducer.
+			end := 2
+			for end < len(s) && (s[end] < '@' || s[end] > '~') {
+				end++
+			}
+			if end == len(s) {
+				return keys
+			}
+			if k, ok := escapeKeys[s[1:end+1]]; ok {
+				keys = append(keys, k)
+			}
+			s = s[end+1:]
+		case c == '\r' || c == '\n':
+			keys = append(keys, keyEnter)
+			s = s[1:]
+		case c == 0x7f || c == 0x08:
+			keys = append(keys, keyBackspace)
+			s = s[1:]
+		case c == 0x03:
+			keys = append(keys, keyCtrlC)
+			s = s[1:]
+		case c < 0x20:
+			s = s[1:]
+		default:
+			r, size := utf8.DecodeRuneInString(s)
+			keys = append(keys, string(r))
+			s = s[size:]
+		}
+	}
+	return keys
+}
@@ -0,0 +1,294 @@
+// Package tui is the interactive browser of `gorgon tui`: a package tree
+// with scores, the survivors of each file and the original and mutated code
+// of each mutant, with keys to suppress a mutant, open it in $EDITOR and
+// re-run it.
+package tui
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	testing "github.com/aclfe/gorgon/internal/core"
+	"github.com/aclfe/gorgon/pkg/config"
+)
+
+// Options is what the browser shows and where its actions go.
+type Options struct {
+	Mutants    []testing.Mutant
+	Root       string   // project root the paths are shown relative to
+	Source     string   // where the mutants were loaded from, for the header
+	ConfigFile string   // config that accepted mutants are suppressed in; "" disables accepting
+	Targets    []string // paths passed to gorgon when a mutant is re-run
+}
+
+const footerKeys = "↑↓ move  ⏎ open  ← close  t all/survivors  a accept  e edit  r re-run  q quit"
+
+// Run opens the browser on the terminal and returns when the user quits,
+// with the number of mutants accepted.
+func Run(opts Options) (accepted int, err error) {
+	if len(opts.Mutants) == 0 {
+		return 0, errNoMutants
+	}
+	term, err := openTerminal(os.Stdin, os.Stdout)
+	if err != nil {
+		return 0, err
+	}
+	defer term.leave()
+
+	m := newModel(opts.Mutants, opts.Root, opts.Source)
+	var prompt *reasonPrompt
+	for {
+		footer := footerKeys
+		switch {
+		case prompt != nil:
+			footer = prompt.text()
+		case m.message != "":
+			footer = m.message
+		}
+		w, h := term.size()
+		var screen bytes.Buffer
+		screen.WriteString(clearScreen)
+		screen.WriteString(strings.Join(m.render(w, h, footer), "\r\n"))
+		if _, err := term.out.Write(screen.Bytes()); err != nil {
+			return len(m.accepted), err
+		}
+
+		keys, err := term.readKeys()
+		if err != nil {
+			return len(m.accepted), err
+		}
+		for _, k := range keys {
+			if prompt != nil {
+				done, ok := prompt.key(k)
+				if done {
+					if ok {
+						m.message = accept(m, opts.ConfigFile, prompt.mutant, prompt.reason())
+					} else {
+						m.message = "Cancelled"
+					}
+					prompt = nil
+				}
+				continue
+			}
+			m.message = ""
+			switch k {
+			case "q", keyCtrlC:
+				return len(m.accepted), nil
+			case keyUp, "k":
+				m.move(-1)
+			case keyDown, "j":
+				m.move(1)
+			case keyPageUp:
+				m.move(-(h - 2))
+			case keyPageDown:
+				m.move(h - 2)
+			case keyHome, "g":
+				m.move(-len(m.rows))
+			case keyEnd, "G":
+				m.move(len(m.rows))
+			case keyEnter, keyRight, "l", " ":
+				m.toggle()
+			case keyLeft, "h":
+				m.collapse()
+			case "t":
+				m.toggleAll()
+			case "a":
+				i, ok := m.selected()
+				switch {
+				case !ok:
+					m.message = "Select a mutant to accept"
+				case opts.ConfigFile == "":
+					m.message = "Start gorgon tui with -config to record suppressions"
+				default:
+					prompt = &reasonPrompt{mutant: i}
+				}
+			case "e":
+				if i, ok := m.selected(); ok {
+					m.message = edit(term, m.mutants[i])
+				} else {
+					m.message = "Select a mutant to open"
+				}
+			case "r":
+				if i, ok := m.selected(); ok {
+					m.message = rerun(term, m, i, opts)
+				} else {
+					m.message = "Select a mutant to re-run"
+				}
+			}
+		}
+	}
+}
+
+// reasonPrompt reads the reason a mutant is accepted on the footer line.
+type reasonPrompt struct {
+	mutant int
+	input  []rune
+}
+
+func (p *reasonPrompt) text() string {
+	return "Reason (equivalent, accepted, …; ⏎ to save, esc to cancel): " + string(p.input) + "▏"
+}
+
+func (p *reasonPrompt) reason() string {
+	return strings.TrimSpace(string(p.input))
+}
+
+// key edits the input; done is set on enter or escape, ok on enter with a
+// reason.
+func (p *reasonPrompt) key(k string) (done, ok bool) {
+	switch k {
+	case keyEnter:
+		return true, p.reason() != ""
+	case keyEscape, keyCtrlC:
+		return true, false
+	case keyBackspace:
+		if len(p.input) > 0 {
+			p.input = p.input[:len(p.input)-1]
+		}
+	default:
+		if r := []rune(k); len(r) == 1 {
+			p.input = append(p.input, r[0])
+		}
+	}
+	return false, false
+}
+
+// accept suppresses mutant i by fingerprint in the config at path and
+// returns the message to show.
+func accept(m *model, path string, i int, reason string) string {
+	mu := m.mutants[i]
+	if mu.Fingerprint == "" {
+		return "The mutant has no fingerprint and cannot be suppressed"
+	}
+	if err := suppress(path, mu.Fingerprint, reason); err != nil {
+		return err.Error()
+	}
+	m.accepted[i] = reason
+	return fmt.Sprintf("Suppressed %s in %s", mu.Fingerprint, path)
+}
+
+// suppress adds a fingerprint suppression to the config at path, creating
+// the config when it does not exist yet. An existing config is edited in
+// place rather than saved anew, which would drop its comments and the
+// settings Save does not write.
+func suppress(path, fingerprint, reason string) error {
+	if _, err := os.Stat(path); err == nil {
+		return config.SuppressFingerprintInFile(path, fingerprint, reason)
+	}
+	cfg := config.Default()
+	cfg.SuppressFingerprint(fingerprint, reason)
+	return cfg.Save(path)
+}
+
+// edit opens the mutant's file at its line in $VISUAL or $EDITOR.
+func edit(term *terminal, mu testing.Mutant) string {
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = "vi"
+	}
+	args := editorCommand(editor, mu.Site.File.Name(), mu.Site.Line, mu.Site.Column)
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+
+	term.leave()
+	err := cmd.Run()
+	if enterErr := term.enter(); enterErr != nil {
+		return enterErr.Error()
+	}
+	if err != nil {
+		return fmt.Sprintf("%s: %v", args[0], err)
+	}
+	return ""
+}
+
+// editorCommand is the command line that opens file at line in editor,
+// which may carry arguments of its own ("code -w"). Editors are told the
+// position the way each expects it; the rest get vi's +line.
+func editorCommand(editor, file string, line, col int) []string {
+	args := strings.Fields(editor)
+	switch filepath.Base(args[0]) {
+	case "code", "code-insiders", "codium", "cursor":
+		return append(args, "--goto", fmt.Sprintf("%s:%d:%d", file, line, col))
+	case "subl", "hx", "zed":
+		return append(args, fmt.Sprintf("%s:%d:%d", file, line, col))
+	}
+	return append(args, fmt.Sprintf("+%d", line), file)
+}
+
+// rerun runs gorgon again for mutant i alone, showing its output, and
+// records the new status.
+func rerun(term *terminal, m *model, i int, opts Options) string {
+	mu := m.mutants[i]
+	if mu.Fingerprint == "" {
+		return "The mutant has no fingerprint and cannot be re-run"
+	}
+	exe, err := os.Executable()
+	if err != nil {
+		return fmt.Sprintf("failed to find the gorgon binary: %v", err)
+	}
+	var out bytes.Buffer
+	cmd := exec.Command(exe, rerunArgs(opts, mu.Fingerprint)...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = io.MultiWriter(os.Stdout, &out)
+	cmd.Stderr = os.Stderr
+
+	term.leave()
+	fmt.Fprintf(os.Stdout, "Re-running mutant %s...\n", mu.Fingerprint)
+	runErr := cmd.Run()
+	term.waitKey("Press any key to return")
+	if err := term.enter(); err != nil {
+		return err.Error()
+	}
+
+	status, killedBy, ok := parseResult(out.String())
+	if !ok {
+		if runErr != nil {
+			return fmt.Sprintf("Re-run failed: %v", runErr)
+		}
+		return "Re-run did not report a status"
+	}
+	before := mu.Status
+	m.setResult(i, status, killedBy)
+	if before == status {
+		return fmt.Sprintf("Mutant %s is still %s", mu.Fingerprint, status)
+	}
+	return fmt.Sprintf("Mutant %s is now %s (was %s)", mu.Fingerprint, status, before)
+}
+
+// rerunArgs are the gorgon arguments that run one mutant. A config that
+// does not exist yet only receives suppressions and is not passed on.
+func rerunArgs(opts Options, fingerprint string) []string {
+	var args []string
+	if _, err := os.Stat(opts.ConfigFile); opts.ConfigFile != "" && err == nil {
+		args = append(args, "-config", opts.ConfigFile)
+	}
+	args = append(args, "-mutant", fingerprint)
+	return append(args, opts.Targets...)
+}
+
+// parseResult reads the Status and Killed by lines a -mutant run prints.
+func parseResult(output string) (status, killedBy string, ok bool) {
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(line, "Status: "):
+			status = strings.TrimPrefix(line, "Status: ")
+			ok = true
+		case strings.HasPrefix(line, "Killed by: "):
+			killedBy = strings.TrimPrefix(line, "Killed by: ")
+		}
+	}
+	return status, killedBy, ok
+}
+
+// errNoMutants is returned when there is nothing to browse.
+var errNoMutants = errors.New("no mutants to browse")
@@ -0,0 +1,229 @@
+//go:build unit
+// +build unit
+
+package tui
+
+import (
+	"go/token"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	core "github.com/aclfe/gorgon/internal/core"
+	"github.com/aclfe/gorgon/internal/engine"
+	"github.com/aclfe/gorgon/pkg/config"
+	"github.com/aclfe/gorgon/pkg/mutator/operators/arithmetic_flip"
+)
+
+// tuiMutants returns two survivors and a killed mutant in calc/calc.go and
+// a killed one in util/util.go.
+func tuiMutants(root string) []core.Mutant {
+	fset := token.NewFileSet()
+	calc := fset.AddFile(filepath.Join(root, "calc", "calc.go"), -1, 200)
+	util := fset.AddFile(filepath.Join(root, "util", "util.go"), -1, 200)
+	mutant := func(id int, file *token.File, line int, status string) core.Mutant {
+		return core.Mutant{
+			ID:          id,
+			Fingerprint: strings.Repeat(string(rune('a'+id)), 16),
+			Status:      status,
+			Operator:    arithmetic_flip.ArithmeticFlip{},
+			Original:    "a + b",
+			Mutated:     "a - b",
+			Site:        engine.Site{File: file, Fset: fset, Line: line, Column: 9, FunctionName: "Add"},
+		}
+	}
+	return []core.Mutant{
+		mutant(1, calc, 7, core.StatusSurvived),
+		mutant(2, calc, 3, core.StatusSurvived),
+		mutant(3, calc, 5, core.StatusKilled),
+		mutant(4, util, 2, core.StatusKilled),
+	}
+}
+
+func rowTexts(m *model) []string {
+	var out []string
+	for _, r := range m.rows {
+		out = append(out, m.rowText(r))
+	}
+	return out
+}
+
+func TestModel_TreeListsSurvivorsByPosition(t *testing.T) {
+	root := t.TempDir()
+	m := newModel(tuiMutants(root), root, "results.json")
+
+	// calc has survivors and starts open; util does not.
+	want := []string{
+		"▾ calc  33.3% (2 survived)",
+		"  ▸ calc.go  33.3% (2 survived)",
+		"▸ util  100.0%",
+	}
+	if got := rowTexts(m); !reflect.DeepEqual(got, want) {
+		t.Fatalf("rows = %q, want %q", got, want)
+	}
+
+	m.move(1)
+	m.toggle()
+	got := rowTexts(m)
+	if len(got) != 5 || !strings.HasPrefix(got[2], "      3:9 ") || !strings.HasPrefix(got[3], "      7:9 ") {
+		t.Fatalf("survivors not listed by line under the file: %q", got)
+	}
+
+	m.toggleAll()
+	if got := rowTexts(m); len(got) != 6 || !strings.Contains(got[3], "5:9 killed arithmetic_flip") {
+		t.Fatalf("all mutants not listed: %q", got)
+	}
+}
+
+func TestModel_CursorFollowsRowAcrossRebuilds(t *testing.T) {
+	root := t.TempDir()
+	m := newModel(tuiMutants(root), root, "results.json")
+	m.move(1)
+	m.toggle()
+	m.move(1) // survivor at line 3
+	i, ok := m.selected()
+	if !ok || m.mutants[i].Site.Line != 3 {
+		t.Fatalf("selected = %d, %v", i, ok)
+	}
+
+	// Killed, it leaves the survivors; the cursor stays on the same line,
+	// now the next survivor.
+	m.setResult(i, core.StatusKilled, "TestAdd")
+	if i, ok := m.selected(); !ok || m.mutants[i].Site.Line != 7 {
+		t.Errorf("selected after re-run = %d, %v", i, ok)
+	}
+	if got := m.rowText(m.rows[0]); got != "▾ calc  66.7% (1 survived)" {
+		t.Errorf("package row after re-run = %q", got)
+	}
+
+	m.collapse() // back to the file row
+	m.collapse() // close it
+	if r, _ := m.current(); r.kind != rowFile || len(m.rows) != 3 {
+		t.Errorf("cursor = %+v, rows = %q", r, rowTexts(m))
+	}
+}
+
+func TestModel_RenderShowsOriginalAndMutated(t *testing.T) {
+	root := t.TempDir()
+	m := newModel(tuiMutants(root), root, "results.json")
+	m.move(1)
+	m.toggle()
+	m.move(1)
+
+	lines := m.render(100, 20, footerKeys)
+	if len(lines) != 20 {
+		t.Fatalf("render returned %d lines, want 20", len(lines))
+	}
+	screen := strings.Join(lines, "\n")
+	for _, want := range []string{"Mutant cccccccccccccccc", "calc/calc.go:3:9", "in Add", "Status:    survived", "  a + b", "  a - b"} {
+		if !strings.Contains(screen, want) {
+			t.Errorf("screen lacks %q:\n%s", want, screen)
+		}
+	}
+}
+
+func TestDecodeKeys(t *testing.T) {
+	got := decodeKeys([]byte("j\x1b[A\x1b[6~\r\x7f\x1bé\x03\x1b[99X"))
+	want := []string{"j", keyUp, keyPageDown, keyEnter, keyBackspace, keyEscape, "é", keyCtrlC}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("decodeKeys = %q, want %q", got, want)
+	}
+}
+
+func TestEditorCommand(t *testing.T) {
+	tests := []struct {
+		editor string
+		want   []string
+	}{
+		{"vim", []string{"vim", "+12", "a.go"}},
+		{"emacs -nw", []string{"emacs", "-nw", "+12", "a.go"}},
+		{"/usr/bin/code -w", []string{"/usr/bin/code", "-w", "--goto", "a.go:12:4"}},
+		{"hx", []string{"hx", "a.go:12:4"}},
+	}
+	for _, tt := range tests {
+		if got := editorCommand(tt.editor, "a.go", 12, 4); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("editorCommand(%q) = %q, want %q", tt.editor, got, tt.want)
+		}
+	}
+}
+
+func TestRerunArgsAndResult(t *testing.T) {
+	dir := t.TempDir()
+	cfg := filepath.Join(dir, "gorgon.yml")
+	opts := Options{ConfigFile: cfg, Targets: []string{"./calc"}}
+	if got, want := rerunArgs(opts, "fp"), []string{"-mutant", "fp", "./calc"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("rerunArgs without a config file = %q, want %q", got, want)
+	}
+	if err := os.WriteFile(cfg, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := rerunArgs(opts, "fp"), []string{"-config", cfg, "-mutant", "fp", "./calc"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("rerunArgs = %q, want %q", got, want)
+	}
+
+	status, killedBy, ok := parseResult("Scanning...\n\nMutant fp (arithmetic_flip) calc.go:3:9\nStatus: killed\nKilled by: TestAdd\n")
+	if !ok || status != core.StatusKilled || killedBy != "TestAdd" {
+		t.Errorf("parseResult = %q, %q, %v", status, killedBy, ok)
+	}
+	if _, _, ok := parseResult("failed to load config\n"); ok {
+		t.Error("parseResult found a status in output without one")
+	}
+}
+
+func TestAccept_SuppressesWithReason(t *testing.T) {
+	root := t.TempDir()
+	path := filepath.Join(root, "gorgon.yml")
+	m := newModel(tuiMutants(root), root, "results.json")
+
+	if msg := accept(m, path, 0, "equivalent"); !strings.HasPrefix(msg, "Suppressed bbbbbbbbbbbbbbbb") {
+		t.Fatalf("accept = %q", msg)
+	}
+	accept(m, path, 1, "accepted")
+	accept(m, path, 0, `equivalent: "+0" is a no-op`)
+
+	cfg, err := config.Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []config.SuppressEntry{
+		{Fingerprint: "bbbbbbbbbbbbbbbb", Reason: `equivalent: "+0" is a no-op`},
+		{Fingerprint: "cccccccccccccccc", Reason: "accepted"},
+	}
+	if !reflect.DeepEqual(cfg.Suppress, want) {
+		t.Errorf("suppress = %+v, want %+v", cfg.Suppress, want)
+	}
+	if got := m.rowText(row{kind: rowMutant, mutant: 1}); !strings.HasSuffix(got, "(accepted)") {
+		t.Errorf("accepted mutant row = %q", got)
+	}
+}
+
+func TestAccept_KeepsTheRestOfTheConfig(t *testing.T) {
+	root := t.TempDir()
+	path := filepath.Join(root, "gorgon.yml")
+	original := "# Project settings\nthreshold: 80 # enforced in CI\nbuild_tags:\n    - integration\nsub_config_mode: merge\nsuppress: []\n"
+	if err := os.WriteFile(path, []byte(original), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	m := newModel(tuiMutants(root), root, "results.json")
+	accept(m, path, 0, "equivalent")
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range []string{"# Project settings", "# enforced in CI", "build_tags:\n    - integration", "sub_config_mode: merge"} {
+		if !strings.Contains(string(data), s) {
+			t.Errorf("config lost %q:\n%s", s, data)
+		}
+	}
+	cfg, err := config.Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []config.SuppressEntry{{Fingerprint: "bbbbbbbbbbbbbbbb", Reason: "equivalent"}}
+	if !reflect.DeepEqual(cfg.Suppress, want) || cfg.Threshold != 80 {
+		t.Errorf("suppress = %+v, threshold = %v", cfg.Suppress, cfg.Threshold)
+	}
+}
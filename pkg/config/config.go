@@ -8,9 +8,14 @@ import (
 	"gopkg.in/yaml.v3"
 )
 
+// SuppressEntry excludes mutants either by location ("file:line", narrowed
+// by Operators) or by Fingerprint, which names one mutant stably across runs.
+// Reason records why, e.g. that the mutant is equivalent.
 type SuppressEntry struct {
-	Location  string   `yaml:"location"`
-	Operators []string `yaml:"operators,omitempty"`
+	Location    string   `yaml:"location,omitempty"`
+	Operators   []string `yaml:"operators,omitempty"`
+	Fingerprint string   `yaml:"fingerprint,omitempty"`
+	Reason      string   `yaml:"reason,omitempty"`
 }
 
 type DirOperatorRule struct {
@@ -37,12 +42,34 @@ type ExternalSuitesConfig struct {
 }
 
 type BaselineConfig struct {
-	NoRegression bool    `yaml:"no_regression"`
-	File         string  `yaml:"file,omitempty"`
-	Tolerance    float64 `yaml:"tolerance,omitempty"`
-	Save         bool    `yaml:"save,omitempty"` // Always save baseline after run
+	NoRegression bool         `yaml:"no_regression"`
+	File         string       `yaml:"file,omitempty"`
+	Tolerance    float64      `yaml:"tolerance,omitempty"`
+	Save         bool         `yaml:"save,omitempty"` // Always save baseline after run
+	Mode         BaselineMode `yaml:"mode,omitempty"`
 }
 
+// SARIFConfig adds timed-out and untested mutants to SARIF output, each at
+// its own result level. Survivors are always reported, as warnings.
+type SARIFConfig struct {
+	TimeoutLevel  string `yaml:"timeout_level,omitempty"`  // "", "none", "note", "warning" or "error"; "" leaves them out
+	UntestedLevel string `yaml:"untested_level,omitempty"` // as TimeoutLevel
+}
+
+// HistoryConfig controls the run history the HTML report charts trends
+// from. Every run appends a record unless Enabled is false.
+type HistoryConfig struct {
+	Enabled bool   `yaml:"enabled"`
+	File    string `yaml:"file,omitempty"` // default .gorgon-history.jsonl, next to the baseline
+}
+
+type BaselineMode string
+
+const (
+	BaselineScore   BaselineMode = "score"   // Fail when the score drops below the saved score (default)
+	BaselineMutants BaselineMode = "mutants" // Fail when a survivor is missing from the saved survivor list
+)
+
 type SubConfigMode string
 
 const (
@@ -59,17 +86,37 @@ const (
 	ViolationSilent ViolationMode = "silent" // Apply constraints silently
 )
 
+// WorkspaceMode selects how the schemata-transformed module is built.
+type WorkspaceMode string
+
+const (
+	WorkspaceCopy    WorkspaceMode = "copy"    // Copy the module into a temp dir and build there (default)
+	WorkspaceOverlay WorkspaceMode = "overlay" // Write only transformed files; build the module in place with -overlay
+)
+
+// CacheInvalidation selects which cached results survive a change to tests.
+type CacheInvalidation string
+
+const (
+	CacheInvalidationStrict    CacheInvalidation = "strict"    // Any test change reruns every mutant of the package (default)
+	CacheInvalidationSelective CacheInvalidation = "selective" // Test-only changes rerun survivors; kills are kept
+)
+
 type Config struct {
 	Operators         []string          `yaml:"operators"`
 	Concurrent        string            `yaml:"concurrent"`
 	Threshold         float64           `yaml:"threshold"`
 	Cache             bool              `yaml:"cache"`
+	CacheInvalidation CacheInvalidation `yaml:"cache_invalidation,omitempty"` // "strict" (default) or "selective"
+	CacheBackend      string            `yaml:"cache_backend,omitempty"`      // "" (local), shared directory path, or http(s):// cache server URL
 	DryRun            bool              `yaml:"dry_run"`
 	Debug             bool              `yaml:"debug"`
 	ProgBar           bool              `yaml:"progbar"`
 	ShowKilled        bool              `yaml:"show_killed"`
 	ShowSurvived      bool              `yaml:"show_survived"`
 	Outputs           []string          `yaml:"outputs,omitempty"` // format:file pairs, e.g. ["junit:report.xml", "html:report"]
+	SARIF             SARIFConfig       `yaml:"sarif,omitempty"`
+	LiveDashboard     string            `yaml:"live_dashboard,omitempty"` // Address to serve the live dashboard on during the run (e.g. "localhost:8080")
 	CPUProfile        string            `yaml:"cpu_profile"`
 	MemProfile        string            `yaml:"mem_profile"` // Write periodic heap profiles to this directory (e.g. "profiles")
 	Exclude           []string          `yaml:"exclude"`
@@ -84,6 +131,7 @@ type Config struct {
 	UnitTestsEnabled  bool                 `yaml:"unit_tests_enabled"`
 	ExternalSuites    ExternalSuitesConfig `yaml:"external_suites"`
 	Baseline          BaselineConfig       `yaml:"baseline,omitempty"`
+	History           HistoryConfig        `yaml:"history"`
 	GoVersion         string               `yaml:"go_version,omitempty"` // Override detected Go version (e.g., "1.25")
 	SubConfigMode     SubConfigMode        `yaml:"sub_config_mode,omitempty"`
 	ThresholdInherit  bool                 `yaml:"threshold_inherit,omitempty"`
@@ -91,6 +139,9 @@ type Config struct {
 	Badge             string               `yaml:"badge,omitempty"` // "json" or "svg" - generates badge file
 	ChunkLargeFiles   bool                 `yaml:"chunk_large_files,omitempty"` // Split files with many mutants to reduce memory (default: true)
 	BuildTags         []string             `yaml:"build_tags,omitempty"`        // Build tags passed to `go test -c` (e.g. ["unit"])
+	Workspace         WorkspaceMode        `yaml:"workspace,omitempty"`         // "copy" (default) or "overlay"
+	BinaryCache       bool                 `yaml:"binary_cache,omitempty"`        // Reuse compiled test binaries across runs (~/.cache/gorgon/bin)
+	BinaryCacheMaxMB  int64                `yaml:"binary_cache_max_mb,omitempty"` // Size bound for the binary cache; LRU-evicted (default 2048)
 }
 
 func Default() *Config {
@@ -114,6 +165,7 @@ func Default() *Config {
 			Suites:  []ExternalSuite{},
 		},
 		Baseline: BaselineConfig{},
+		History:  HistoryConfig{Enabled: true},
 		ChunkLargeFiles: true, // Default to chunking for memory safety
 		Outputs:  []string{},
 	}
@@ -160,6 +212,31 @@ func (c *Config) Validate() error {
 	if c.Concurrent == "" {
 		c.Concurrent = "all"
 	}
+	if c.BinaryCacheMaxMB < 0 {
+		return fmt.Errorf("binary_cache_max_mb must not be negative")
+	}
+	switch c.CacheInvalidation {
+	case "", CacheInvalidationStrict, CacheInvalidationSelective:
+	default:
+		return fmt.Errorf("invalid cache_invalidation %q (use %q or %q)", c.CacheInvalidation, CacheInvalidationStrict, CacheInvalidationSelective)
+	}
+	switch c.Baseline.Mode {
+	case "", BaselineScore, BaselineMutants:
+	default:
+		return fmt.Errorf("invalid baseline.mode %q (use %q or %q)", c.Baseline.Mode, BaselineScore, BaselineMutants)
+	}
+	for key, level := range map[string]string{"sarif.timeout_level": c.SARIF.TimeoutLevel, "sarif.untested_level": c.SARIF.UntestedLevel} {
+		switch level {
+		case "", "none", "note", "warning", "error":
+		default:
+			return fmt.Errorf("invalid %s %q (use none, note, warning or error)", key, level)
+		}
+	}
+	switch c.Workspace {
+	case "", WorkspaceCopy, WorkspaceOverlay:
+	default:
+		return fmt.Errorf("invalid workspace %q (use %q or %q)", c.Workspace, WorkspaceCopy, WorkspaceOverlay)
+	}
 	return nil
 }
 
@@ -200,6 +277,114 @@ func (c *Config) AddSuppression(location string, operators []string) {
 	})
 }
 
+// SuppressFingerprint suppresses the mutant with fingerprint, replacing the
+// reason of an existing entry for it.
+func (c *Config) SuppressFingerprint(fingerprint, reason string) {
+	fingerprint = strings.TrimSpace(fingerprint)
+	if fingerprint == "" {
+		return
+	}
+	reason = strings.TrimSpace(reason)
+	for i := range c.Suppress {
+		if c.Suppress[i].Fingerprint == fingerprint {
+			c.Suppress[i].Reason = reason
+			return
+		}
+	}
+	c.Suppress = append(c.Suppress, SuppressEntry{
+		Fingerprint: fingerprint,
+		Reason:      reason,
+	})
+}
+
+// SuppressFingerprintInFile suppresses the mutant with fingerprint in the
+// config file at path, as SuppressFingerprint does. It edits the suppress
+// list in place, so comments and every other setting are kept as written.
+func SuppressFingerprintInFile(path, fingerprint, reason string) error {
+	fingerprint = strings.TrimSpace(fingerprint)
+	if fingerprint == "" {
+		return nil
+	}
+	reason = strings.TrimSpace(reason)
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return fmt.Errorf("failed to parse config file: %w", err)
+	}
+	if len(doc.Content) == 0 {
+		doc = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode, Tag: "!!map"}}}
+	}
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return fmt.Errorf("failed to parse config file: not a mapping")
+	}
+
+	var list *yaml.Node
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		if root.Content[i].Value == "suppress" {
+			list = root.Content[i+1]
+			break
+		}
+	}
+	if list == nil {
+		list = &yaml.Node{}
+		root.Content = append(root.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: "suppress"}, list)
+	}
+	if list.Kind != yaml.SequenceNode {
+		// "suppress:" with no entries, or null.
+		*list = yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq", HeadComment: list.HeadComment, LineComment: list.LineComment}
+	}
+	// An empty flow list ("[]") would keep its style and stay on one line.
+	list.Style &^= yaml.FlowStyle
+
+	scalar := func(v string) *yaml.Node { return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: v} }
+	entry := func() *yaml.Node {
+		for _, item := range list.Content {
+			if item.Kind != yaml.MappingNode {
+				continue
+			}
+			for i := 0; i+1 < len(item.Content); i += 2 {
+				if item.Content[i].Value == "fingerprint" && strings.TrimSpace(item.Content[i+1].Value) == fingerprint {
+					return item
+				}
+			}
+		}
+		item := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map", Content: []*yaml.Node{scalar("fingerprint"), scalar(fingerprint)}}
+		list.Content = append(list.Content, item)
+		return item
+	}()
+
+	// Replace the reason, as SuppressFingerprint does.
+	kept := entry.Content[:0]
+	for i := 0; i+1 < len(entry.Content); i += 2 {
+		if entry.Content[i].Value != "reason" {
+			kept = append(kept, entry.Content[i], entry.Content[i+1])
+		}
+	}
+	entry.Content = kept
+	if reason != "" {
+		entry.Content = append(entry.Content, scalar("reason"), scalar(reason))
+	}
+
+	var buf strings.Builder
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(4)
+	if err := enc.Encode(&doc); err != nil {
+		return fmt.Errorf("failed to write config file: %w", err)
+	}
+	if err := enc.Close(); err != nil {
+		return fmt.Errorf("failed to write config file: %w", err)
+	}
+	if err := os.WriteFile(path, []byte(buf.String()), 0o644); err != nil {
+		return fmt.Errorf("failed to write config file: %w", err)
+	}
+	return nil
+}
+
 func (c *Config) Save(path string) error {
 	// Create organized YAML with comments and proper structure
 	var lines []string
@@ -215,8 +400,23 @@ func (c *Config) Save(path string) error {
 	lines = append(lines, "# === Execution Settings ===")
 	lines = append(lines, fmt.Sprintf("concurrent: %s", c.Concurrent))
 	lines = append(lines, fmt.Sprintf("cache: %t", c.Cache))
+	if c.CacheInvalidation != "" {
+		lines = append(lines, fmt.Sprintf("cache_invalidation: %s", c.CacheInvalidation))
+	}
+	if c.CacheBackend != "" {
+		lines = append(lines, fmt.Sprintf("cache_backend: %q", c.CacheBackend))
+	}
 	lines = append(lines, fmt.Sprintf("dry_run: %t", c.DryRun))
 	lines = append(lines, fmt.Sprintf("progbar: %t", c.ProgBar))
+	if c.Workspace != "" {
+		lines = append(lines, fmt.Sprintf("workspace: %s", c.Workspace))
+	}
+	if c.BinaryCache {
+		lines = append(lines, "binary_cache: true")
+		if c.BinaryCacheMaxMB > 0 {
+			lines = append(lines, fmt.Sprintf("binary_cache_max_mb: %d", c.BinaryCacheMaxMB))
+		}
+	}
 	lines = append(lines, "")
 	
 	lines = append(lines, "# === Test Configuration ===")
@@ -306,6 +506,17 @@ func (c *Config) Save(path string) error {
 	if c.Baseline.File != "" {
 		lines = append(lines, fmt.Sprintf("    file: \"%s\"", c.Baseline.File))
 	}
+	if c.Baseline.Mode != "" {
+		lines = append(lines, fmt.Sprintf("    mode: %s", c.Baseline.Mode))
+	}
+	lines = append(lines, "")
+
+	lines = append(lines, "# === Run History ===")
+	lines = append(lines, "history:")
+	lines = append(lines, fmt.Sprintf("    enabled: %t", c.History.Enabled))
+	if c.History.File != "" {
+		lines = append(lines, fmt.Sprintf("    file: \"%s\"", c.History.File))
+	}
 	lines = append(lines, "")
 	
 	lines = append(lines, "# === Output Settings ===")
@@ -319,6 +530,18 @@ func (c *Config) Save(path string) error {
 			lines = append(lines, fmt.Sprintf("    - %s", out))
 		}
 	}
+	if c.SARIF.TimeoutLevel != "" || c.SARIF.UntestedLevel != "" {
+		lines = append(lines, "sarif:")
+		if c.SARIF.TimeoutLevel != "" {
+			lines = append(lines, fmt.Sprintf("    timeout_level: %s", c.SARIF.TimeoutLevel))
+		}
+		if c.SARIF.UntestedLevel != "" {
+			lines = append(lines, fmt.Sprintf("    untested_level: %s", c.SARIF.UntestedLevel))
+		}
+	}
+	if c.LiveDashboard != "" {
+		lines = append(lines, fmt.Sprintf("live_dashboard: \"%s\"", c.LiveDashboard))
+	}
 	lines = append(lines, fmt.Sprintf("cpu_profile: \"%s\"", c.CPUProfile))
 	lines = append(lines, fmt.Sprintf("mem_profile: \"%s\"", c.MemProfile))
 	lines = append(lines, "")
@@ -351,13 +574,20 @@ func (c *Config) Save(path string) error {
 		lines = append(lines, "    []")
 	} else {
 		for _, sup := range c.Suppress {
-			lines = append(lines, fmt.Sprintf("    - location: %s", sup.Location))
-			if len(sup.Operators) > 0 {
-				lines = append(lines, "      operators:")
-				for _, op := range sup.Operators {
-					lines = append(lines, fmt.Sprintf("        - %s", op))
+			if sup.Fingerprint != "" {
+				lines = append(lines, fmt.Sprintf("    - fingerprint: %s", sup.Fingerprint))
+			} else {
+				lines = append(lines, fmt.Sprintf("    - location: %s", sup.Location))
+				if len(sup.Operators) > 0 {
+					lines = append(lines, "      operators:")
+					for _, op := range sup.Operators {
+						lines = append(lines, fmt.Sprintf("        - %s", op))
+					}
 				}
 			}
+			if sup.Reason != "" {
+				lines = append(lines, fmt.Sprintf("      reason: %q", sup.Reason))
+			}
 		}
 	}
 	
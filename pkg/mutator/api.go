@@ -262,6 +262,19 @@ func GetCategory(name string) ([]Operator, bool) {
 	return result, true
 }
 
+// CategoryOf returns the category an operator belongs to, or "" if it has
+// none.
+func CategoryOf(name string) string {
+	for cat, names := range categoryMap {
+		for _, n := range names {
+			if n == name {
+				return cat
+			}
+		}
+	}
+	return ""
+}
+
 func ListCategories() []string {
 	cats := make([]string, 0, len(categoryMap))
 	for k := range categoryMap {
@@ -245,17 +245,21 @@ func extractStatsFromSARIF(path string) (reporter.ReportStats, error) {
 
 	var report struct {
 		Runs []struct {
-			Properties reporter.ReportStats `json:"properties"`
+			Invocations []struct {
+				Properties struct {
+					Stats reporter.ReportStats `json:"stats"`
+				} `json:"properties"`
+			} `json:"invocations"`
 		} `json:"runs"`
 	}
 	if err := json.Unmarshal(data, &report); err != nil {
 		return stats, fmt.Errorf("unmarshal sarif json: %w", err)
 	}
 
-	if len(report.Runs) == 0 {
-		return stats, fmt.Errorf("no runs found in sarif")
+	if len(report.Runs) == 0 || len(report.Runs[0].Invocations) == 0 {
+		return stats, fmt.Errorf("no invocations found in sarif")
 	}
-	return report.Runs[0].Properties, nil
+	return report.Runs[0].Invocations[0].Properties.Stats, nil
 }
 
 
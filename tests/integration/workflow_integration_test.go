@@ -2196,7 +2196,7 @@ func TestWorkflow_Output_JUnit_ValidXML(t *testing.T) {
 }
 
 // TestWorkflow_Output_SARIF_ValidJSON verifies SARIF output follows the
-// SARIF specification (version, runs, results, properties).
+// SARIF specification (version, runs, results, invocation stats).
 func TestWorkflow_Output_SARIF_ValidJSON(t *testing.T) {
 	repoRoot := findRepoRoot(t)
 	targetDir := filepath.Join(repoRoot, "pkg/mutator/operators/negate_condition")